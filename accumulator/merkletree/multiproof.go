@@ -0,0 +1,141 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+)
+
+var (
+	errEmptyTree      = errors.New("the tree has no leaves")
+	errInvalidIndices = errors.New("proof indices must be increasing and smaller than the number of leaves")
+)
+
+// BuildMultiProof returns the Merkle root of the tree whose leaves are the
+// given data, and a proof that the leaves at proofIndices are in the tree.
+//
+// The tree is the same as the one built by Push (see Tree). proofIndices must
+// be strictly increasing. The proof set only contains the roots of the
+// maximal subtrees containing none of the proven leaves, in depth first
+// order, so the nodes shared by several Merkle paths appear once, and the
+// nodes that can be computed from the proven leaves don't appear at all.
+func BuildMultiProof(h hash.Hash, leaves [][]byte, proofIndices []uint64) (merkleRoot []byte, proofSet [][]byte, err error) {
+	if len(leaves) == 0 {
+		return nil, nil, errEmptyTree
+	}
+	if !validIndices(proofIndices, uint64(len(leaves))) {
+		return nil, nil, errInvalidIndices
+	}
+
+	// build returns the root of the subtree of leaves [start, end), and
+	// appends to proofSet the roots of its children containing none of the
+	// indices, in the order in which VerifyMultiProof reads them.
+	var build func(start, end uint64, indices []uint64) []byte
+	build = func(start, end uint64, indices []uint64) []byte {
+		if end-start == 1 {
+			return leafSum(h, leaves[start])
+		}
+		mid := start + splitPoint(end-start)
+		left, right := splitIndices(indices, mid)
+		l := build(start, mid, left)
+		if len(left) == 0 && len(right) != 0 {
+			proofSet = append(proofSet, l)
+		}
+		r := build(mid, end, right)
+		if len(right) == 0 && len(left) != 0 {
+			proofSet = append(proofSet, r)
+		}
+		return nodeSum(h, l, r)
+	}
+	merkleRoot = build(0, uint64(len(leaves)), proofIndices)
+
+	return merkleRoot, proofSet, nil
+}
+
+// VerifyMultiProof returns true if leaves are the data of the leaves at
+// proofIndices in the Merkle tree of root merkleRoot with numLeaves leaves.
+// proofSet is the proof returned by BuildMultiProof, and proofIndices must be
+// strictly increasing.
+func VerifyMultiProof(h hash.Hash, merkleRoot []byte, leaves [][]byte, proofSet [][]byte, proofIndices []uint64, numLeaves uint64) bool {
+	if merkleRoot == nil || len(leaves) == 0 || len(leaves) != len(proofIndices) {
+		return false
+	}
+	if !validIndices(proofIndices, numLeaves) {
+		return false
+	}
+
+	nextLeaf := 0
+	var verify func(start, end uint64, indices []uint64) []byte
+	verify = func(start, end uint64, indices []uint64) []byte {
+		if len(indices) == 0 {
+			if len(proofSet) == 0 {
+				return nil
+			}
+			s := proofSet[0]
+			proofSet = proofSet[1:]
+			return s
+		}
+		if end-start == 1 {
+			l := leafSum(h, leaves[nextLeaf])
+			nextLeaf++
+			return l
+		}
+		mid := start + splitPoint(end-start)
+		left, right := splitIndices(indices, mid)
+		l := verify(start, mid, left)
+		r := verify(mid, end, right)
+		if l == nil || r == nil {
+			return nil
+		}
+		return nodeSum(h, l, r)
+	}
+
+	root := verify(0, numLeaves, proofIndices)
+	return root != nil && len(proofSet) == 0 && bytes.Equal(root, merkleRoot)
+}
+
+// splitPoint returns the largest power of 2 smaller than n (n > 1), which is
+// the number of leaves of the left subtree of a tree with n leaves.
+func splitPoint(n uint64) uint64 {
+	k := uint64(1)
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// splitIndices splits the sorted indices in those smaller than mid and the
+// others.
+func splitIndices(indices []uint64, mid uint64) (left, right []uint64) {
+	i := 0
+	for i < len(indices) && indices[i] < mid {
+		i++
+	}
+	return indices[:i], indices[i:]
+}
+
+func validIndices(indices []uint64, numLeaves uint64) bool {
+	if len(indices) == 0 {
+		return false
+	}
+	for i := range indices {
+		if indices[i] >= numLeaves || (i > 0 && indices[i] <= indices[i-1]) {
+			return false
+		}
+	}
+	return true
+}
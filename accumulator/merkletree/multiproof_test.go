@@ -0,0 +1,102 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiProof(t *testing.T) {
+	assert := require.New(t)
+	h := sha256.New()
+
+	for _, numLeaves := range []uint64{1, 2, 5, 8, 13} {
+		leaves := make([][]byte, numLeaves)
+		tree := New(h)
+		for i := range leaves {
+			leaves[i] = []byte(fmt.Sprintf("leaf %d", i))
+			tree.Push(leaves[i])
+		}
+		root := tree.Root()
+
+		// all the subsets of at most 3 leaves
+		var subsets [][]uint64
+		for i := uint64(0); i < numLeaves; i++ {
+			subsets = append(subsets, []uint64{i})
+			for j := i + 1; j < numLeaves; j++ {
+				subsets = append(subsets, []uint64{i, j})
+				for k := j + 1; k < numLeaves; k++ {
+					subsets = append(subsets, []uint64{i, j, k})
+				}
+			}
+		}
+
+		for _, indices := range subsets {
+			proven := make([][]byte, len(indices))
+			for i, index := range indices {
+				proven[i] = leaves[index]
+			}
+			merkleRoot, proofSet, err := BuildMultiProof(h, leaves, indices)
+			assert.NoError(err)
+			assert.Equal(root, merkleRoot)
+			assert.True(VerifyMultiProof(h, root, proven, proofSet, indices, numLeaves), "indices %v, %d leaves", indices, numLeaves)
+
+			// a single leaf proof is a regular Merkle proof
+			if len(indices) == 1 {
+				single := New(h)
+				assert.NoError(single.SetIndex(indices[0]))
+				for _, l := range leaves {
+					single.Push(l)
+				}
+				_, singleProof, _, _ := single.Prove()
+				assert.Equal(len(singleProof)-1, len(proofSet))
+				assert.True(VerifyProof(h, root, singleProof, indices[0], numLeaves))
+			}
+
+			// wrong leaf
+			proven[0] = []byte("wrong")
+			assert.False(VerifyMultiProof(h, root, proven, proofSet, indices, numLeaves))
+			proven[0] = leaves[indices[0]]
+
+			// wrong, missing or extra nodes
+			if len(proofSet) > 0 {
+				proofSet[0] = h.Sum([]byte("wrong"))
+				assert.False(VerifyMultiProof(h, root, proven, proofSet, indices, numLeaves))
+				assert.False(VerifyMultiProof(h, root, proven, proofSet[1:], indices, numLeaves))
+			}
+			assert.False(VerifyMultiProof(h, root, proven, append(proofSet, root), indices, numLeaves))
+		}
+	}
+
+	// the proof of two sibling leaves doesn't contain their parent
+	leaves := [][]byte{{0}, {1}, {2}, {3}, {4}, {5}, {6}, {7}}
+	_, proofSet, err := BuildMultiProof(h, leaves, []uint64{4, 5})
+	assert.NoError(err)
+	assert.Len(proofSet, 2)
+
+	// invalid indices
+	_, _, err = BuildMultiProof(h, leaves, []uint64{5, 4})
+	assert.Error(err)
+	_, _, err = BuildMultiProof(h, leaves, []uint64{8})
+	assert.Error(err)
+	_, _, err = BuildMultiProof(h, leaves, nil)
+	assert.Error(err)
+	_, _, err = BuildMultiProof(h, nil, []uint64{0})
+	assert.Error(err)
+}
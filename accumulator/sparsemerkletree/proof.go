@@ -0,0 +1,220 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparsemerkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"sort"
+)
+
+var errInvalidProof = errors.New("invalid proof")
+
+// Proof is a membership proof of (Key, Value) if Value is not nil, and a
+// non-membership proof of Key otherwise.
+type Proof struct {
+	Key      []byte
+	Value    []byte   // nil if the leaf of Key is empty
+	Siblings [][]byte // siblings of the path from the leaf to the root, bottom up
+}
+
+// BatchProof proves the values of several leaves at once. The siblings shared
+// by several paths, or computable from the leaves, are not repeated.
+type BatchProof struct {
+	Keys     [][]byte
+	Values   [][]byte // Values[i] is nil if the leaf of Keys[i] is empty
+	Siblings [][]byte // siblings not computable from the leaves, by height then by position
+}
+
+// Prove returns a proof of the value of the leaf of the given key: a
+// membership proof if the leaf is not empty, a non-membership proof otherwise.
+func (t *Tree) Prove(key []byte) (Proof, error) {
+	if err := t.checkKey(key); err != nil {
+		return Proof{}, err
+	}
+	proof := Proof{
+		Key:      append([]byte(nil), key...),
+		Siblings: make([][]byte, t.depth),
+	}
+	proof.Value, _ = t.Get(key)
+	for d := 0; d < t.depth; d++ {
+		proof.Siblings[d] = t.node(flipBit(key, d), d)
+	}
+	return proof, nil
+}
+
+// ProveBatch returns a proof of the values of the leaves of the given keys.
+func (t *Tree) ProveBatch(keys [][]byte) (BatchProof, error) {
+	proof := BatchProof{
+		Keys:   make([][]byte, len(keys)),
+		Values: make([][]byte, len(keys)),
+	}
+	for i := range keys {
+		if err := t.checkKey(keys[i]); err != nil {
+			return BatchProof{}, err
+		}
+		proof.Keys[i] = append([]byte(nil), keys[i]...)
+		proof.Values[i], _ = t.Get(keys[i])
+	}
+
+	// the prover only needs the siblings, not the hashes of the traversal
+	_, err := traverse(nil, proof.Keys, nil, t.depth, func(path []byte, d int) ([]byte, error) {
+		s := t.node(path, d)
+		proof.Siblings = append(proof.Siblings, s)
+		return s, nil
+	})
+	if err != nil {
+		return BatchProof{}, err
+	}
+	return proof, nil
+}
+
+// VerifyProof returns true if proof is a valid proof for root, in a tree of
+// the given depth using the hash function h.
+func VerifyProof(h hash.Hash, root []byte, depth int, proof *Proof) bool {
+	if len(proof.Siblings) != depth || !validKey(h, proof.Key, depth) {
+		return false
+	}
+	current, err := leafOrEmpty(h, proof.Key, proof.Value)
+	if err != nil {
+		return false
+	}
+	for d := 0; d < depth; d++ {
+		if bit(proof.Key, d) == 0 {
+			current, err = nodeSum(h, current, proof.Siblings[d])
+		} else {
+			current, err = nodeSum(h, proof.Siblings[d], current)
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return bytes.Equal(current, root)
+}
+
+// VerifyBatchProof returns true if proof is a valid batch proof for root, in a
+// tree of the given depth using the hash function h.
+func VerifyBatchProof(h hash.Hash, root []byte, depth int, proof *BatchProof) bool {
+	if len(proof.Keys) != len(proof.Values) || len(proof.Keys) == 0 {
+		return false
+	}
+	leaves := make([][]byte, len(proof.Keys))
+	for i := range proof.Keys {
+		if !validKey(h, proof.Keys[i], depth) {
+			return false
+		}
+		var err error
+		if leaves[i], err = leafOrEmpty(h, proof.Keys[i], proof.Values[i]); err != nil {
+			return false
+		}
+	}
+
+	siblings := proof.Siblings
+	computed, err := traverse(h, proof.Keys, leaves, depth, func([]byte, int) ([]byte, error) {
+		if len(siblings) == 0 {
+			return nil, errInvalidProof
+		}
+		s := siblings[0]
+		siblings = siblings[1:]
+		return s, nil
+	})
+	if err != nil || len(siblings) != 0 {
+		return false
+	}
+	return bytes.Equal(computed, root)
+}
+
+// traverse computes the root from the leaves of the given keys, level by
+// level, calling sibling for each node whose sibling is not on the path of
+// one of the keys. If h is nil, only the sibling calls are made.
+func traverse(h hash.Hash, keys, leaves [][]byte, depth int, sibling func(path []byte, d int) ([]byte, error)) ([]byte, error) {
+	type node struct {
+		path []byte
+		hash []byte
+	}
+	nodes := make([]node, len(keys))
+	for i := range keys {
+		nodes[i].path = append([]byte(nil), keys[i]...)
+		if leaves != nil {
+			nodes[i].hash = leaves[i]
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return bytes.Compare(nodes[i].path, nodes[j].path) < 0
+	})
+	for i := 1; i < len(nodes); i++ {
+		if bytes.Equal(nodes[i-1].path, nodes[i].path) {
+			return nil, ErrDuplicatedKeys
+		}
+	}
+
+	for d := 0; d < depth; d++ {
+		parents := nodes[:0]
+		for i := 0; i < len(nodes); i++ {
+			var left, right []byte
+			path := nodes[i].path
+			if bit(path, d) == 0 && i+1 < len(nodes) && bytes.Equal(nodes[i+1].path, flipBit(path, d)) {
+				// both children are on the paths
+				left, right = nodes[i].hash, nodes[i+1].hash
+				i++
+			} else {
+				s, err := sibling(flipBit(path, d), d)
+				if err != nil {
+					return nil, err
+				}
+				if bit(path, d) == 0 {
+					left, right = nodes[i].hash, s
+				} else {
+					left, right = s, nodes[i].hash
+				}
+			}
+
+			clearBit(path, d)
+			parent := node{path: path}
+			if h != nil {
+				var err error
+				if parent.hash, err = nodeSum(h, left, right); err != nil {
+					return nil, err
+				}
+			}
+			parents = append(parents, parent)
+		}
+		nodes = parents
+	}
+	return nodes[0].hash, nil
+}
+
+func leafOrEmpty(h hash.Hash, key, value []byte) ([]byte, error) {
+	if value == nil {
+		return make([]byte, h.Size()), nil
+	}
+	if len(value) != h.Size() {
+		return nil, ErrInvalidValue
+	}
+	return leafSum(h, key, value)
+}
+
+func validKey(h hash.Hash, key []byte, depth int) bool {
+	if len(key) != h.Size() || depth < 1 || depth > 8*len(key) {
+		return false
+	}
+	for i := depth; i < 8*len(key); i++ {
+		if bit(key, i) != 0 {
+			return false
+		}
+	}
+	return true
+}
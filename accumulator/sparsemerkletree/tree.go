@@ -0,0 +1,259 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sparsemerkletree provides a sparse Merkle tree keyed by field
+// elements, with membership and non-membership proofs.
+//
+// The tree has a fixed depth; the leaf of key k is at position k, so k must
+// be smaller than 2^depth. Keys and values are field elements, encoded in
+// big endian on h.Size() bytes, as expected by the SNARK-friendly hash
+// functions (MiMC, Poseidon2...).
+//
+// The hashes are computed as follows:
+//
+//	empty leaf     = 0 (h.Size() zero bytes)
+//	non-empty leaf = H(key || value)
+//	node           = H(left || right)
+//
+// so that the root of an empty subtree of height d is a constant, and the
+// tree only stores its non-empty nodes.
+package sparsemerkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+)
+
+var (
+	ErrInvalidDepth   = errors.New("invalid depth: must be in [1, 8·h.Size()]")
+	ErrInvalidKey     = errors.New("invalid key: must be h.Size() bytes long and smaller than 2^depth")
+	ErrInvalidValue   = errors.New("invalid value: must be h.Size() bytes long")
+	ErrDuplicatedKeys = errors.New("duplicated keys")
+)
+
+// Tree is a sparse Merkle tree. It is not safe for concurrent use.
+type Tree struct {
+	h     hash.Hash
+	depth int
+	size  int // size of keys, values and digests
+
+	// empty[d] is the root of an empty subtree of height d
+	empty [][]byte
+
+	// nodes stores the non-empty nodes, see nodeID
+	nodes map[string][]byte
+
+	// values stores the values of the non-empty leaves, by key
+	values map[string][]byte
+}
+
+// Option modifies the parameters of a Tree.
+type Option func(*Tree)
+
+// WithHash sets the hash function of the tree. The default is MiMC on the
+// scalar field of BN254.
+func WithHash(h hash.Hash) Option {
+	return func(t *Tree) {
+		t.h = h
+	}
+}
+
+// New returns an empty tree of the given depth.
+func New(depth int, options ...Option) (*Tree, error) {
+	t := &Tree{
+		depth:  depth,
+		nodes:  make(map[string][]byte),
+		values: make(map[string][]byte),
+	}
+	for _, option := range options {
+		option(t)
+	}
+	if t.h == nil {
+		t.h = mimc.NewMiMC()
+	}
+	t.size = t.h.Size()
+	if depth < 1 || depth > 8*t.size {
+		return nil, ErrInvalidDepth
+	}
+
+	var err error
+	t.empty = make([][]byte, depth+1)
+	t.empty[0] = make([]byte, t.size)
+	for d := 0; d < depth; d++ {
+		if t.empty[d+1], err = nodeSum(t.h, t.empty[d], t.empty[d]); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Depth returns the depth of the tree.
+func (t *Tree) Depth() int {
+	return t.depth
+}
+
+// Root returns the root of the tree.
+func (t *Tree) Root() []byte {
+	return t.node(nil, t.depth)
+}
+
+// Get returns the value of the leaf of the given key, and false if the leaf
+// is empty.
+func (t *Tree) Get(key []byte) ([]byte, bool) {
+	v, ok := t.values[string(key)]
+	if !ok {
+		return nil, false
+	}
+	return append([]byte(nil), v...), true
+}
+
+// Update sets the value of the leaf of the given key, and updates the path
+// from the leaf to the root.
+func (t *Tree) Update(key, value []byte) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	if len(value) != t.size {
+		return ErrInvalidValue
+	}
+	leaf, err := leafSum(t.h, key, value)
+	if err != nil {
+		return err
+	}
+	if err := t.updatePath(key, leaf); err != nil {
+		return err
+	}
+	t.values[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete empties the leaf of the given key, and updates the path from the
+// leaf to the root. Deleting an empty leaf is a no-op.
+func (t *Tree) Delete(key []byte) error {
+	if err := t.checkKey(key); err != nil {
+		return err
+	}
+	if _, ok := t.values[string(key)]; !ok {
+		return nil
+	}
+	if err := t.updatePath(key, t.empty[0]); err != nil {
+		return err
+	}
+	delete(t.values, string(key))
+	return nil
+}
+
+// updatePath sets the leaf of the given key and recomputes its ancestors.
+func (t *Tree) updatePath(key, leaf []byte) error {
+	current := leaf
+	path := append([]byte(nil), key...)
+	var err error
+	for d := 0; d < t.depth; d++ {
+		t.setNode(path, d, current)
+		sibling := t.node(flipBit(path, d), d)
+		if bit(path, d) == 0 {
+			current, err = nodeSum(t.h, current, sibling)
+		} else {
+			current, err = nodeSum(t.h, sibling, current)
+		}
+		if err != nil {
+			return err
+		}
+		clearBit(path, d)
+	}
+	t.setNode(path, t.depth, current)
+	return nil
+}
+
+// node returns the node of height d on the path of key; key is nil for the
+// root.
+func (t *Tree) node(key []byte, d int) []byte {
+	if key == nil {
+		key = make([]byte, t.size)
+	}
+	if v, ok := t.nodes[nodeID(key, d)]; ok {
+		return v
+	}
+	return t.empty[d]
+}
+
+// setNode sets the node of height d on the path of key; empty nodes are not
+// stored.
+func (t *Tree) setNode(key []byte, d int, v []byte) {
+	id := nodeID(key, d)
+	if bytes.Equal(v, t.empty[d]) {
+		delete(t.nodes, id)
+		return
+	}
+	t.nodes[id] = v
+}
+
+func (t *Tree) checkKey(key []byte) error {
+	if !validKey(t.h, key, t.depth) {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// nodeID returns the identifier of the node of height d on the path of key,
+// that is the key with its d least significant bits cleared, followed by d.
+func nodeID(key []byte, d int) string {
+	id := make([]byte, len(key)+2)
+	copy(id, key)
+	for i := 0; i < d; i++ {
+		clearBit(id[:len(key)], i)
+	}
+	id[len(key)] = byte(d >> 8)
+	id[len(key)+1] = byte(d)
+	return string(id)
+}
+
+// bit returns the i-th least significant bit of the big endian integer b.
+func bit(b []byte, i int) byte {
+	return (b[len(b)-1-i/8] >> (i % 8)) & 1
+}
+
+func clearBit(b []byte, i int) {
+	b[len(b)-1-i/8] &^= 1 << (i % 8)
+}
+
+// flipBit returns a copy of b with its i-th least significant bit flipped.
+func flipBit(b []byte, i int) []byte {
+	res := append([]byte(nil), b...)
+	res[len(res)-1-i/8] ^= 1 << (i % 8)
+	return res
+}
+
+func sum(h hash.Hash, data ...[]byte) ([]byte, error) {
+	h.Reset()
+	for _, d := range data {
+		if _, err := h.Write(d); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// leafSum returns H(key || value).
+func leafSum(h hash.Hash, key, value []byte) ([]byte, error) {
+	return sum(h, key, value)
+}
+
+// nodeSum returns H(left || right).
+func nodeSum(h hash.Hash, left, right []byte) ([]byte, error) {
+	return sum(h, left, right)
+}
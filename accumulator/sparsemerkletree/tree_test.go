@@ -0,0 +1,253 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparsemerkletree
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
+	"github.com/stretchr/testify/require"
+)
+
+func element(v uint64) []byte {
+	var e fr.Element
+	e.SetUint64(v)
+	b := e.Bytes()
+	return b[:]
+}
+
+func randomElement() []byte {
+	var e fr.Element
+	e.SetRandom()
+	b := e.Bytes()
+	return b[:]
+}
+
+// denseRoot computes the root of a tree of the given depth from all its leaves.
+func denseRoot(t *testing.T, depth int, values map[uint64][]byte) []byte {
+	h := mimc.NewMiMC()
+	level := make([][]byte, 1<<depth)
+	for i := range level {
+		if v, ok := values[uint64(i)]; ok {
+			leaf, err := leafSum(h, element(uint64(i)), v)
+			require.NoError(t, err)
+			level[i] = leaf
+		} else {
+			level[i] = make([]byte, fr.Bytes)
+		}
+	}
+	for len(level) > 1 {
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			var err error
+			next[i], err = nodeSum(h, level[2*i], level[2*i+1])
+			require.NoError(t, err)
+		}
+		level = next
+	}
+	return level[0]
+}
+
+func TestTree(t *testing.T) {
+	assert := require.New(t)
+
+	const depth = 4
+	tree, err := New(depth)
+	assert.NoError(err)
+	emptyRoot := tree.Root()
+	assert.Equal(denseRoot(t, depth, nil), emptyRoot)
+
+	values := map[uint64][]byte{}
+	for _, k := range []uint64{3, 0, 15, 4, 3} {
+		values[k] = randomElement()
+		assert.NoError(tree.Update(element(k), values[k]))
+		assert.Equal(denseRoot(t, depth, values), tree.Root())
+	}
+
+	v, ok := tree.Get(element(4))
+	assert.True(ok)
+	assert.Equal(values[4], v)
+	_, ok = tree.Get(element(5))
+	assert.False(ok)
+
+	// delete
+	assert.NoError(tree.Delete(element(4)))
+	delete(values, 4)
+	assert.Equal(denseRoot(t, depth, values), tree.Root())
+	assert.NoError(tree.Delete(element(4)))
+	assert.Equal(denseRoot(t, depth, values), tree.Root())
+
+	// the root doesn't depend on the history
+	for k := range values {
+		assert.NoError(tree.Delete(element(k)))
+	}
+	assert.Equal(emptyRoot, tree.Root())
+	assert.Empty(tree.nodes)
+
+	// invalid inputs
+	assert.ErrorIs(tree.Update(element(16), randomElement()), ErrInvalidKey)
+	assert.ErrorIs(tree.Update(element(1)[1:], randomElement()), ErrInvalidKey)
+	assert.ErrorIs(tree.Update(element(1), element(1)[1:]), ErrInvalidValue)
+	_, err = New(0)
+	assert.ErrorIs(err, ErrInvalidDepth)
+	_, err = New(8*fr.Bytes + 1)
+	assert.ErrorIs(err, ErrInvalidDepth)
+}
+
+func TestProof(t *testing.T) {
+	assert := require.New(t)
+
+	// all the field elements are valid keys
+	const depth = fr.Bits
+	tree, err := New(depth, WithHash(mimc.NewMiMC()))
+	assert.NoError(err)
+
+	keys := make([][]byte, 10)
+	for i := range keys {
+		keys[i] = randomElement()
+		assert.NoError(tree.Update(keys[i], randomElement()))
+	}
+	root := tree.Root()
+	h := mimc.NewMiMC()
+
+	// membership
+	for _, key := range keys {
+		proof, err := tree.Prove(key)
+		assert.NoError(err)
+		assert.NotNil(proof.Value)
+		assert.True(VerifyProof(h, root, depth, &proof))
+
+		// wrong value
+		proof.Value = randomElement()
+		assert.False(VerifyProof(h, root, depth, &proof))
+
+		// claiming the leaf is empty
+		proof.Value = nil
+		assert.False(VerifyProof(h, root, depth, &proof))
+	}
+
+	// non-membership
+	absent := randomElement()
+	proof, err := tree.Prove(absent)
+	assert.NoError(err)
+	assert.Nil(proof.Value)
+	assert.True(VerifyProof(h, root, depth, &proof))
+	proof.Value = randomElement()
+	assert.False(VerifyProof(h, root, depth, &proof))
+
+	// the depth is fixed by the verifier
+	proof.Value = nil
+	assert.False(VerifyProof(h, root, depth-1, &proof))
+	proof.Siblings = proof.Siblings[1:]
+	assert.False(VerifyProof(h, root, depth, &proof))
+}
+
+func TestBatchProof(t *testing.T) {
+	assert := require.New(t)
+
+	const depth = 8
+	tree, err := New(depth)
+	assert.NoError(err)
+	for _, k := range []uint64{0, 1, 2, 7, 100, 101, 200, 255} {
+		assert.NoError(tree.Update(element(k), randomElement()))
+	}
+	root := tree.Root()
+	h := mimc.NewMiMC()
+
+	// present and absent keys, siblings and not, in any order
+	keys := [][]byte{element(101), element(3), element(0), element(1), element(255), element(254), element(100)}
+	proof, err := tree.ProveBatch(keys)
+	assert.NoError(err)
+	assert.True(VerifyBatchProof(h, root, depth, &proof))
+
+	// the batch proof is smaller than the single proofs
+	assert.Less(len(proof.Siblings), len(keys)*depth)
+
+	// values are checked
+	for i := range keys {
+		v, ok := tree.Get(keys[i])
+		if ok {
+			assert.Equal(v, proof.Values[i])
+		} else {
+			assert.Nil(proof.Values[i])
+		}
+	}
+
+	// wrong value
+	proof.Values[1] = randomElement()
+	assert.False(VerifyBatchProof(h, root, depth, &proof))
+	proof.Values[1] = nil
+
+	// wrong sibling
+	proof.Siblings[0] = randomElement()
+	assert.False(VerifyBatchProof(h, root, depth, &proof))
+
+	// missing or extra siblings
+	proof, _ = tree.ProveBatch(keys)
+	proof.Siblings = proof.Siblings[1:]
+	assert.False(VerifyBatchProof(h, root, depth, &proof))
+	proof, _ = tree.ProveBatch(keys)
+	proof.Siblings = append(proof.Siblings, randomElement())
+	assert.False(VerifyBatchProof(h, root, depth, &proof))
+
+	// single key
+	proof, err = tree.ProveBatch(keys[:1])
+	assert.NoError(err)
+	assert.Len(proof.Siblings, depth)
+	assert.True(VerifyBatchProof(h, root, depth, &proof))
+
+	// duplicated keys
+	_, err = tree.ProveBatch([][]byte{element(1), element(1)})
+	assert.ErrorIs(err, ErrDuplicatedKeys)
+}
+
+func TestProveAfterUpdate(t *testing.T) {
+	assert := require.New(t)
+
+	const depth = 16
+	tree, err := New(depth)
+	assert.NoError(err)
+	h := mimc.NewMiMC()
+
+	key := element(42)
+	assert.NoError(tree.Update(key, element(1)))
+	oldRoot := tree.Root()
+	oldProof, _ := tree.Prove(key)
+
+	assert.NoError(tree.Update(key, element(2)))
+	newProof, _ := tree.Prove(key)
+	assert.True(VerifyProof(h, tree.Root(), depth, &newProof))
+	assert.False(VerifyProof(h, tree.Root(), depth, &oldProof))
+	assert.True(VerifyProof(h, oldRoot, depth, &oldProof))
+
+	// an update only changes the value, the siblings are the same
+	for d := range oldProof.Siblings {
+		assert.True(bytes.Equal(oldProof.Siblings[d], newProof.Siblings[d]))
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	tree, _ := New(fr.Bits)
+	keys := make([][]byte, 1024)
+	for i := range keys {
+		keys[i] = randomElement()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Update(keys[i%len(keys)], keys[(i+1)%len(keys)])
+	}
+}
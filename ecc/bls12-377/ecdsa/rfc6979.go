@@ -0,0 +1,206 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/sha256"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+)
+
+// SignDeterministic performs the ECDSA signature with the deterministic nonce
+// of RFC 6979, and returns the signature r||s with s ≤ order/2 (low-s).
+//
+// hFunc hashes the message and instantiates the HMAC of the nonce generation,
+// so it must be a byte oriented hash function (SHA-2, Keccak...). If hFunc is
+// nil, message is the hash of the message, and the nonce generation uses
+// SHA-256.
+//
+// The signature of a message by a key is always the same, and doesn't depend
+// on the quality of the source of randomness.
+//
+// https://www.rfc-editor.org/rfc/rfc6979
+func (privKey *PrivateKey) SignDeterministic(message []byte, hFunc hash.Hash) ([]byte, error) {
+	_, r, s, err := privKey.signDeterministic(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	var sig Signature
+	r.FillBytes(sig.R[:sizeFr])
+	s.FillBytes(sig.S[:sizeFr])
+
+	return sig.Bytes(), nil
+}
+
+// signDeterministic returns the low-s signature (r, s) of message with the
+// RFC 6979 nonce, and the recovery information v.
+func (privKey *PrivateKey) signDeterministic(message []byte, hFunc hash.Hash) (v uint, r, s *big.Int, err error) {
+	h1 := message
+	if hFunc != nil {
+		hFunc.Reset()
+		if _, err := hFunc.Write(message); err != nil {
+			return 0, nil, nil, err
+		}
+		h1 = hFunc.Sum(nil)
+	} else {
+		hFunc = sha256.New()
+	}
+	m := HashToInt(h1)
+
+	scalar := new(big.Int).SetBytes(privKey.scalar[:sizeFr])
+	nonces := newRFC6979Nonces(hFunc, privKey.scalar[:sizeFr], bits2octets(h1))
+	r, s = new(big.Int), new(big.Int)
+	for {
+		k := nonces.next()
+
+		var P bls12377.G1Affine
+		P.ScalarMultiplicationBaseConstantTime(k)
+
+		P.X.BigInt(r)
+		// set how many times we overflow the scalar field
+		v = uint(new(big.Int).Div(r, order).Uint64()) << 1
+		// set if y is even or odd
+		v |= P.Y.BigInt(new(big.Int)).Bit(0)
+
+		r.Mod(r, order)
+		if r.Sign() == 0 {
+			continue
+		}
+
+		// s = k⁻¹ . (m + sk ⋅ r)
+		s.Mul(r, scalar).
+			Add(s, m).
+			Mul(s, k.ModInverse(k, order)).
+			Mod(s, order)
+		if s.Sign() != 0 {
+			break
+		}
+	}
+
+	// (r, -s) is also a valid signature, for -R
+	if s.Cmp(halfOrder) > 0 {
+		s.Sub(order, s)
+		v ^= 1
+	}
+	return v, r, s, nil
+}
+
+// rfc6979Nonces is the HMAC_DRBG of RFC 6979, section 3.2.
+type rfc6979Nonces struct {
+	h    hash.Hash
+	k, v []byte
+}
+
+// newRFC6979Nonces instantiates the generator with the private key x and the
+// hash of the message h1, both encoded on sizeFr bytes (steps b. to g.).
+func newRFC6979Nonces(h hash.Hash, x, h1 []byte) *rfc6979Nonces {
+	size := h.Size()
+	g := &rfc6979Nonces{
+		h: h,
+		k: make([]byte, size),
+		v: make([]byte, size),
+	}
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	g.k = g.hmac(g.v, []byte{0x00}, x, h1)
+	g.v = g.hmac(g.v)
+	g.k = g.hmac(g.v, []byte{0x01}, x, h1)
+	g.v = g.hmac(g.v)
+	return g
+}
+
+// next returns the next candidate nonce in [1, order) (step h.).
+func (g *rfc6979Nonces) next() *big.Int {
+	for {
+		t := make([]byte, 0, sizeFr+g.h.Size())
+		for len(t) < sizeFr {
+			g.v = g.hmac(g.v)
+			t = append(t, g.v...)
+		}
+		k := bits2int(t)
+
+		// prepare the next candidate
+		g.k = g.hmac(g.v, []byte{0x00})
+		g.v = g.hmac(g.v)
+
+		if k.Sign() > 0 && k.Cmp(order) < 0 {
+			return k
+		}
+	}
+}
+
+// hmac returns HMAC_k(data...), with the hash function of the generator.
+func (g *rfc6979Nonces) hmac(data ...[]byte) []byte {
+	blockSize := g.h.BlockSize()
+	key := g.k
+	if len(key) > blockSize {
+		g.h.Reset()
+		g.h.Write(key)
+		key = g.h.Sum(nil)
+	}
+	pad := make([]byte, blockSize)
+
+	// inner hash, with the key xored with ipad
+	copy(pad, key)
+	for i := range pad {
+		pad[i] ^= 0x36
+	}
+	g.h.Reset()
+	g.h.Write(pad)
+	for _, d := range data {
+		g.h.Write(d)
+	}
+	inner := g.h.Sum(nil)
+
+	// outer hash, with the key xored with opad
+	for i := range pad {
+		pad[i] = 0
+	}
+	copy(pad, key)
+	for i := range pad {
+		pad[i] ^= 0x5c
+	}
+	g.h.Reset()
+	g.h.Write(pad)
+	g.h.Write(inner)
+	return g.h.Sum(nil)
+}
+
+// bits2int returns the integer of the qlen leftmost bits of b, where qlen is
+// the bit length of the order (RFC 6979, section 2.3.2).
+func bits2int(b []byte) *big.Int {
+	res := new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - order.BitLen(); excess > 0 {
+		res.Rsh(res, uint(excess))
+	}
+	return res
+}
+
+// bits2octets returns bits2int(b) mod order, on sizeFr bytes (RFC 6979,
+// section 2.3.4).
+func bits2octets(b []byte) []byte {
+	z := bits2int(b)
+	if z.Cmp(order) >= 0 {
+		z.Sub(z, order)
+	}
+	res := make([]byte, sizeFr)
+	z.FillBytes(res)
+	return res
+}
@@ -230,20 +230,11 @@ func VerifyLookupTables(vk kzg.VerifyingKey, proof ProofLookupTables) error {
 	return VerifyLookupVector(vk, proof.foldedProof)
 }
 
-// TODO put that in fiat-shamir package
+// deriveRandomness binds the points to the challenge and returns it as a
+// field element.
 func deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...*bls12377.G1Affine) (fr.Element, error) {
-
-	var buf [bls12377.SizeOfG1AffineUncompressed]byte
 	var r fr.Element
-
-	for _, p := range points {
-		buf = p.RawBytes()
-		if err := fs.Bind(challenge, buf[:]); err != nil {
-			return r, err
-		}
-	}
-
-	b, err := fs.ComputeChallenge(challenge)
+	b, err := fiatshamir.ComputeChallengeFrom(fs, challenge, points...)
 	if err != nil {
 		return r, err
 	}
@@ -0,0 +1,450 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package secretsharing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+)
+
+var (
+	ErrInvalidRound        = errors.New("DKG round called out of order")
+	ErrTooManyDisqualified = errors.New("not enough qualified dealers")
+)
+
+// DKG is the state of a participant in the Pedersen distributed key
+// generation of Gennaro, Jarecki, Krawczyk and Rabin. The participants are
+// identified by 1..n, and any threshold of them can use the generated key.
+//
+// The messages are either broadcast (all the participants must receive the
+// same list) or sent privately to their recipient. The participants call, in
+// order:
+//
+//  1. Deal: broadcast the Deal and send each DealShare to its recipient;
+//  2. ProcessDeals, with the n deals and the shares received: broadcast the
+//     complaints against the dealers whose share is missing or invalid;
+//  3. Respond, with all the complaints: broadcast the shares revealed to
+//     answer the complaints against the participant;
+//  4. ProcessResponses, with all the complaints and responses: this fixes the
+//     set of qualified dealers. Broadcast the returned Extraction, the Feldman
+//     commitment to the secret polynomial;
+//  5. ProcessExtractions, with all the extractions: broadcast the complaints
+//     against the qualified dealers whose extraction doesn't match the share;
+//  6. Reconstruct, with all the extraction complaints: broadcast the shares
+//     of the dealers with a valid complaint, so that their public polynomial
+//     can be reconstructed;
+//  7. Finalize, with all the reconstruction shares: this returns the key
+//     share of the participant.
+//
+// The first three rounds are the Pedersen verifiable secret sharing of a
+// random secret by each participant, which fixes the group secret without
+// revealing anything about it. The last ones reveal the Feldman commitments
+// (and so the group key) of the qualified dealers.
+type DKG struct {
+	id           uint64
+	threshold, n int
+	round        int
+
+	f, fBlinding polynomial.Polynomial
+
+	deals    map[uint64]PedersenCommitment // broadcast in round 1
+	received map[uint64]PedersenShare      // shares received from the dealers
+	qual     []uint64                      // qualified dealers, sorted
+
+	extractions map[uint64]FeldmanCommitment // of the qualified dealers
+	accused     []uint64                     // qualified dealers with a valid extraction complaint, sorted
+}
+
+// Deal is the message broadcast by a dealer in the first round: the Pedersen
+// commitment to its secret polynomial.
+type Deal struct {
+	From       uint64
+	Commitment PedersenCommitment
+}
+
+// DealShare is the share of the secret of the dealer From, for the
+// participant To. It is sent privately in the first round, and broadcast to
+// answer a complaint or to reconstruct the polynomial of a dealer.
+type DealShare struct {
+	From, To uint64
+	Share    PedersenShare
+}
+
+// Complaint is broadcast by the participant From when the share of the dealer
+// Against is missing or doesn't match its commitment.
+type Complaint struct {
+	From, Against uint64
+}
+
+// Extraction is the message broadcast by a qualified dealer in the fourth
+// round: the Feldman commitment to its secret polynomial.
+type Extraction struct {
+	From       uint64
+	Commitment FeldmanCommitment
+}
+
+// ExtractionComplaint is broadcast by the participant From when the extraction
+// of the dealer Against is missing or doesn't match the share it received.
+// The share is revealed so that everybody can check the complaint.
+type ExtractionComplaint struct {
+	From, Against uint64
+	Share         PedersenShare
+}
+
+// KeyShare is the result of the distributed key generation for a participant.
+type KeyShare struct {
+	Share                               // secret share of the participant
+	VerificationShare curve.G1Affine    // Share.Value⋅G
+	GroupKey          curve.G1Affine    // group secret ⋅ G
+	Commitment        FeldmanCommitment // Feldman commitment to the sharing polynomial of the group secret
+	Qualified         []uint64          // dealers whose secret is part of the group secret
+}
+
+// NewDKG returns the state of the participant id in [1, n] in the generation
+// of a threshold-of-n key.
+func NewDKG(id uint64, threshold, n int) (*DKG, error) {
+	if threshold < 1 || threshold > n {
+		return nil, ErrInvalidThreshold
+	}
+	if id == 0 || id > uint64(n) {
+		return nil, ErrInvalidID
+	}
+	return &DKG{id: id, threshold: threshold, n: n}, nil
+}
+
+// Deal samples the secret polynomial of the participant, and returns the deal
+// to broadcast and the shares to send to the other participants.
+func (d *DKG) Deal() (Deal, []DealShare, error) {
+	if d.round != 0 {
+		return Deal{}, nil, ErrInvalidRound
+	}
+	var secret, zero fr.Element
+	if _, err := secret.SetRandom(); err != nil {
+		return Deal{}, nil, err
+	}
+	var err error
+	if d.f, err = randomPolynomial(&secret, d.threshold, d.n); err != nil {
+		return Deal{}, nil, err
+	}
+	secret.SetZero()
+	if d.fBlinding, err = randomPolynomial(&zero, d.threshold, d.n); err != nil {
+		return Deal{}, nil, err
+	}
+	if _, err = d.fBlinding[0].SetRandom(); err != nil {
+		return Deal{}, nil, err
+	}
+
+	deal := Deal{From: d.id, Commitment: commitPedersen(d.f, d.fBlinding)}
+	pedersenShares := evalPedersenShares(d.f, d.fBlinding, d.n)
+	shares := make([]DealShare, 0, d.n-1)
+	for i := range pedersenShares {
+		if pedersenShares[i].ID == d.id {
+			continue
+		}
+		shares = append(shares, DealShare{From: d.id, To: pedersenShares[i].ID, Share: pedersenShares[i]})
+	}
+	d.round++
+	return deal, shares, nil
+}
+
+// ProcessDeals checks the shares sent to the participant against the deals,
+// and returns the complaints to broadcast. A dealer without a well-formed deal
+// is disqualified.
+func (d *DKG) ProcessDeals(deals []Deal, shares []DealShare) ([]Complaint, error) {
+	if d.round != 1 {
+		return nil, ErrInvalidRound
+	}
+	d.deals = make(map[uint64]PedersenCommitment, len(deals))
+	for i := range deals {
+		from := deals[i].From
+		if !d.isParticipant(from) || len(deals[i].Commitment) != d.threshold {
+			continue
+		}
+		if _, ok := d.deals[from]; ok {
+			// two deals from the same dealer: disqualified
+			d.deals[from] = nil
+			continue
+		}
+		d.deals[from] = deals[i].Commitment
+	}
+
+	d.received = make(map[uint64]PedersenShare, len(shares)+1)
+	d.received[d.id] = evalPedersenShares(d.f, d.fBlinding, d.n)[d.id-1]
+	for i := range shares {
+		from := shares[i].From
+		if shares[i].To != d.id || shares[i].Share.ID != d.id || !d.isParticipant(from) || from == d.id {
+			continue
+		}
+		if _, ok := d.received[from]; ok {
+			continue
+		}
+		d.received[from] = shares[i].Share
+	}
+
+	var complaints []Complaint
+	for _, from := range d.dealers() {
+		share, ok := d.received[from]
+		if !ok || d.deals[from].Verify(&share) != nil {
+			delete(d.received, from)
+			complaints = append(complaints, Complaint{From: d.id, Against: from})
+		}
+	}
+	d.round++
+	return complaints, nil
+}
+
+// Respond returns the shares to broadcast to answer the complaints against
+// the participant.
+func (d *DKG) Respond(complaints []Complaint) ([]DealShare, error) {
+	if d.round != 2 {
+		return nil, ErrInvalidRound
+	}
+	var responses []DealShare
+	answered := make(map[uint64]bool)
+	for i := range complaints {
+		to := complaints[i].From
+		if complaints[i].Against != d.id || !d.isParticipant(to) || answered[to] {
+			continue
+		}
+		answered[to] = true
+		var x fr.Element
+		x.SetUint64(to)
+		share := PedersenShare{Share: Share{ID: to, Value: d.f.Eval(&x)}, Blinding: d.fBlinding.Eval(&x)}
+		responses = append(responses, DealShare{From: d.id, To: to, Share: share})
+	}
+	d.round++
+	return responses, nil
+}
+
+// ProcessResponses determines the set of qualified dealers, and returns the
+// extraction to broadcast if the participant is qualified (the zero
+// Extraction otherwise).
+//
+// A dealer is disqualified if it receives threshold complaints or more, or if
+// one of the complaints against it is not answered by a valid share. The
+// participant replaces the shares it complained about by the revealed ones.
+func (d *DKG) ProcessResponses(complaints []Complaint, responses []DealShare) (Extraction, error) {
+	if d.round != 3 {
+		return Extraction{}, ErrInvalidRound
+	}
+	type pair struct{ from, against uint64 }
+	complained := make(map[pair]bool)
+	nbComplaints := make(map[uint64]int)
+	for i := range complaints {
+		c := pair{complaints[i].From, complaints[i].Against}
+		if !d.isParticipant(c.from) || !d.isParticipant(c.against) || complained[c] {
+			continue
+		}
+		complained[c] = true
+		nbComplaints[c.against]++
+	}
+	answered := make(map[pair]bool)
+	for i := range responses {
+		r := &responses[i]
+		c := pair{r.To, r.From}
+		if !complained[c] || answered[c] || r.Share.ID != r.To {
+			continue
+		}
+		commitment := d.deals[r.From]
+		if commitment == nil || commitment.Verify(&r.Share) != nil {
+			continue
+		}
+		answered[c] = true
+		if r.To == d.id {
+			d.received[r.From] = r.Share
+		}
+	}
+
+	d.qual = d.qual[:0]
+	for _, from := range d.dealers() {
+		if nbComplaints[from] >= d.threshold {
+			continue
+		}
+		qualified := true
+		for c := range complained {
+			if c.against == from && !answered[c] {
+				qualified = false
+				break
+			}
+		}
+		if qualified {
+			d.qual = append(d.qual, from)
+		}
+	}
+	if len(d.qual) < d.threshold {
+		return Extraction{}, ErrTooManyDisqualified
+	}
+	d.round++
+
+	if !d.isQualified(d.id) {
+		return Extraction{}, nil
+	}
+	return Extraction{From: d.id, Commitment: commitFeldman(d.f)}, nil
+}
+
+// ProcessExtractions checks the extractions of the qualified dealers against
+// the shares received from them, and returns the complaints to broadcast.
+func (d *DKG) ProcessExtractions(extractions []Extraction) ([]ExtractionComplaint, error) {
+	if d.round != 4 {
+		return nil, ErrInvalidRound
+	}
+	d.extractions = make(map[uint64]FeldmanCommitment, len(extractions))
+	for i := range extractions {
+		from := extractions[i].From
+		if !d.isQualified(from) || len(extractions[i].Commitment) != d.threshold {
+			continue
+		}
+		if _, ok := d.extractions[from]; ok {
+			d.extractions[from] = nil
+			continue
+		}
+		d.extractions[from] = extractions[i].Commitment
+	}
+
+	var complaints []ExtractionComplaint
+	for _, from := range d.qual {
+		share := d.received[from]
+		if c := d.extractions[from]; c == nil || c.Verify(&share.Share) != nil {
+			complaints = append(complaints, ExtractionComplaint{From: d.id, Against: from, Share: share})
+		}
+	}
+	d.round++
+	return complaints, nil
+}
+
+// Reconstruct checks the extraction complaints, and returns the shares to
+// broadcast so that the public polynomials of the accused dealers can be
+// reconstructed.
+func (d *DKG) Reconstruct(complaints []ExtractionComplaint) ([]DealShare, error) {
+	if d.round != 5 {
+		return nil, ErrInvalidRound
+	}
+	accused := make(map[uint64]bool)
+	for i := range complaints {
+		c := &complaints[i]
+		if accused[c.Against] || !d.isQualified(c.Against) || !d.isParticipant(c.From) || c.Share.ID != c.From {
+			continue
+		}
+		// the share must be genuine, and not match the extraction
+		if d.deals[c.Against].Verify(&c.Share) != nil {
+			continue
+		}
+		if e := d.extractions[c.Against]; e != nil && e.Verify(&c.Share.Share) == nil {
+			continue
+		}
+		accused[c.Against] = true
+	}
+	d.accused = d.accused[:0]
+	for from := range accused {
+		d.accused = append(d.accused, from)
+	}
+	sort.Slice(d.accused, func(i, j int) bool { return d.accused[i] < d.accused[j] })
+
+	shares := make([]DealShare, 0, len(d.accused))
+	for _, from := range d.accused {
+		shares = append(shares, DealShare{From: from, To: d.id, Share: d.received[from]})
+	}
+	d.round++
+	return shares, nil
+}
+
+// Finalize reconstructs the public polynomials of the accused dealers from the
+// reconstruction shares, and returns the key share of the participant. The
+// secret polynomial of the participant is erased.
+func (d *DKG) Finalize(shares []DealShare) (KeyShare, error) {
+	if d.round != 6 {
+		return KeyShare{}, ErrInvalidRound
+	}
+	for _, from := range d.accused {
+		points := make([]Share, 0, d.threshold)
+		seen := make(map[uint64]bool)
+		for i := range shares {
+			s := &shares[i]
+			if s.From != from || s.Share.ID != s.To || !d.isParticipant(s.To) || seen[s.To] {
+				continue
+			}
+			if d.deals[from].Verify(&s.Share) != nil {
+				continue
+			}
+			seen[s.To] = true
+			points = append(points, s.Share.Share)
+			if len(points) == d.threshold {
+				break
+			}
+		}
+		if len(points) < d.threshold {
+			return KeyShare{}, fmt.Errorf("dealer %d: %w", from, ErrNotEnoughShares)
+		}
+		f := interpolate(points)
+		d.extractions[from] = commitFeldman(f)
+		erase(f)
+	}
+
+	var res KeyShare
+	res.ID = d.id
+	res.Qualified = append([]uint64(nil), d.qual...)
+	for _, from := range d.qual {
+		share := d.received[from]
+		res.Value.Add(&res.Value, &share.Value)
+		res.Commitment = addCommitments(res.Commitment, d.extractions[from])
+	}
+	res.GroupKey = res.Commitment[0]
+	var b big.Int
+	res.VerificationShare.ScalarMultiplicationBaseConstantTime(res.Value.BigInt(&b))
+	if expected := res.Commitment.Eval(d.id); !expected.Equal(&res.VerificationShare) {
+		return KeyShare{}, ErrInvalidShare
+	}
+
+	erase(d.f)
+	erase(d.fBlinding)
+	d.f, d.fBlinding = nil, nil
+	for from, share := range d.received {
+		share.Value.SetZero()
+		share.Blinding.SetZero()
+		d.received[from] = share
+	}
+	d.round++
+	return res, nil
+}
+
+// dealers returns the identifiers of the dealers with a well-formed deal,
+// sorted.
+func (d *DKG) dealers() []uint64 {
+	res := make([]uint64, 0, len(d.deals))
+	for from, c := range d.deals {
+		if c != nil {
+			res = append(res, from)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+func (d *DKG) isParticipant(id uint64) bool {
+	return id != 0 && id <= uint64(d.n)
+}
+
+func (d *DKG) isQualified(id uint64) bool {
+	i := sort.Search(len(d.qual), func(i int) bool { return d.qual[i] >= id })
+	return i < len(d.qual) && d.qual[i] == id
+}
@@ -17,6 +17,7 @@
 package bls12377
 
 import (
+	"crypto/subtle"
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/internal/fptower"
@@ -909,6 +910,192 @@ func (p *g2Proj) FromAffine(Q *G2Affine) *g2Proj {
 	return p
 }
 
+// -------------------------------------------------------------------------------------------------
+// Constant time
+
+// ctG2WindowSize is the size in bits of the windows of ScalarMultiplicationConstantTime
+const ctG2WindowSize = 4
+
+// ScalarMultiplicationConstantTime computes and returns p = [s]a
+// in constant time (see G2Jac.ScalarMultiplicationConstantTime).
+func (p *G2Affine) ScalarMultiplicationConstantTime(a *G2Affine, s *big.Int) *G2Affine {
+	var _p G2Jac
+	_p.FromAffine(a)
+	_p.ScalarMultiplicationConstantTime(&_p, s)
+	p.FromJacobian(&_p)
+	return p
+}
+
+// ScalarMultiplicationBaseConstantTime computes and returns p = [s]g, where g
+// is the prime subgroup generator, in constant time (see
+// G2Jac.ScalarMultiplicationConstantTime).
+func (p *G2Affine) ScalarMultiplicationBaseConstantTime(s *big.Int) *G2Affine {
+	return p.ScalarMultiplicationConstantTime(&g2GenAff, s)
+}
+
+// ScalarMultiplicationConstantTime computes and returns p = [s]a, a in the
+// prime order subgroup, with a sequence of operations and memory accesses
+// that doesn't depend on s. It should be used instead of ScalarMultiplication
+// when s is secret (signing keys, trusted setup secrets), and is slower.
+//
+// s is reduced modulo r and processed with fixed 4-bit windows. The
+// multiples of a are selected from the table with conditional copies, and
+// added with the complete formulas of Renes, Costello and Batina, which have
+// no special case for equal points or the point at infinity
+// (https://eprint.iacr.org/2015/1060).
+//
+// The guarantee is at the group law level: the additions and subtractions of
+// the field packages end with a conditional reduction, whose timing variations
+// can be measured with the dudect test of this package (set
+// DUDECT_MEASUREMENTS to run it).
+func (p *G2Jac) ScalarMultiplicationConstantTime(a *G2Jac, s *big.Int) *G2Jac {
+	const w = ctG2WindowSize
+	var b3 fptower.E2
+	b3.Double(&bTwistCurveCoeff).Add(&b3, &bTwistCurveCoeff)
+
+	// table[i] = [i]a
+	var table [1 << w]g2Proj
+	table[0].setInfinity()
+	table[1].fromJacobian(a)
+	for i := 2; i < len(table); i++ {
+		table[i].addComplete(&table[i-1], &table[1], &b3)
+	}
+
+	var e fr.Element
+	e.SetBigInt(s)
+	k := e.Bits()
+
+	var res, t g2Proj
+	res.setInfinity()
+	for i := (fr.Bits+w-1)/w - 1; i >= 0; i-- {
+		for j := 0; j < w; j++ {
+			res.doubleComplete(&res, &b3)
+		}
+		// w divides 64, so that a window doesn't overlap two words
+		digit := (k[(i*w)/64] >> ((i * w) % 64)) & (1<<w - 1)
+		t.setInfinity()
+		for j := 1; j < len(table); j++ {
+			t.cmov(subtle.ConstantTimeEq(int32(j), int32(digit)), &table[j])
+		}
+		res.addComplete(&res, &t, &b3)
+	}
+
+	// (x:y:z) -> (xz, yz², z)
+	p.X.Mul(&res.x, &res.z)
+	p.Y.Square(&res.z).Mul(&p.Y, &res.y)
+	p.Z.Set(&res.z)
+	// the point at infinity is (1:1:0) in Jacobian coordinates, this only
+	// depends on s if s = 0 mod r
+	if p.Z.IsZero() {
+		p.X.SetOne()
+		p.Y.SetOne()
+	}
+	return p
+}
+
+// setInfinity sets p to the point at infinity (0:1:0).
+func (p *g2Proj) setInfinity() *g2Proj {
+	p.x.SetZero()
+	p.y.SetOne()
+	p.z.SetZero()
+	return p
+}
+
+// fromJacobian sets p = a, without branching on the point at infinity.
+func (p *g2Proj) fromJacobian(a *G2Jac) *g2Proj {
+	// (X:Y:Z) -> (XZ:Y:Z³)
+	var z fptower.E2
+	z.Square(&a.Z).Mul(&z, &a.Z)
+	p.x.Mul(&a.X, &a.Z)
+	p.y.Set(&a.Y)
+	p.z = z
+	return p
+}
+
+// cmov sets p to a if c = 1, and leaves p unchanged if c = 0.
+func (p *g2Proj) cmov(c int, a *g2Proj) {
+	cmovG2Coordinate(c, &p.x, &a.x)
+	cmovG2Coordinate(c, &p.y, &a.y)
+	cmovG2Coordinate(c, &p.z, &a.z)
+}
+
+// cmovG2Coordinate sets z to x if c = 1, and leaves z unchanged if c = 0.
+func cmovG2Coordinate(c int, z, x *fptower.E2) {
+	z.A0.Select(c, &z.A0, &x.A0)
+	z.A1.Select(c, &z.A1, &x.A1)
+}
+
+// addComplete sets p = a + b with the complete addition formulas for short
+// Weierstrass curves with a = 0 (https://eprint.iacr.org/2015/1060,
+// algorithm 7), where b3 = 3b.
+func (p *g2Proj) addComplete(a, b *g2Proj, b3 *fptower.E2) *g2Proj {
+	var t0, t1, t2, t3, t4, x3, y3, z3 fptower.E2
+	t0.Mul(&a.x, &b.x)
+	t1.Mul(&a.y, &b.y)
+	t2.Mul(&a.z, &b.z)
+	t3.Add(&a.x, &a.y)
+	t4.Add(&b.x, &b.y)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(&a.y, &a.z)
+	x3.Add(&b.y, &b.z)
+	t4.Mul(&t4, &x3)
+	x3.Add(&t1, &t2)
+	t4.Sub(&t4, &x3)
+	x3.Add(&a.x, &a.z)
+	y3.Add(&b.x, &b.z)
+	x3.Mul(&x3, &y3)
+	y3.Add(&t0, &t2)
+	y3.Sub(&x3, &y3)
+	x3.Double(&t0)
+	t0.Add(&x3, &t0)
+	t2.Mul(&t2, b3)
+	z3.Add(&t1, &t2)
+	t1.Sub(&t1, &t2)
+	y3.Mul(&y3, b3)
+	x3.Mul(&t4, &y3)
+	t2.Mul(&t3, &t1)
+	x3.Sub(&t2, &x3)
+	y3.Mul(&y3, &t0)
+	t1.Mul(&t1, &z3)
+	y3.Add(&t1, &y3)
+	t0.Mul(&t0, &t3)
+	z3.Mul(&z3, &t4)
+	z3.Add(&z3, &t0)
+
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// doubleComplete sets p = 2a with the complete doubling formulas for short
+// Weierstrass curves with a = 0 (https://eprint.iacr.org/2015/1060,
+// algorithm 9), where b3 = 3b.
+func (p *g2Proj) doubleComplete(a *g2Proj, b3 *fptower.E2) *g2Proj {
+	var t0, t1, t2, x3, y3, z3 fptower.E2
+	t0.Square(&a.y)
+	z3.Double(&t0)
+	z3.Double(&z3)
+	z3.Double(&z3)
+	t1.Mul(&a.y, &a.z)
+	t2.Square(&a.z)
+	t2.Mul(&t2, b3)
+	x3.Mul(&t2, &z3)
+	y3.Add(&t0, &t2)
+	z3.Mul(&t1, &z3)
+	t1.Double(&t2)
+	t2.Add(&t1, &t2)
+	t0.Sub(&t0, &t2)
+	y3.Mul(&t0, &y3)
+	y3.Add(&x3, &y3)
+	t1.Mul(&a.x, &a.y)
+	x3.Mul(&t0, &t1)
+	x3.Double(&x3)
+
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
 // BatchScalarMultiplicationG2 multiplies the same base by all scalars
 // and return resulting points in affine coordinates
 // uses a simple windowed-NAF like exponentiation algorithm
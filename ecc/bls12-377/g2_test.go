@@ -18,13 +18,17 @@ package bls12377
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"math/rand"
+	"os"
+	"strconv"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/internal/fptower"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/internal/dudect"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
 )
@@ -389,6 +393,44 @@ func TestG2AffineOps(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BLS12-377] constant time scalar multiplication and ScalarMultiplication should output the same result", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var scalar big.Int
+			s.BigInt(&scalar)
+			var op1, op2 G2Jac
+			op1.ScalarMultiplicationConstantTime(&g2Gen, &scalar)
+			op2.ScalarMultiplication(&g2Gen, &scalar)
+
+			// the result of an addition of equal points and of the point at
+			// infinity are computed without special cases
+			var a, b G2Affine
+			a.ScalarMultiplicationConstantTime(&g2GenAff, &scalar)
+			b.FromJacobian(&op2)
+
+			return op1.Equal(&op2) && a.Equal(&b)
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS12-377] constant time scalar multiplication should handle 0, r and the point at infinity", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var scalar big.Int
+			s.BigInt(&scalar)
+			var op1, op2, op3, op4 G2Jac
+			op1.ScalarMultiplicationConstantTime(&g2Gen, big.NewInt(0))
+			op2.ScalarMultiplicationConstantTime(&g2Gen, fr.Modulus())
+			op3.ScalarMultiplicationConstantTime(&g2Infinity, &scalar)
+			op4.ScalarMultiplicationConstantTime(&g2Gen, new(big.Int).Sub(fr.Modulus(), big.NewInt(1)))
+			var gneg G2Jac
+			gneg.Neg(&g2Gen)
+
+			return op1.Equal(&g2Infinity) && op2.Equal(&g2Infinity) && op3.Equal(&g2Infinity) && op4.Equal(&gneg)
+		},
+		genScalar,
+	))
+
 	properties.Property("[BLS12-377] psi should map points from E' to itself", prop.ForAll(
 		func() bool {
 			var a G2Jac
@@ -437,6 +479,40 @@ func TestG2AffineOps(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+// TestG2JacScalarMultiplicationConstantTimeLeakage runs a dudect-style
+// fixed-vs-random timing test on ScalarMultiplicationConstantTime. It is slow
+// and sensitive to the environment, so it only runs when DUDECT_MEASUREMENTS is
+// set to the number of measurements to perform.
+func TestG2JacScalarMultiplicationConstantTimeLeakage(t *testing.T) {
+	nbMeasurements, err := strconv.Atoi(os.Getenv("DUDECT_MEASUREMENTS"))
+	if err != nil || nbMeasurements <= 0 {
+		t.Skip("set DUDECT_MEASUREMENTS to run the timing leakage test")
+	}
+
+	// fix-vs-random: the fixed scalar is drawn once, so that both classes have
+	// the same size
+	var fixedFr fr.Element
+	fixedFr.SetRandom()
+	fixed := fixedFr.BigInt(new(big.Int))
+	scalars := make([]big.Int, nbMeasurements)
+	var res G2Jac
+	tStat := dudect.Run(nbMeasurements, 0.9, func(i, class int) {
+		if class == 0 {
+			scalars[i].Set(fixed)
+			return
+		}
+		var r fr.Element
+		r.SetRandom()
+		r.BigInt(&scalars[i])
+	}, func(i int) {
+		res.ScalarMultiplicationConstantTime(&g2Gen, &scalars[i])
+	})
+	t.Logf("t = %f", tStat)
+	if math.Abs(tStat) > dudect.Threshold {
+		t.Fatalf("timing leak detected, |t| = %f > %d", math.Abs(tStat), dudect.Threshold)
+	}
+}
+
 func TestG2AffineCofactorCleaning(t *testing.T) {
 	t.Parallel()
 	parameters := gopter.DefaultTestParameters()
@@ -659,6 +735,14 @@ func BenchmarkG2JacScalarMultiplication(b *testing.B) {
 		}
 	})
 
+	var constantTime G2Jac
+	b.Run("constant time", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			constantTime.ScalarMultiplicationConstantTime(&g2Gen, &scalar)
+		}
+	})
+
 }
 
 func BenchmarkG2AffineCofactorClearing(b *testing.B) {
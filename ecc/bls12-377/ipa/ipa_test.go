@@ -0,0 +1,279 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/utils"
+)
+
+const srsSize = 32
+
+var testSeed = []byte("gnark-crypto ipa test seed")
+
+func randomPolynomial(size int) []fr.Element {
+	p := make([]fr.Element, size)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	return p
+}
+
+func eval(p []fr.Element, point fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &point).Add(&res, &p[i])
+	}
+	return res
+}
+
+func TestNewSRS(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+	assert.Equal(srsSize, len(srs.G))
+
+	// the setup is deterministic
+	_srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+	assert.Equal(srs.G, _srs.G)
+	assert.True(srs.Q.Equal(&_srs.Q))
+
+	// and the bases are distinct
+	for i := range srs.G {
+		assert.False(srs.G[i].Equal(&srs.Q))
+		for j := i + 1; j < len(srs.G); j++ {
+			assert.False(srs.G[i].Equal(&srs.G[j]))
+		}
+	}
+
+	// another seed yields other bases
+	_srs, err = NewSRS(srsSize, []byte("another seed"))
+	assert.NoError(err)
+	assert.False(srs.G[0].Equal(&_srs.G[0]))
+
+	_, err = NewSRS(srsSize+1, testSeed)
+	assert.ErrorIs(err, ErrInvalidSRSSize)
+	_, err = NewSRS(0, testSeed)
+	assert.ErrorIs(err, ErrInvalidSRSSize)
+}
+
+func TestCommit(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+
+	// the commitment is linear
+	p, q := randomPolynomial(srsSize), randomPolynomial(srsSize-3)
+	sum := make([]fr.Element, srsSize)
+	for i := range p {
+		sum[i] = p[i]
+		if i < len(q) {
+			sum[i].Add(&sum[i], &q[i])
+		}
+	}
+	dp, err := Commit(p, srs)
+	assert.NoError(err)
+	dq, err := Commit(q, srs)
+	assert.NoError(err)
+	dsum, err := Commit(sum, srs)
+	assert.NoError(err)
+	dp.Add(&dp, &dq)
+	assert.True(dp.Equal(&dsum))
+
+	_, err = Commit(randomPolynomial(srsSize+1), srs)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = Commit(nil, srs)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestOpen(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+
+	hf := sha256.New()
+	for _, size := range []int{1, 7, srsSize} {
+		p := randomPolynomial(size)
+		digest, err := Commit(p, srs)
+		assert.NoError(err)
+
+		var point fr.Element
+		point.SetRandom()
+		proof, err := Open(p, digest, point, hf, srs, []byte("data"))
+		assert.NoError(err)
+		assert.Equal(eval(p, point), proof.ClaimedValue)
+		assert.Equal(5, len(proof.L))
+
+		assert.NoError(Verify(&digest, &proof, point, hf, srs, []byte("data")))
+
+		// wrong claimed value
+		{
+			_proof := proof
+			_proof.ClaimedValue.SetRandom()
+			assert.ErrorIs(Verify(&digest, &_proof, point, hf, srs, []byte("data")), ErrVerifyOpeningProof)
+		}
+
+		// wrong folded polynomial
+		{
+			_proof := proof
+			_proof.A.SetRandom()
+			assert.ErrorIs(Verify(&digest, &_proof, point, hf, srs, []byte("data")), ErrVerifyOpeningProof)
+		}
+
+		// wrong point
+		{
+			var _point fr.Element
+			_point.SetRandom()
+			assert.Error(Verify(&digest, &proof, _point, hf, srs, []byte("data")))
+		}
+
+		// wrong transcript
+		assert.Error(Verify(&digest, &proof, point, hf, srs, []byte("other data")))
+
+		// wrong cross term
+		{
+			_proof := proof
+			_proof.L = make([]Digest, len(proof.L))
+			copy(_proof.L, proof.L)
+			_proof.L[2] = proof.R[2]
+			assert.Error(Verify(&digest, &_proof, point, hf, srs, []byte("data")))
+		}
+
+		// wrong number of rounds
+		{
+			_proof := proof
+			_proof.L = proof.L[1:]
+			assert.ErrorIs(Verify(&digest, &_proof, point, hf, srs, []byte("data")), ErrInvalidNbRounds)
+		}
+	}
+
+	_, err = Open(randomPolynomial(srsSize+1), Digest{}, fr.One(), hf, srs)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestBatchVerify(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+
+	hf := sha256.New()
+	const nbProofs = 5
+	digests := make([]Digest, nbProofs)
+	proofs := make([]OpeningProof, nbProofs)
+	points := make([]fr.Element, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		p := randomPolynomial(srsSize - i)
+		digests[i], err = Commit(p, srs)
+		assert.NoError(err)
+		points[i].SetRandom()
+		proofs[i], err = Open(p, digests[i], points[i], hf, srs)
+		assert.NoError(err)
+	}
+
+	assert.NoError(BatchVerify(digests, proofs, points, hf, srs))
+
+	// a single wrong proof makes the batch fail
+	proofs[3].ClaimedValue.SetRandom()
+	assert.ErrorIs(BatchVerify(digests, proofs, points, hf, srs), ErrVerifyOpeningProof)
+
+	assert.ErrorIs(BatchVerify(digests[1:], proofs, points, hf, srs), ErrInvalidNbProofs)
+}
+
+func TestSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+
+	p := randomPolynomial(srsSize)
+	digest, err := Commit(p, srs)
+	assert.NoError(err)
+	proof, err := Open(p, digest, fr.One(), sha256.New(), srs)
+	assert.NoError(err)
+
+	t.Run("SRS round-trip", utils.SerializationRoundTrip(srs))
+	t.Run("SRS raw round-trip", utils.SerializationRoundTripRaw(srs))
+	t.Run("opening proof round-trip", utils.SerializationRoundTrip(&proof))
+
+	// the number of bases must be a non-zero power of two
+	for _, size := range []int{0, 3, srsSize - 1} {
+		_srs := SRS{G: srs.G[:size], Q: srs.Q}
+		var buf bytes.Buffer
+		_, err := _srs.WriteTo(&buf)
+		assert.NoError(err)
+		var decoded SRS
+		_, err = decoded.ReadFrom(&buf)
+		assert.ErrorIs(err, ErrInvalidSRSSize)
+	}
+}
+
+func BenchmarkOpen(b *testing.B) {
+	const size = 1 << 10
+	srs, err := NewSRS(size, testSeed)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(size)
+	digest, err := Commit(p, srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var point fr.Element
+	point.SetRandom()
+	hf := sha256.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Open(p, digest, point, hf, srs)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	const size = 1 << 10
+	srs, err := NewSRS(size, testSeed)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(size)
+	digest, err := Commit(p, srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var point fr.Element
+	point.SetRandom()
+	hf := sha256.New()
+	proof, err := Open(p, digest, point, hf, srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(&digest, &proof, point, hf, srs)
+	}
+}
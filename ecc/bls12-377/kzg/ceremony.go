@@ -0,0 +1,360 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidPowersOfTau  = errors.New("invalid powers of tau")
+	ErrInvalidContribution = errors.New("invalid contribution")
+)
+
+// powersOfTauDST is the domain separation tag of the hash to G1 of the proofs
+// of knowledge of the contributions.
+const powersOfTauDST = "GNARK-CRYPTO-KZG-BLS12-377-POWERS-OF-TAU-POK"
+
+// PowersOfTau is the state of a powers-of-tau ceremony, in which participants
+// successively contribute a secret to build an SRS whose secret τ (the product
+// of the contributions) is unknown as long as one of them erased its secret.
+//
+// A ceremony starts from NewPowersOfTau (τ = 1). Each participant calls
+// Contribute on the current state and publishes the new state with its
+// Contribution. The chain of contributions is checked with VerifyContributions,
+// and the result is converted to an SRS with SRS.
+//
+// implements io.ReaderFrom and io.WriterTo
+type PowersOfTau struct {
+	G1 []bls12377.G1Affine // [G₁, [τ]G₁, [τ²]G₁, ...]
+	G2 []bls12377.G2Affine // [G₂, [τ]G₂, [τ²]G₂, ...]
+}
+
+// Contribution is the public record of a contribution x to a powers-of-tau
+// ceremony, which multiplies τ by x.
+//
+// implements io.ReaderFrom and io.WriterTo
+type Contribution struct {
+	RunningProduct bls12377.G1Affine // [τ]G₁ after the contribution
+	PubKey         bls12377.G2Affine // [x]G₂
+	PoK            bls12377.G1Affine // [x]S, S = hash(previous running product, RunningProduct, PubKey)
+}
+
+// NewPowersOfTau returns the initial state of a ceremony producing nG1 powers
+// in G1 and nG2 powers in G2, that is all the powers set to the generators.
+func NewPowersOfTau(nG1, nG2 int) (*PowersOfTau, error) {
+	if nG1 < 2 || nG2 < 2 {
+		return nil, ErrMinSRSSize
+	}
+	_, _, gen1Aff, gen2Aff := bls12377.Generators()
+	p := &PowersOfTau{
+		G1: make([]bls12377.G1Affine, nG1),
+		G2: make([]bls12377.G2Affine, nG2),
+	}
+	for i := range p.G1 {
+		p.G1[i] = gen1Aff
+	}
+	for i := range p.G2 {
+		p.G2[i] = gen2Aff
+	}
+	return p, nil
+}
+
+// Contribute updates p with a random secret x, erased before returning:
+// [τⁱ]G becomes [(τx)ⁱ]G. It returns the record of the contribution.
+func (p *PowersOfTau) Contribute() (Contribution, error) {
+	var x fr.Element
+	for x.IsZero() {
+		if _, err := x.SetRandom(); err != nil {
+			return Contribution{}, err
+		}
+	}
+	c := p.contribute(&x)
+	x.SetZero()
+	return c, nil
+}
+
+func (p *PowersOfTau) contribute(x *fr.Element) Contribution {
+	var c Contribution
+	previous := p.G1[1]
+
+	// powers of x
+	n := len(p.G1)
+	if len(p.G2) > n {
+		n = len(p.G2)
+	}
+	xs := make([]fr.Element, n)
+	xs[0].SetOne()
+	for i := 1; i < n; i++ {
+		xs[i].Mul(&xs[i-1], x)
+	}
+
+	// the powers are secret: use the constant-time scalar multiplications
+	g1 := make([]bls12377.G1Jac, len(p.G1))
+	parallel.Execute(len(p.G1), func(start, end int) {
+		var b big.Int
+		var t bls12377.G1Jac
+		for i := start; i < end; i++ {
+			t.FromAffine(&p.G1[i])
+			g1[i].ScalarMultiplicationConstantTime(&t, xs[i].BigInt(&b))
+		}
+	})
+	copy(p.G1, bls12377.BatchJacobianToAffineG1(g1))
+	parallel.Execute(len(p.G2), func(start, end int) {
+		var b big.Int
+		for i := start; i < end; i++ {
+			p.G2[i].ScalarMultiplicationConstantTime(&p.G2[i], xs[i].BigInt(&b))
+		}
+	})
+	for i := range xs {
+		xs[i].SetZero()
+	}
+
+	var b big.Int
+	x.BigInt(&b)
+	c.RunningProduct = p.G1[1]
+	c.PubKey.ScalarMultiplicationBaseConstantTime(&b)
+	s := powersOfTauPoKBase(&previous, &c.RunningProduct, &c.PubKey)
+	c.PoK.ScalarMultiplicationConstantTime(&s, &b)
+	b.SetUint64(0)
+	return c
+}
+
+// Verify checks that p is a well-formed powers-of-tau: the points are in the
+// prime order subgroups, the first ones are the generators, and the G1 and G2
+// points are the successive powers of the same τ. The powers are checked
+// together with random linear combinations, with 2 pairings.
+func (p *PowersOfTau) Verify() error {
+	if len(p.G1) < 2 || len(p.G2) < 2 {
+		return ErrInvalidPowersOfTau
+	}
+	_, _, gen1Aff, gen2Aff := bls12377.Generators()
+	if !p.G1[0].Equal(&gen1Aff) || !p.G2[0].Equal(&gen2Aff) {
+		return ErrInvalidPowersOfTau
+	}
+	for i := range p.G1 {
+		if p.G1[i].IsInfinity() || !p.G1[i].IsInSubGroup() {
+			return ErrInvalidPowersOfTau
+		}
+	}
+	for i := range p.G2 {
+		if p.G2[i].IsInfinity() || !p.G2[i].IsInSubGroup() {
+			return ErrInvalidPowersOfTau
+		}
+	}
+
+	// with r random, e(∑ rⁱ[τⁱ]G₁, [τ]G₂) == e(∑ rⁱ[τⁱ⁺¹]G₁, G₂)
+	var r fr.Element
+	if _, err := r.SetRandom(); err != nil {
+		return err
+	}
+	n := len(p.G1)
+	if len(p.G2) > n {
+		n = len(p.G2)
+	}
+	rs := make([]fr.Element, n-1)
+	rs[0].SetOne()
+	for i := 1; i < len(rs); i++ {
+		rs[i].Mul(&rs[i-1], &r)
+	}
+	config := ecc.MultiExpConfig{}
+
+	var l1, r1 bls12377.G1Affine
+	if _, err := l1.MultiExp(p.G1[:len(p.G1)-1], rs[:len(p.G1)-1], config); err != nil {
+		return err
+	}
+	if _, err := r1.MultiExp(p.G1[1:], rs[:len(p.G1)-1], config); err != nil {
+		return err
+	}
+	if !sameRatio(&l1, &r1, &p.G2[0], &p.G2[1]) {
+		return ErrInvalidPowersOfTau
+	}
+
+	// e([τ]G₁, ∑ rⁱ[τⁱ]G₂) == e(G₁, ∑ rⁱ[τⁱ⁺¹]G₂)
+	var l2, r2 bls12377.G2Affine
+	if _, err := l2.MultiExp(p.G2[:len(p.G2)-1], rs[:len(p.G2)-1], config); err != nil {
+		return err
+	}
+	if _, err := r2.MultiExp(p.G2[1:], rs[:len(p.G2)-1], config); err != nil {
+		return err
+	}
+	if !sameRatio(&p.G1[0], &p.G1[1], &l2, &r2) {
+		return ErrInvalidPowersOfTau
+	}
+	return nil
+}
+
+// VerifyContribution checks that next is the well-formed result of the
+// contribution c to previous.
+func VerifyContribution(previous, next *PowersOfTau, c *Contribution) error {
+	if len(previous.G1) != len(next.G1) || len(previous.G2) != len(next.G2) || len(next.G1) < 2 {
+		return ErrInvalidPowersOfTau
+	}
+	if !next.G1[1].Equal(&c.RunningProduct) {
+		return ErrInvalidContribution
+	}
+	if err := verifyContribution(&previous.G1[1], c, true); err != nil {
+		return err
+	}
+	return next.Verify()
+}
+
+// VerifyContributions checks that p is the well-formed result of the
+// successive contributions, starting from NewPowersOfTau.
+func VerifyContributions(p *PowersOfTau, contributions []Contribution) error {
+	return verifyContributions(p, contributions, true)
+}
+
+func verifyContributions(p *PowersOfTau, contributions []Contribution, withPoK bool) error {
+	if len(p.G1) < 2 {
+		return ErrInvalidPowersOfTau
+	}
+	_, _, previous, _ := bls12377.Generators()
+	for i := range contributions {
+		if err := verifyContribution(&previous, &contributions[i], withPoK); err != nil {
+			return err
+		}
+		previous = contributions[i].RunningProduct
+	}
+	if !p.G1[1].Equal(&previous) {
+		return ErrInvalidContribution
+	}
+	return p.Verify()
+}
+
+// verifyContribution checks that c.RunningProduct = [x]previous where
+// c.PubKey = [x]G₂, and the proof of knowledge of x if withPoK is set.
+func verifyContribution(previous *bls12377.G1Affine, c *Contribution, withPoK bool) error {
+	if c.RunningProduct.IsInfinity() || !c.RunningProduct.IsInSubGroup() ||
+		c.PubKey.IsInfinity() || !c.PubKey.IsInSubGroup() {
+		return ErrInvalidContribution
+	}
+	_, _, _, gen2Aff := bls12377.Generators()
+
+	// e(running product, G₂) == e(previous running product, [x]G₂)
+	if !sameRatio(previous, &c.RunningProduct, &gen2Aff, &c.PubKey) {
+		return ErrInvalidContribution
+	}
+	if !withPoK {
+		return nil
+	}
+	// e([x]S, G₂) == e(S, [x]G₂)
+	if !c.PoK.IsInSubGroup() {
+		return ErrInvalidContribution
+	}
+	s := powersOfTauPoKBase(previous, &c.RunningProduct, &c.PubKey)
+	if !sameRatio(&s, &c.PoK, &gen2Aff, &c.PubKey) {
+		return ErrInvalidContribution
+	}
+	return nil
+}
+
+// SRS returns the SRS defined by the powers of tau.
+func (p *PowersOfTau) SRS() (*SRS, error) {
+	if len(p.G1) < 2 || len(p.G2) < 2 {
+		return nil, ErrMinSRSSize
+	}
+	var srs SRS
+	srs.Pk.G1 = make([]bls12377.G1Affine, len(p.G1))
+	copy(srs.Pk.G1, p.G1)
+	srs.Vk.G1 = p.G1[0]
+	srs.Vk.G2[0] = p.G2[0]
+	srs.Vk.G2[1] = p.G2[1]
+	srs.Vk.Lines[0] = bls12377.PrecomputeLines(srs.Vk.G2[0])
+	srs.Vk.Lines[1] = bls12377.PrecomputeLines(srs.Vk.G2[1])
+	return &srs, nil
+}
+
+// sameRatio returns true if e(a1, b2) == e(a2, b1), that is if a2 = [x]a1 and
+// b2 = [x]b1 for some x.
+func sameRatio(a1, a2 *bls12377.G1Affine, b1, b2 *bls12377.G2Affine) bool {
+	var na2 bls12377.G1Affine
+	na2.Neg(a2)
+	ok, err := bls12377.PairingCheck([]bls12377.G1Affine{*a1, na2}, []bls12377.G2Affine{*b2, *b1})
+	return err == nil && ok
+}
+
+// powersOfTauPoKBase returns the point S of the proof of knowledge of a
+// contribution, hashed from the previous and new running products and the
+// public key of the contribution.
+func powersOfTauPoKBase(previous, runningProduct *bls12377.G1Affine, pubKey *bls12377.G2Affine) bls12377.G1Affine {
+	b1 := previous.Bytes()
+	b2 := runningProduct.Bytes()
+	b3 := pubKey.Bytes()
+	msg := make([]byte, 0, len(b1)+len(b2)+len(b3))
+	msg = append(msg, b1[:]...)
+	msg = append(msg, b2[:]...)
+	msg = append(msg, b3[:]...)
+	s, err := bls12377.HashToG1(msg, []byte(powersOfTauDST))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// WriteTo writes the binary encoding of the powers of tau
+func (p *PowersOfTau) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+	for _, v := range []interface{}{p.G1, p.G2} {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes the powers of tau from reader. The points are checked to be
+// in the prime order subgroups, see Verify for the other checks.
+func (p *PowersOfTau) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+	for _, v := range []interface{}{&p.G1, &p.G2} {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes the binary encoding of the contribution
+func (c *Contribution) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+	for _, v := range []interface{}{&c.RunningProduct, &c.PubKey, &c.PoK} {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes the contribution from reader
+func (c *Contribution) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+	for _, v := range []interface{}{&c.RunningProduct, &c.PubKey, &c.PoK} {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	return dec.BytesRead(), nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPowersOfTau(t *testing.T) {
+	const nG1, nG2 = 17, 3
+
+	p, err := NewPowersOfTau(nG1, nG2)
+	require.NoError(t, err)
+	require.NoError(t, p.Verify())
+
+	// 3 contributions, the second one deterministic: τ = x₁⋅5⋅x₃
+	var contributions []Contribution
+	var previous PowersOfTau
+	for i := 0; i < 3; i++ {
+		previous.G1 = append([]bls12377.G1Affine(nil), p.G1...)
+		previous.G2 = append([]bls12377.G2Affine(nil), p.G2...)
+		var c Contribution
+		if i == 1 {
+			var x fr.Element
+			x.SetUint64(5)
+			c = p.contribute(&x)
+		} else {
+			c, err = p.Contribute()
+			require.NoError(t, err)
+		}
+		require.NoError(t, VerifyContribution(&previous, p, &c))
+		contributions = append(contributions, c)
+	}
+	require.NoError(t, VerifyContributions(p, contributions))
+
+	// the SRS commits and opens
+	srs, err := p.SRS()
+	require.NoError(t, err)
+	var f [nG1]fr.Element
+	for i := range f {
+		f[i].SetRandom()
+	}
+	digest, err := Commit(f[:], srs.Pk)
+	require.NoError(t, err)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := Open(f[:], point, srs.Pk)
+	require.NoError(t, err)
+	require.NoError(t, Verify(&digest, &proof, point, srs.Vk))
+
+	t.Run("invalid contributions", func(t *testing.T) {
+		// missing contribution
+		assert.ErrorIs(t, VerifyContributions(p, contributions[1:]), ErrInvalidContribution)
+
+		// invalid proof of knowledge
+		bad := append([]Contribution(nil), contributions...)
+		bad[1].PoK.Double(&bad[1].PoK)
+		assert.ErrorIs(t, VerifyContributions(p, bad), ErrInvalidContribution)
+
+		// public key inconsistent with the running product
+		bad = append([]Contribution(nil), contributions...)
+		bad[2].PubKey.Double(&bad[2].PubKey)
+		assert.ErrorIs(t, VerifyContributions(p, bad), ErrInvalidContribution)
+	})
+
+	t.Run("invalid powers", func(t *testing.T) {
+		for _, tamper := range []func(q *PowersOfTau){
+			func(q *PowersOfTau) { q.G1[7].Double(&q.G1[7]) },
+			func(q *PowersOfTau) { q.G1[nG1-1].Double(&q.G1[nG1-1]) },
+			func(q *PowersOfTau) { q.G2[2].Double(&q.G2[2]) },
+			func(q *PowersOfTau) { q.G1[0].Double(&q.G1[0]) },
+		} {
+			q := PowersOfTau{
+				G1: append([]bls12377.G1Affine(nil), p.G1...),
+				G2: append([]bls12377.G2Affine(nil), p.G2...),
+			}
+			tamper(&q)
+			assert.ErrorIs(t, q.Verify(), ErrInvalidPowersOfTau)
+		}
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		var buf bytes.Buffer
+		written, err := p.WriteTo(&buf)
+		require.NoError(t, err)
+		var q PowersOfTau
+		read, err := q.ReadFrom(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, written, read)
+		assert.Equal(t, p, &q)
+
+		buf.Reset()
+		written, err = contributions[0].WriteTo(&buf)
+		require.NoError(t, err)
+		var c Contribution
+		read, err = c.ReadFrom(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, written, read)
+		assert.Equal(t, contributions[0], c)
+	})
+}
+
+func TestPowersOfTauDeterministic(t *testing.T) {
+	p, err := NewPowersOfTau(4, 2)
+	require.NoError(t, err)
+	var x fr.Element
+	x.SetUint64(3)
+	p.contribute(&x)
+	x.SetUint64(7)
+	p.contribute(&x)
+
+	// τ = 21
+	_, _, gen1Aff, gen2Aff := bls12377.Generators()
+	tau := big.NewInt(21)
+	var expected bls12377.G1Affine
+	var e big.Int
+	for i := range p.G1 {
+		e.Exp(tau, big.NewInt(int64(i)), nil)
+		expected.ScalarMultiplication(&gen1Aff, &e)
+		assert.True(t, expected.Equal(&p.G1[i]))
+	}
+	var expectedG2 bls12377.G2Affine
+	expectedG2.ScalarMultiplication(&gen2Aff, tau)
+	assert.True(t, expectedG2.Equal(&p.G2[1]))
+}
+
+func BenchmarkPowersOfTauContribute(b *testing.B) {
+	p, _ := NewPowersOfTau(1<<10, 2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = p.Contribute()
+	}
+}
+
+func BenchmarkPowersOfTauVerify(b *testing.B) {
+	p, _ := NewPowersOfTau(1<<10, 2)
+	_, _ = p.Contribute()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = p.Verify()
+	}
+}
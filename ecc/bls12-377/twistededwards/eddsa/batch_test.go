@@ -0,0 +1,106 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/hash"
+)
+
+func batchOfSignatures(tb testing.TB, n int) ([]PublicKey, [][]byte, [][]byte) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine here
+	hFunc := hash.MIMC_BLS12_377.New()
+
+	pubs := make([]PublicKey, n)
+	sigs := make([][]byte, n)
+	msgs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		pubs[i] = privKey.PublicKey
+		var frMsg fr.Element
+		frMsg.SetUint64(uint64(i))
+		msgs[i] = frMsg.Marshal()
+		if sigs[i], err = privKey.Sign(msgs[i], hFunc); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return pubs, sigs, msgs
+}
+
+func TestBatchVerify(t *testing.T) {
+	const n = 20
+	pubs, sigs, msgs := batchOfSignatures(t, n)
+	hFunc := hash.MIMC_BLS12_377.New()
+
+	ok, invalid, err := BatchVerify(pubs, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || invalid != -1 {
+		t.Fatal("valid batch should verify")
+	}
+
+	// a single signature is invalid
+	msgs[7], msgs[8] = msgs[8], msgs[7]
+	ok, invalid, err = BatchVerify(pubs, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok || invalid != 7 {
+		t.Fatalf("expected signature 7 to be invalid, got %d", invalid)
+	}
+	msgs[7], msgs[8] = msgs[8], msgs[7]
+
+	// malformed signature
+	sigs[3] = sigs[3][1:]
+	if _, invalid, err = BatchVerify(pubs, sigs, msgs, hFunc); err == nil || invalid != 3 {
+		t.Fatal("expected an error for a malformed signature")
+	}
+
+	// empty batch and wrong sizes
+	if ok, _, err := BatchVerify(nil, nil, nil, hFunc); !ok || err != nil {
+		t.Fatal("empty batch should verify")
+	}
+	if _, _, err := BatchVerify(pubs, sigs[1:], msgs, hFunc); err != errBatchSize {
+		t.Fatal("expected an error for mismatched sizes")
+	}
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	const n = 256
+	pubs, sigs, msgs := batchOfSignatures(b, n)
+	hFunc := hash.MIMC_BLS12_377.New()
+
+	b.Run("BatchVerify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchVerify(pubs, sigs, msgs, hFunc)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range pubs {
+				pubs[j].Verify(sigs[j], msgs[j], hFunc)
+			}
+		}
+	})
+}
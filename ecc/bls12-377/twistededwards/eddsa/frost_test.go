@@ -0,0 +1,191 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/sha256"
+	"errors"
+	gohash "hash"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+	"github.com/consensys/gnark-crypto/hash"
+)
+
+// frostSign runs the two rounds of the signature with the given signers, and
+// returns the aggregated signature.
+func frostSign(t *testing.T, r *rand.Rand, signers []KeyShare, commitment []twistededwards.PointAffine, message []byte, hFunc gohash.Hash) []byte {
+	nonces := make([]*SigningNonces, len(signers))
+	commitments := make([]NonceCommitment, len(signers))
+	for i := range signers {
+		var err error
+		nonces[i], commitments[i], err = signers[i].Commit(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the order of the commitments doesn't matter
+	r.Shuffle(len(commitments), func(i, j int) {
+		commitments[i], commitments[j] = commitments[j], commitments[i]
+	})
+
+	shares := make([]SignatureShare, len(signers))
+	for i := range signers {
+		var err error
+		shares[i], err = signers[i].SignShare(nonces[i], commitments, message, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vs := ComputeVerificationShare(commitment, signers[i].ID)
+		if err := VerifySignatureShare(&shares[i], &vs, &signers[i].GroupKey, commitments, message, hFunc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// nonces can't be reused
+	if _, err := signers[0].SignShare(nonces[0], commitments, message, hFunc); err != errNoncesUsed {
+		t.Fatal("expected an error when reusing nonces")
+	}
+
+	sig, err := Aggregate(&signers[0].GroupKey, commitments, shares, message, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestFROSTTrustedDealer(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine here
+
+	const threshold, n = 3, 5
+	shares, commitment, err := GenerateKeyShares(r, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range shares {
+		if err := VerifyKeyShare(&shares[i], commitment); err != nil {
+			t.Fatal(err)
+		}
+	}
+	groupKey := shares[0].GroupKey
+
+	var frMsg fr.Element
+	frMsg.SetRandom()
+	msg := frMsg.Marshal()
+	hFunc := hash.MIMC_BLS12_377.New()
+
+	// any threshold of signers
+	for _, signers := range [][]int{{0, 1, 2}, {4, 0, 3}, {1, 2, 3, 4}, {0, 1, 2, 3, 4}} {
+		keyShares := make([]KeyShare, len(signers))
+		for i, s := range signers {
+			keyShares[i] = shares[s]
+		}
+		sig := frostSign(t, r, keyShares, commitment, msg, hFunc)
+		ok, err := groupKey.Verify(sig, msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("signature of %v doesn't verify", signers)
+		}
+
+		// wrong message
+		frMsg.SetRandom()
+		if ok, _ := groupKey.Verify(sig, frMsg.Marshal(), hFunc); ok {
+			t.Fatal("signature verifies for a wrong message")
+		}
+	}
+
+	// less than threshold signers
+	sig := frostSign(t, r, shares[:threshold-1], commitment, msg, hFunc)
+	if ok, _ := groupKey.Verify(sig, msg, hFunc); ok {
+		t.Fatal("signature with less than threshold signers verifies")
+	}
+
+	// tampered key share
+	tampered := shares[0]
+	tampered.Secret.SetUint64(42)
+	if err := VerifyKeyShare(&tampered, commitment); err != errInvalidKeyShare {
+		t.Fatal("expected an error for a tampered key share")
+	}
+
+	if _, _, err := GenerateKeyShares(r, n+1, n); err != errInvalidThreshold {
+		t.Fatal("expected an error for an invalid threshold")
+	}
+}
+
+func TestFROSTDKG(t *testing.T) {
+	r := rand.New(rand.NewSource(0)) //#nosec G404 weak rng is fine here
+
+	const threshold, n = 2, 3
+	participants := make([]*DKGParticipant, n)
+	round1 := make([]DKGRound1, n)
+	for i := range participants {
+		var err error
+		participants[i], round1[i], err = NewDKGParticipant(r, uint64(i+1), threshold, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	received := make([][]DKGShare, n)
+	for i := range participants {
+		shares, err := participants[i].Round2(round1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range shares {
+			received[s.To-1] = append(received[s.To-1], s)
+		}
+	}
+
+	// a wrong share is detected, and its sender identified
+	wrong := make([]DKGShare, len(received[0]))
+	copy(wrong, received[0])
+	wrong[1].Value = *big.NewInt(1)
+	if _, err := participants[0].Finalize(round1, wrong); !errors.Is(err, errInvalidSecretShare) {
+		t.Fatal("expected an error for a wrong share")
+	}
+
+	keyShares := make([]KeyShare, n)
+	for i := range participants {
+		var err error
+		if keyShares[i], err = participants[i].Finalize(round1, received[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitment := DKGCommitment(round1)
+	for i := range keyShares {
+		if err := VerifyKeyShare(&keyShares[i], commitment); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hFunc := sha256.New()
+	msg := []byte("message")
+	sig := frostSign(t, r, keyShares[1:], commitment, msg, hFunc)
+	ok, err := keyShares[0].GroupKey.Verify(sig, msg, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("signature doesn't verify")
+	}
+}
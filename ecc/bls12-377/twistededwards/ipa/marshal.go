@@ -0,0 +1,84 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/twistededwards"
+)
+
+const sizeFr = fr.Bytes
+
+var errWrongSize = errors.New("wrong size buffer")
+var errNotInSubGroup = errors.New("point not in the prime order subgroup")
+var errScalarNotReduced = errors.New("scalar is not reduced modulo the order of the curve")
+
+// Bytes returns the binary representation of the proof, as
+// L₀||…||Lₖ₋₁||R₀||…||Rₖ₋₁||A||ClaimedValue, where the points are compressed
+// and the scalars are big endian integers of size fr.Bytes.
+func (proof *OpeningProof) Bytes() []byte {
+	nbRounds := len(proof.L)
+	res := make([]byte, (2*nbRounds+2)*sizeFr)
+	for i := 0; i < nbRounds; i++ {
+		bL, bR := proof.L[i].Bytes(), proof.R[i].Bytes()
+		copy(res[i*sizeFr:], bL[:])
+		copy(res[(nbRounds+i)*sizeFr:], bR[:])
+	}
+	proof.A.FillBytes(res[2*nbRounds*sizeFr : (2*nbRounds+1)*sizeFr])
+	proof.ClaimedValue.FillBytes(res[(2*nbRounds+1)*sizeFr:])
+	return res
+}
+
+// SetBytes sets proof from its binary representation in buf, as returned by
+// Bytes. The number of rounds is deduced from the size of buf, and the points
+// must be in the prime order subgroup.
+// It returns the number of bytes read from the buffer.
+func (proof *OpeningProof) SetBytes(buf []byte) (int, error) {
+	if len(buf) < 2*sizeFr || len(buf)%(2*sizeFr) != 0 {
+		return 0, errWrongSize
+	}
+	nbRounds := len(buf)/(2*sizeFr) - 1
+
+	proof.L = make([]twistededwards.PointAffine, nbRounds)
+	proof.R = make([]twistededwards.PointAffine, nbRounds)
+	curve := twistededwards.GetEdwardsCurve()
+	n := 0
+	for _, points := range [][]twistededwards.PointAffine{proof.L, proof.R} {
+		for i := range points {
+			if _, err := points[i].SetBytes(buf[n : n+sizeFr]); err != nil {
+				return n, err
+			}
+			if !isInSubGroup(&points[i], &curve.Order) {
+				return n, errNotInSubGroup
+			}
+			n += sizeFr
+		}
+	}
+
+	for _, s := range []*big.Int{&proof.A, &proof.ClaimedValue} {
+		s.SetBytes(buf[n : n+sizeFr])
+		if s.Cmp(&curve.Order) >= 0 {
+			return n, errScalarNotReduced
+		}
+		n += sizeFr
+	}
+
+	return n, nil
+}
@@ -19,15 +19,22 @@ package ecdsa
 import (
 	"crypto/subtle"
 	"errors"
-	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
 	"io"
 	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
 )
 
 var errWrongSize = errors.New("wrong size buffer")
 var errRBiggerThanRMod = errors.New("r >= r_mod")
 var errSBiggerThanRMod = errors.New("s >= r_mod")
 var errZero = errors.New("zero value")
+var errInvalidDER = errors.New("invalid DER encoding")
+
+// halfOrder is ⌊order/2⌋, the largest low-s value.
+var halfOrder = new(big.Int).Rsh(order, 1)
 
 // Bytes returns the binary representation of the public key
 // follows https://tools.ietf.org/html/rfc8032#section-3.1
@@ -134,3 +141,70 @@ func (sig *Signature) SetBytes(buf []byte) (int, error) {
 	n += sizeFr
 	return n, nil
 }
+
+// BytesDER returns the DER encoding of sig, as the ASN.1 structure
+//
+//	ECDSA-Sig-Value ::= SEQUENCE { r INTEGER, s INTEGER }
+//
+// used by X.509, HSMs and Bitcoin.
+func (sig *Signature) BytesDER() []byte {
+	r := new(big.Int).SetBytes(sig.R[:])
+	s := new(big.Int).SetBytes(sig.S[:])
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(r)
+		b.AddASN1BigInt(s)
+	})
+	return b.BytesOrPanic()
+}
+
+// SetBytesDER sets sig from its DER encoding (see BytesDER). The encoding
+// must be strict (minimal integers, no trailing data), and r, s must be in
+// [1, order).
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytesDER(buf []byte) (int, error) {
+	r, s := new(big.Int), new(big.Int)
+	input := cryptobyte.String(buf)
+	var inner cryptobyte.String
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Integer(r) ||
+		!inner.ReadASN1Integer(s) ||
+		!inner.Empty() {
+		return 0, errInvalidDER
+	}
+	if r.Sign() <= 0 || s.Sign() <= 0 {
+		return 0, errZero
+	}
+	if r.Cmp(order) >= 0 {
+		return 0, errRBiggerThanRMod
+	}
+	if s.Cmp(order) >= 0 {
+		return 0, errSBiggerThanRMod
+	}
+	r.FillBytes(sig.R[:])
+	s.FillBytes(sig.S[:])
+	return len(buf), nil
+}
+
+// IsLowS returns true if s ≤ order/2. Since (r, s) and (r, order-s) are both
+// valid signatures, some protocols (Bitcoin, Ethereum) only accept the low-s
+// one to prevent malleability.
+func (sig *Signature) IsLowS() bool {
+	s := new(big.Int).SetBytes(sig.S[:])
+	return s.Cmp(halfOrder) <= 0
+}
+
+// NormalizeS replaces s by order-s if s > order/2, so that the signature is
+// low-s, and returns true if s was replaced. The signature is still valid,
+// but for the opposite of the point R, so the y-parity bit of the recovery
+// information, if any, must be flipped.
+func (sig *Signature) NormalizeS() bool {
+	s := new(big.Int).SetBytes(sig.S[:])
+	if s.Cmp(halfOrder) <= 0 {
+		return false
+	}
+	s.Sub(order, s)
+	s.FillBytes(sig.S[:])
+	return true
+}
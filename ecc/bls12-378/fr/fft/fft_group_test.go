@@ -0,0 +1,128 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fft
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-378"
+
+	"github.com/stretchr/testify/require"
+)
+
+// checkGroupFFT checks that the FFT over the group is the FFT of the discrete
+// logarithms of the points.
+func checkGroupFFT(t *testing.T, domain *Domain, decimation Decimation, inverse bool, opts ...Option) {
+	n := int(domain.Cardinality)
+	s := make([]fr.Element, n)
+	for i := range s {
+		s[i].SetRandom()
+	}
+	_, _, g1, g2 := curve.Generators()
+	p1 := make([]curve.G1Affine, n)
+	p2 := make([]curve.G2Affine, n)
+	var b big.Int
+	for i := range s {
+		p1[i].ScalarMultiplication(&g1, s[i].BigInt(&b))
+		p2[i].ScalarMultiplication(&g2, s[i].BigInt(&b))
+	}
+
+	if inverse {
+		domain.FFTInverse(s, decimation, opts...)
+		domain.FFTInverseG1Affine(p1, decimation, opts...)
+		domain.FFTInverseG2Affine(p2, decimation, opts...)
+	} else {
+		domain.FFT(s, decimation, opts...)
+		domain.FFTG1Affine(p1, decimation, opts...)
+		domain.FFTG2Affine(p2, decimation, opts...)
+	}
+
+	var e1 curve.G1Affine
+	var e2 curve.G2Affine
+	for i := range s {
+		e1.ScalarMultiplication(&g1, s[i].BigInt(&b))
+		e2.ScalarMultiplication(&g2, s[i].BigInt(&b))
+		require.True(t, e1.Equal(&p1[i]), "G1 point %d", i)
+		require.True(t, e2.Equal(&p2[i]), "G2 point %d", i)
+	}
+}
+
+func TestFFTGroup(t *testing.T) {
+	const size = 16
+
+	for name, domain := range map[string]*Domain{
+		"with precompute":    NewDomain(size),
+		"without precompute": NewDomain(size, WithoutPrecompute()),
+	} {
+		domain := domain
+		t.Run(name, func(t *testing.T) {
+			for _, decimation := range []Decimation{DIF, DIT} {
+				for _, inverse := range []bool{false, true} {
+					checkGroupFFT(t, domain, decimation, inverse)
+					checkGroupFFT(t, domain, decimation, inverse, OnCoset())
+					checkGroupFFT(t, domain, decimation, inverse, WithNbTasks(1))
+				}
+			}
+		})
+	}
+}
+
+func TestFFTGroupInverse(t *testing.T) {
+	const size = 32
+	domain := NewDomain(size)
+
+	_, _, g1, _ := curve.Generators()
+	p := make([]curve.G1Jac, size)
+	expected := make([]curve.G1Jac, size)
+	var s fr.Element
+	var b big.Int
+	for i := range p {
+		s.SetRandom()
+		p[i].FromAffine(&g1)
+		p[i].ScalarMultiplication(&p[i], s.BigInt(&b))
+		expected[i].Set(&p[i])
+	}
+
+	domain.FFTG1(p, DIF)
+	domain.FFTInverseG1(p, DIT)
+	for i := range p {
+		require.True(t, expected[i].Equal(&p[i]))
+	}
+
+	domain.FFTG1(p, DIF, OnCoset())
+	domain.FFTInverseG1(p, DIT, OnCoset())
+	for i := range p {
+		require.True(t, expected[i].Equal(&p[i]))
+	}
+}
+
+func BenchmarkFFTG1(b *testing.B) {
+	const size = 1 << 10
+	domain := NewDomain(size)
+	_, _, g1, _ := curve.Generators()
+	p := make([]curve.G1Jac, size)
+	for i := range p {
+		p[i].FromAffine(&g1)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		domain.FFTG1(p, DIF)
+	}
+}
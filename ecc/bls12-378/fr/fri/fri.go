@@ -52,16 +52,20 @@ type Digest []byte
 
 // merkleProof helper structure to build the merkle proof
 // At each round, two contiguous values from the evaluated polynomial
-// are queried. For one value, the full Merkle path will be provided.
-// For the neighbor value, only the leaf is provided (so ProofSet will
-// be empty), since the Merkle path is the same as for the first value.
+// are queried. Both values are opened with a single Merkle multiproof
+// (see merkletree.BuildMultiProof), since their Merkle paths only differ
+// by their leaves.
 type MerkleProof struct {
 
 	// Merkle root
 	MerkleRoot []byte
 
-	// ProofSet stores [leaf ∥ node_1 ∥ .. ∥ merkleRoot ], where the leaf is not
-	// hashed.
+	// Leaves stores the two queried values, the one at the even position
+	// first. The leaves are not hashed.
+	Leaves [2][]byte
+
+	// ProofSet stores the nodes needed to recompute the Merkle root from
+	// the leaves.
 	ProofSet [][]byte
 
 	// number of leaves of the tree.
@@ -106,7 +110,7 @@ type Round struct {
 	// stores the Interactions between the prover and the verifier.
 	// Each interaction results in a set or merkle proofs, corresponding
 	// to the queries of the verifier.
-	Interactions [][2]MerkleProof
+	Interactions []MerkleProof
 
 	// evaluation stores the evaluation of the fully folded polynomial.
 	// The fully folded polynomial is constant, and is evaluated on a
@@ -170,6 +174,22 @@ func (iopp IOPP) New(size uint64, h hash.Hash) Iopp {
 	}
 }
 
+// NewWithDuplex creates a new IOPP capable to handle degree(size) polynomials,
+// whose Fiat Shamir challenges are derived from the duplex d instead of h.
+// h is still used to commit to the oracles. The transcript of each round is
+// forked from d, so the prover and the verifier must use duplexes in the same
+// state.
+func (iopp IOPP) NewWithDuplex(size uint64, h hash.Hash, d *fiatshamir.Duplex) Iopp {
+	switch iopp {
+	case RADIX_2_FRI:
+		res := newRadixTwoFri(size, h)
+		res.duplex = d
+		return res
+	default:
+		panic("iopp name is not recognized")
+	}
+}
+
 // radixTwoFri empty structs implementing compressionFunction for
 // the squaring function.
 type radixTwoFri struct {
@@ -178,6 +198,9 @@ type radixTwoFri struct {
 	// the oracles.
 	h hash.Hash
 
+	// duplex, if not nil, from which the Fiat Shamir transcripts are forked.
+	duplex *fiatshamir.Duplex
+
 	// nbSteps number of Interactions between the prover and the verifier
 	nbSteps int
 
@@ -207,6 +230,15 @@ func newRadixTwoFri(size uint64, h hash.Hash) radixTwoFri {
 	return res
 }
 
+// newTranscript returns the Fiat Shamir transcript of a round, forked from the
+// duplex if any.
+func (s radixTwoFri) newTranscript(challengesID ...string) (*fiatshamir.Transcript, error) {
+	if s.duplex != nil {
+		return s.duplex.NewTranscript(challengesID...)
+	}
+	return fiatshamir.NewTranscript(s.h, challengesID...), nil
+}
+
 // convertCanonicalSorted convert the index i, an entry in a
 // sorted polynomial, to the corresponding entry in canonical
 // representation. n is the size of the polynomial.
@@ -303,17 +335,8 @@ func (s radixTwoFri) Open(p []fr.Element, position uint64) (OpeningProof, error)
 // those should be equal, if not an error is raised.
 func (s radixTwoFri) VerifyOpening(position uint64, openingProof OpeningProof, pp ProofOfProximity) error {
 
-	// To query the Merkle path, we look at the first series of Interactions, and check whether it's the point
-	// at 'position' or its neighbor that contains the full Merkle path.
-	var fullMerkleProof int
-	if len(pp.Rounds[0].Interactions[0][0].ProofSet) > len(pp.Rounds[0].Interactions[0][1].ProofSet) {
-		fullMerkleProof = 0
-	} else {
-		fullMerkleProof = 1
-	}
-
 	// check that the merkle roots coincide
-	if !bytes.Equal(openingProof.merkleRoot, pp.Rounds[0].Interactions[0][fullMerkleProof].MerkleRoot) {
+	if !bytes.Equal(openingProof.merkleRoot, pp.Rounds[0].Interactions[0].MerkleRoot) {
 		return ErrMerkleRoot
 	}
 
@@ -373,7 +396,7 @@ func (s radixTwoFri) buildProofOfProximitySingleRound(salt fr.Element, p []fr.El
 
 	// the proof will contain nbSteps Interactions
 	var res Round
-	res.Interactions = make([][2]MerkleProof, s.nbSteps)
+	res.Interactions = make([]MerkleProof, s.nbSteps)
 
 	// Fiat Shamir transcript to derive the challenges. The xᵢ are used to fold the
 	// polynomials.
@@ -386,11 +409,14 @@ func (s radixTwoFri) buildProofOfProximitySingleRound(salt fr.Element, p []fr.El
 		xis[i] = fmt.Sprintf("x%d", i)
 	}
 	xis[s.nbSteps] = "s0"
-	fs := fiatshamir.NewTranscript(s.h, xis...)
+	fs, err := s.newTranscript(xis...)
+	if err != nil {
+		return Round{}, err
+	}
 
 	// the salt is binded to the first challenge, to ensure the challenges
 	// are different at each round.
-	err := fs.Bind(xis[0], salt.Marshal())
+	err = fs.Bind(xis[0], salt.Marshal())
 	if err != nil {
 		return Round{}, err
 	}
@@ -465,35 +491,23 @@ func (s radixTwoFri) buildProofOfProximitySingleRound(salt fr.Element, p []fr.El
 
 	for i := 0; i < s.nbSteps; i++ {
 
-		// build proofs of queries at s[i]
-		t := merkletree.New(s.h)
-		err := t.SetIndex(uint64(si[i]))
-		if err != nil {
-			return res, err
-		}
+		// build the proof of the queries at s[i] and its neighbor, which
+		// share their Merkle path
+		leaves := make([][]byte, len(evalsAtRound[i]))
 		for k := 0; k < len(evalsAtRound[i]); k++ {
-			t.Push(evalsAtRound[i][k].Marshal())
-		}
-		mr, ProofSet, _, numLeaves := t.Prove()
-
-		// c denotes the entry that contains the full Merkle proof. The entry 1-c will
-		// only contain 2 elements, which are the neighbor point, and the hash of the
-		// first point. The remaining of the Merkle path is common to both the original
-		// point and its neighbor.
-		c := si[i] % 2
-		res.Interactions[i][c] = MerkleProof{mr, ProofSet, numLeaves}
-		res.Interactions[i][1-c] = MerkleProof{
-			mr,
-			make([][]byte, 2),
-			numLeaves,
+			leaves[k] = evalsAtRound[i][k].Marshal()
 		}
-		res.Interactions[i][1-c].ProofSet[0] = evalsAtRound[i][si[i]+1-2*c].Marshal()
-		s.h.Reset()
-		_, err = s.h.Write(res.Interactions[i][c].ProofSet[0])
+		even := uint64(si[i] - si[i]%2)
+		mr, proofSet, err := merkletree.BuildMultiProof(s.h, leaves, []uint64{even, even + 1})
 		if err != nil {
 			return res, err
 		}
-		res.Interactions[i][1-c].ProofSet[1] = s.h.Sum(nil)
+		res.Interactions[i] = MerkleProof{
+			MerkleRoot: mr,
+			Leaves:     [2][]byte{leaves[even], leaves[even+1]},
+			ProofSet:   proofSet,
+			numLeaves:  uint64(len(leaves)),
+		}
 
 	}
 
@@ -540,19 +554,22 @@ func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Ro
 		xis[i] = fmt.Sprintf("x%d", i)
 	}
 	xis[s.nbSteps] = "s0"
-	fs := fiatshamir.NewTranscript(s.h, xis...)
+	fs, err := s.newTranscript(xis...)
+	if err != nil {
+		return err
+	}
 
 	xi := make([]fr.Element, s.nbSteps)
 
 	// the salt is binded to the first challenge, to ensure the challenges
 	// are different at each round.
-	err := fs.Bind(xis[0], salt.Marshal())
+	err = fs.Bind(xis[0], salt.Marshal())
 	if err != nil {
 		return err
 	}
 
 	for i := 0; i < s.nbSteps; i++ {
-		err := fs.Bind(xis[i], proof.Interactions[i][0].MerkleRoot)
+		err := fs.Bind(xis[i], proof.Interactions[i].MerkleRoot)
 		if err != nil {
 			return err
 		}
@@ -591,35 +608,15 @@ func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Ro
 	accGInv.Set(&s.domain.GeneratorInv)
 	for i := 0; i < s.nbSteps; i++ {
 
-		// correctness of Merkle proof
-		// c is the entry containing the full Merkle proof.
-		c := si[i] % 2
-		res := merkletree.VerifyProof(
-			s.h,
-			proof.Interactions[i][c].MerkleRoot,
-			proof.Interactions[i][c].ProofSet,
-			uint64(si[i]),
-			proof.Interactions[i][c].numLeaves,
-		)
-		if !res {
-			return ErrMerklePath
-		}
-
-		// we verify the Merkle proof for the neighbor query, to do that we have
-		// to pick the full Merkle proof of the first entry, stripped off of the leaf and
-		// the first node. We replace the leaf and the first node by the leaf and the first
-		// node of the partial Merkle proof, since the leaf and the first node of both proofs
-		// are the only entries that differ.
-		ProofSet := make([][]byte, len(proof.Interactions[i][c].ProofSet))
-		copy(ProofSet[2:], proof.Interactions[i][c].ProofSet[2:])
-		ProofSet[0] = proof.Interactions[i][1-c].ProofSet[0]
-		ProofSet[1] = proof.Interactions[i][1-c].ProofSet[1]
-		res = merkletree.VerifyProof(
+		// correctness of the Merkle proof of the query and its neighbor
+		even := uint64(si[i] - si[i]%2)
+		res := merkletree.VerifyMultiProof(
 			s.h,
-			proof.Interactions[i][1-c].MerkleRoot,
-			ProofSet,
-			uint64(si[i]+1-2*c),
-			proof.Interactions[i][1-c].numLeaves,
+			proof.Interactions[i].MerkleRoot,
+			proof.Interactions[i].Leaves[:],
+			proof.Interactions[i].ProofSet,
+			[]uint64{even, even + 1},
+			proof.Interactions[i].numLeaves,
 		)
 		if !res {
 			return ErrMerklePath
@@ -631,8 +628,8 @@ func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Ro
 			var fe, fo, l, r, fn fr.Element
 
 			// l = P(gⁱ), r = P(g^{i+n/2})
-			l.SetBytes(proof.Interactions[i][0].ProofSet[0])
-			r.SetBytes(proof.Interactions[i][1].ProofSet[0])
+			l.SetBytes(proof.Interactions[i].Leaves[0])
+			r.SetBytes(proof.Interactions[i].Leaves[1])
 
 			// (g^{si[i]}, g^{si[i]+1}) is the fiber of g^{2*si[i]}. The system to solve
 			// (for P₀(g^{2si[i]}), P₀(g^{2si[i]}) ) is:
@@ -645,7 +642,7 @@ func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Ro
 			fo.Sub(&l, &r).Mul(&fo, &ginv)                      // P₀(g²ⁱ) (to be multiplied by 2⁻¹)
 			fo.Mul(&fo, &xi[i]).Add(&fo, &fe).Mul(&fo, &twoInv) // P₀(g²ⁱ) + xᵢ * P₁(g²ⁱ)
 
-			fn.SetBytes(proof.Interactions[i+1][si[i+1]%2].ProofSet[0])
+			fn.SetBytes(proof.Interactions[i+1].Leaves[si[i+1]%2])
 
 			if !fo.Equal(&fn) {
 				return ErrProximityTestFolding
@@ -660,8 +657,8 @@ func (s radixTwoFri) verifyProofOfProximitySingleRound(salt fr.Element, proof Ro
 	// last transition
 	var fe, fo, l, r fr.Element
 
-	l.SetBytes(proof.Interactions[s.nbSteps-1][0].ProofSet[0])
-	r.SetBytes(proof.Interactions[s.nbSteps-1][1].ProofSet[0])
+	l.SetBytes(proof.Interactions[s.nbSteps-1].Leaves[0])
+	r.SetBytes(proof.Interactions[s.nbSteps-1].Leaves[1])
 
 	_si := si[s.nbSteps-1] / 2
 
@@ -324,3 +324,74 @@ func TestBuildRatioCopyConstraint(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildRatioLogDerivative(t *testing.T) {
+
+	// t is a random table, f looks up entries of t, m counts how many
+	// times each entry of t is looked up.
+	sizePolynomials := 8
+	table := NewPolynomial(randomVector(sizePolynomials), Form{Basis: Lagrange, Layout: Regular})
+	lookup := make([]fr.Element, sizePolynomials)
+	multiplicities := make([]fr.Element, sizePolynomials)
+	var one fr.Element
+	one.SetOne()
+	for i := 0; i < sizePolynomials; i++ {
+		j := (3 * i) % (sizePolynomials / 2)
+		lookup[i].Set(&table.Coefficients()[j])
+		multiplicities[j].Add(&multiplicities[j], &one)
+	}
+	f := NewPolynomial(&lookup, Form{Basis: Lagrange, Layout: Regular})
+	m := NewPolynomial(&multiplicities, Form{Basis: Lagrange, Layout: Regular})
+	backupF, backupT, backupM := f.Clone(), table.Clone(), m.Clone()
+
+	// build the sum
+	expectedForm := Form{Basis: Lagrange, Layout: Regular}
+	domain := fft.NewDomain(uint64(sizePolynomials))
+	var beta fr.Element
+	beta.SetRandom()
+	ratio, err := BuildRatioLogDerivative(f, table, m, beta, expectedForm, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check that the whole sum is equal to zero
+	var a, b, c fr.Element
+	last := sizePolynomials - 1
+	a.Sub(&beta, &f.Coefficients()[last]).Inverse(&a)
+	b.Sub(&beta, &table.Coefficients()[last]).Inverse(&b).
+		Mul(&b, &m.Coefficients()[last])
+	c.Add(&ratio.Coefficients()[last], &a).Sub(&c, &b)
+	if !c.IsZero() {
+		t.Fatal("accumulating sum is not equal to zero")
+	}
+
+	// check that the sum is correct when the inputs are in
+	// canonical form, bit reverse
+	for _, p := range []*Polynomial{backupF, backupT, backupM} {
+		domain.FFTInverse(p.Coefficients(), fft.DIF)
+		p.Layout = BitReverse
+		p.Basis = Canonical
+	}
+	{
+		_ratio, err := BuildRatioLogDerivative(backupF, backupT, backupM, beta, expectedForm, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCoeffs := cmpCoefficents(_ratio.coefficients, ratio.coefficients)
+		if !checkCoeffs {
+			t.Fatal("coefficients of ratio are not consistent")
+		}
+	}
+
+	// a lookup of a value which is not in the table gives a nonzero sum
+	f = NewPolynomial(randomVector(sizePolynomials), Form{Basis: Lagrange, Layout: Regular})
+	ratio, err = BuildRatioLogDerivative(f, table, m, beta, expectedForm, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Sub(&beta, &f.Coefficients()[last]).Inverse(&a)
+	c.Add(&ratio.Coefficients()[last], &a).Sub(&c, &b)
+	if c.IsZero() {
+		t.Fatal("accumulating sum of an incorrect lookup should not be zero")
+	}
+}
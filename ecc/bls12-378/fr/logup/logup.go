@@ -0,0 +1,459 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package logup
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12378 "github.com/consensys/gnark-crypto/ecc/bls12-378"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/iop"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/kzg"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrNotInTable        = errors.New("some value in f is not in t")
+	ErrIncompatibleSize  = errors.New("the tables in f and t are not of the same size")
+	ErrNumberDigests     = errors.New("proof.fs and proof.ts are not of the same length")
+	ErrDomainSize        = errors.New("the size of the domain is not a supported power of two")
+	ErrLogUpVerification = errors.New("logUp proof verification failed")
+)
+
+// Proof proves that the rows of the tables f are rows of the tables t.
+type Proof struct {
+
+	// size of the polynomials
+	size uint64
+
+	// commitments to the columns of f and t
+	fs, ts []kzg.Digest
+
+	// commitments to the multiplicities m, the accumulating sum z and the
+	// quotient h
+	m, z, h kzg.Digest
+
+	// opening proofs of the folded f, the folded t, m, z, h (in that order)
+	batchedProof kzg.BatchOpeningProof
+
+	// shifted opening proof of z
+	shiftedProof kzg.OpeningProof
+}
+
+// ProveLookupVector generates a proof that the entries of f are entries of t.
+// An entry of t can be looked up any number of times, and f and t need not be
+// of the same size.
+func ProveLookupVector(pk kzg.ProvingKey, f, t fr.Vector) (Proof, error) {
+	return ProveLookupTables(pk, []fr.Vector{f}, []fr.Vector{t})
+}
+
+// ProveLookupTables generates a proof that f, seen as a multi dimensional table,
+// consists of rows that are in t. In other words for each i, f[:][i] must be one
+// of the t[:][j].
+//
+// For instance, if t is the truth table of the XOR function, t will be populated such
+// that t[:][i] contains the i-th entry of the truth table, so t[0][i] XOR t[1][i] = t[2][i].
+//
+// The columns of f (resp. t) are supposed to be of the same size.
+func ProveLookupTables(pk kzg.ProvingKey, f, t []fr.Vector) (Proof, error) {
+
+	// res
+	var proof Proof
+	var err error
+
+	// check the sizes
+	if len(f) == 0 || len(f) != len(t) {
+		return proof, ErrIncompatibleSize
+	}
+	nbColumns := len(f)
+	sizeF, sizeT := len(f[0]), len(t[0])
+	if sizeF == 0 || sizeT == 0 {
+		return proof, ErrIncompatibleSize
+	}
+	for i := 1; i < nbColumns; i++ {
+		if len(f[i]) != sizeF || len(t[i]) != sizeT {
+			return proof, ErrIncompatibleSize
+		}
+	}
+
+	// domain of the polynomials
+	n := sizeF
+	if n < sizeT {
+		n = sizeT
+	}
+	proof.size = ecc.NextPowerOfTwo(uint64(n))
+	if proof.size < 2 {
+		proof.size = 2
+	}
+	n = int(proof.size)
+	domain := fft.NewDomain(proof.size)
+
+	// multiplicities: the j-th entry of m is the number of times the j-th row of t
+	// is looked up. f is padded with the first row of t, and t with its last row.
+	index := make(map[string]int, sizeT)
+	for j := 0; j < sizeT; j++ {
+		k := rowKey(t, j)
+		if _, ok := index[k]; !ok {
+			index[k] = j
+		}
+	}
+	lm := make([]fr.Element, n)
+	var one fr.Element
+	one.SetOne()
+	for i := 0; i < sizeF; i++ {
+		j, ok := index[rowKey(f, i)]
+		if !ok {
+			return proof, ErrNotInTable
+		}
+		lm[j].Add(&lm[j], &one)
+	}
+	var padding fr.Element
+	padding.SetUint64(uint64(n - sizeF))
+	lm[0].Add(&lm[0], &padding)
+
+	// hash function used for Fiat Shamir
+	hFunc := sha256.New()
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewTranscript(hFunc, "gamma", "beta", "alpha", "nu")
+	if err = bindSize(fs, proof.size); err != nil {
+		return proof, err
+	}
+
+	// commit to the columns of f and t, and to m
+	lfs := make([][]fr.Element, nbColumns)
+	lts := make([][]fr.Element, nbColumns)
+	proof.fs = make([]kzg.Digest, nbColumns)
+	proof.ts = make([]kzg.Digest, nbColumns)
+	for i := 0; i < nbColumns; i++ {
+		lfs[i] = make([]fr.Element, n)
+		copy(lfs[i], f[i])
+		for j := sizeF; j < n; j++ {
+			lfs[i][j] = t[i][0]
+		}
+		if proof.fs[i], err = commitLagrange(lfs[i], domain, pk); err != nil {
+			return proof, err
+		}
+
+		lts[i] = make([]fr.Element, n)
+		copy(lts[i], t[i])
+		for j := sizeT; j < n; j++ {
+			lts[i][j] = t[i][sizeT-1]
+		}
+		if proof.ts[i], err = commitLagrange(lts[i], domain, pk); err != nil {
+			return proof, err
+		}
+	}
+	if proof.m, err = commitLagrange(lm, domain, pk); err != nil {
+		return proof, err
+	}
+
+	// fold the columns of f and t
+	gamma, err := deriveRandomness(fs, "gamma", append(append(digestsPointers(proof.fs), digestsPointers(proof.ts)...), &proof.m)...)
+	if err != nil {
+		return proof, err
+	}
+	lf := make([]fr.Element, n)
+	lt := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		for j := nbColumns - 1; j >= 0; j-- {
+			lf[i].Mul(&lf[i], &gamma).
+				Add(&lf[i], &lfs[j][i])
+			lt[i].Mul(&lt[i], &gamma).
+				Add(&lt[i], &lts[j][i])
+		}
+	}
+	foldedF := foldDigests(proof.fs, gamma)
+	foldedT := foldDigests(proof.ts, gamma)
+
+	// build the accumulating sum z
+	beta, err := deriveRandomness(fs, "beta")
+	if err != nil {
+		return proof, err
+	}
+	lagrange := iop.Form{Basis: iop.Lagrange, Layout: iop.Regular}
+	canonical := iop.Form{Basis: iop.Canonical, Layout: iop.Regular}
+	pf := iop.NewPolynomial(&lf, lagrange)
+	pt := iop.NewPolynomial(&lt, lagrange)
+	pm := iop.NewPolynomial(&lm, lagrange)
+	pz, err := iop.BuildRatioLogDerivative(pf, pt, pm, beta, canonical, domain)
+	if err != nil {
+		return proof, err
+	}
+	if proof.z, err = kzg.Commit(pz.Coefficients(), pk); err != nil {
+		return proof, err
+	}
+
+	// compute the quotient h of
+	// (z(ωX)-z(X))(β-f)(β-t) - (β-t) + m(β-f) + α L₀ z
+	// by Xⁿ-1
+	alpha, err := deriveRandomness(fs, "alpha", &proof.z)
+	if err != nil {
+		return proof, err
+	}
+	cf := pf.ToCanonical(domain).ToRegular()
+	ct := pt.ToCanonical(domain).ToRegular()
+	cm := pm.ToCanonical(domain).ToRegular()
+	cl0 := make([]fr.Element, n)
+	cl0[0].SetUint64(uint64(n)).Inverse(&cl0[0])
+	for i := 1; i < n; i++ {
+		cl0[i] = cl0[0]
+	}
+	pl0 := iop.NewPolynomial(&cl0, canonical)
+	polys := []*iop.Polynomial{cf.Clone(), ct.Clone(), cm.Clone(), pz.Clone(), pl0}
+
+	bigDomain := fft.NewDomain(ecc.NextPowerOfTwo(3 * proof.size))
+	for _, p := range polys {
+		p.ToLagrangeCoset(bigDomain)
+	}
+	polys = append(polys, polys[3].ShallowClone().Shift(1))
+
+	numerator := func(_ int, x ...fr.Element) fr.Element {
+		// x = [f, t, m, z, l0, z(ωX)]
+		var bf, bt, a, b fr.Element
+		bf.Sub(&beta, &x[0])
+		bt.Sub(&beta, &x[1])
+		a.Sub(&x[5], &x[3]).Mul(&a, &bf).Mul(&a, &bt).Sub(&a, &bt)
+		b.Mul(&x[2], &bf)
+		a.Add(&a, &b)
+		b.Mul(&alpha, &x[4]).Mul(&b, &x[3])
+		a.Add(&a, &b)
+		return a
+	}
+	ph, err := iop.Evaluate(numerator, nil, iop.Form{Basis: iop.LagrangeCoset, Layout: iop.BitReverse}, polys...)
+	if err != nil {
+		return proof, err
+	}
+	ph, err = iop.DivideByXMinusOne(ph, [2]*fft.Domain{domain, bigDomain})
+	if err != nil {
+		return proof, err
+	}
+
+	// h is of degree 2n-3
+	ch := ph.ToRegular().Coefficients()[:2*n-2]
+	if proof.h, err = kzg.Commit(ch, pk); err != nil {
+		return proof, err
+	}
+
+	// open the polynomials at ν, and z at ων
+	nu, err := deriveRandomness(fs, "nu", &proof.h)
+	if err != nil {
+		return proof, err
+	}
+	proof.batchedProof, err = kzg.BatchOpenSinglePoint(
+		[][]fr.Element{
+			cf.Coefficients(),
+			ct.Coefficients(),
+			cm.Coefficients(),
+			pz.Coefficients(),
+			ch,
+		},
+		[]kzg.Digest{
+			foldedF,
+			foldedT,
+			proof.m,
+			proof.z,
+			proof.h,
+		},
+		nu,
+		hFunc,
+		pk,
+	)
+	if err != nil {
+		return proof, err
+	}
+
+	var shiftedNu fr.Element
+	shiftedNu.Mul(&nu, &domain.Generator)
+	proof.shiftedProof, err = kzg.Open(pz.Coefficients(), shiftedNu, pk)
+
+	return proof, err
+}
+
+// Verify verifies that a logUp proof is correct.
+func Verify(vk kzg.VerifyingKey, proof Proof) error {
+
+	// check the shape of the proof
+	if len(proof.fs) == 0 || len(proof.fs) != len(proof.ts) {
+		return ErrNumberDigests
+	}
+	if proof.size < 2 || bits.OnesCount64(proof.size) != 1 {
+		return ErrDomainSize
+	}
+	g, err := fft.Generator(proof.size)
+	if err != nil {
+		return ErrDomainSize
+	}
+
+	// hash function used for Fiat Shamir
+	hFunc := sha256.New()
+
+	// transcript to derive the challenges
+	fs := fiatshamir.NewTranscript(hFunc, "gamma", "beta", "alpha", "nu")
+	if err = bindSize(fs, proof.size); err != nil {
+		return err
+	}
+	gamma, err := deriveRandomness(fs, "gamma", append(append(digestsPointers(proof.fs), digestsPointers(proof.ts)...), &proof.m)...)
+	if err != nil {
+		return err
+	}
+	beta, err := deriveRandomness(fs, "beta")
+	if err != nil {
+		return err
+	}
+	alpha, err := deriveRandomness(fs, "alpha", &proof.z)
+	if err != nil {
+		return err
+	}
+	nu, err := deriveRandomness(fs, "nu", &proof.h)
+	if err != nil {
+		return err
+	}
+
+	// check the opening proofs
+	foldedF := foldDigests(proof.fs, gamma)
+	foldedT := foldDigests(proof.ts, gamma)
+	foldedProof, foldedDigest, err := kzg.FoldProof(
+		[]kzg.Digest{
+			foldedF,
+			foldedT,
+			proof.m,
+			proof.z,
+			proof.h,
+		},
+		&proof.batchedProof,
+		nu,
+		hFunc,
+	)
+	if err != nil {
+		return err
+	}
+	var shiftedNu fr.Element
+	shiftedNu.Mul(&nu, &g)
+	err = kzg.BatchVerifyMultiPoints(
+		[]kzg.Digest{
+			foldedDigest,
+			proof.z,
+		},
+		[]kzg.OpeningProof{
+			foldedProof,
+			proof.shiftedProof,
+		},
+		[]fr.Element{
+			nu,
+			shiftedNu,
+		},
+		vk,
+	)
+	if err != nil {
+		return err
+	}
+
+	// check the relation
+	// (z(ων)-z(ν))(β-f(ν))(β-t(ν)) - (β-t(ν)) + m(ν)(β-f(ν)) + α L₀(ν) z(ν) = h(ν)(νⁿ-1)
+	claimed := proof.batchedProof.ClaimedValues
+	f, t, m, z, h := claimed[0], claimed[1], claimed[2], claimed[3], claimed[4]
+	zs := proof.shiftedProof.ClaimedValue
+
+	var one, nuN, l0, bf, bt, lhs, rhs, tmp fr.Element
+	one.SetOne()
+	nuN.Exp(nu, big.NewInt(int64(proof.size))).Sub(&nuN, &one)
+	l0.SetUint64(proof.size)
+	tmp.Sub(&nu, &one)
+	l0.Mul(&l0, &tmp).Inverse(&l0).Mul(&l0, &nuN)
+
+	bf.Sub(&beta, &f)
+	bt.Sub(&beta, &t)
+	lhs.Sub(&zs, &z).Mul(&lhs, &bf).Mul(&lhs, &bt).Sub(&lhs, &bt)
+	tmp.Mul(&m, &bf)
+	lhs.Add(&lhs, &tmp)
+	tmp.Mul(&alpha, &l0).Mul(&tmp, &z)
+	lhs.Add(&lhs, &tmp)
+	rhs.Mul(&h, &nuN)
+
+	if !lhs.Equal(&rhs) {
+		return ErrLogUpVerification
+	}
+
+	return nil
+}
+
+// commitLagrange commits to the polynomial whose evaluations on domain are
+// given in lagrange (regular layout). lagrange is left unchanged.
+func commitLagrange(lagrange []fr.Element, domain *fft.Domain, pk kzg.ProvingKey) (kzg.Digest, error) {
+	c := make([]fr.Element, len(lagrange))
+	copy(c, lagrange)
+	domain.FFTInverse(c, fft.DIF)
+	fft.BitReverse(c)
+	return kzg.Commit(c, pk)
+}
+
+// foldDigests returns Σᵢ γⁱ digests[i].
+func foldDigests(digests []kzg.Digest, gamma fr.Element) kzg.Digest {
+	var res kzg.Digest
+	var bGamma big.Int
+	gamma.BigInt(&bGamma)
+	res.Set(&digests[len(digests)-1])
+	for i := len(digests) - 2; i >= 0; i-- {
+		res.ScalarMultiplication(&res, &bGamma).
+			Add(&res, &digests[i])
+	}
+	return res
+}
+
+func digestsPointers(digests []kzg.Digest) []*kzg.Digest {
+	res := make([]*kzg.Digest, len(digests))
+	for i := range digests {
+		res[i] = &digests[i]
+	}
+	return res
+}
+
+// rowKey returns a key identifying the i-th row of the table.
+func rowKey(table []fr.Vector, i int) string {
+	res := make([]byte, 0, len(table)*fr.Bytes)
+	for j := range table {
+		b := table[j][i].Bytes()
+		res = append(res, b[:]...)
+	}
+	return string(res)
+}
+
+// bindSize binds the size of the domain to the first challenge.
+func bindSize(fs *fiatshamir.Transcript, size uint64) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], size)
+	return fs.Bind("gamma", buf[:])
+}
+
+// deriveRandomness binds the points to the challenge and returns it as a
+// field element.
+func deriveRandomness(fs *fiatshamir.Transcript, challenge string, points ...*bls12378.G1Affine) (fr.Element, error) {
+	var r fr.Element
+	b, err := fiatshamir.ComputeChallengeFrom(fs, challenge, points...)
+	if err != nil {
+		return r, err
+	}
+	r.SetBytes(b)
+	return r, nil
+}
@@ -0,0 +1,212 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package secretsharing
+
+import (
+	"math/big"
+	"sync"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-378"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/polynomial"
+)
+
+// pedersenDST is the domain separation tag used to derive the second
+// generator of the Pedersen commitments, whose discrete logarithm in base the
+// G1 generator is unknown.
+const pedersenDST = "GNARK-CRYPTO-SECRET-SHARING-BLS12-378-PEDERSEN-H"
+
+var (
+	pedersenH     curve.G1Affine
+	pedersenHOnce sync.Once
+)
+
+// PedersenGenerators returns the generators (G, H) of the Pedersen
+// commitments: G is the G1 generator and H is obtained by hashing to G1, so
+// that nobody knows log_G(H).
+func PedersenGenerators() (g, h curve.G1Affine) {
+	pedersenHOnce.Do(func() {
+		var err error
+		pedersenH, err = curve.HashToG1([]byte("H"), []byte(pedersenDST))
+		if err != nil {
+			panic(err)
+		}
+	})
+	_, _, g, _ = curve.Generators()
+	return g, pedersenH
+}
+
+// FeldmanCommitment is the commitment Cₖ = aₖ⋅G to the sharing polynomial
+// ∑ aₖ⋅Xᵏ. C₀ = secret⋅G is public.
+type FeldmanCommitment []curve.G1Affine
+
+// PedersenCommitment is the commitment Cₖ = aₖ⋅G + bₖ⋅H to the sharing
+// polynomial ∑ aₖ⋅Xᵏ, blinded by the polynomial ∑ bₖ⋅Xᵏ. Unlike Feldman's, it
+// reveals nothing about the secret.
+type PedersenCommitment []curve.G1Affine
+
+// PedersenShare is a share of a secret shared with Pedersen verifiable secret
+// sharing, with the evaluation at ID of the blinding polynomial.
+type PedersenShare struct {
+	Share
+	Blinding fr.Element
+}
+
+// SplitFeldman splits secret as Split, and returns the Feldman commitment to
+// the sharing polynomial.
+func SplitFeldman(secret *fr.Element, threshold, n int) ([]Share, FeldmanCommitment, error) {
+	f, err := randomPolynomial(secret, threshold, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer erase(f)
+	return evalShares(f, n), commitFeldman(f), nil
+}
+
+// SplitPedersen splits secret as Split, and returns the Pedersen commitment to
+// the sharing polynomial.
+func SplitPedersen(secret *fr.Element, threshold, n int) ([]PedersenShare, PedersenCommitment, error) {
+	var zero fr.Element
+	f, err := randomPolynomial(secret, threshold, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer erase(f)
+	fBlinding, err := randomPolynomial(&zero, threshold, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer erase(fBlinding)
+	if _, err = fBlinding[0].SetRandom(); err != nil {
+		return nil, nil, err
+	}
+	return evalPedersenShares(f, fBlinding, n), commitPedersen(f, fBlinding), nil
+}
+
+// Verify checks that share is consistent with the commitment:
+//
+//	share.Value⋅G == ∑ Cₖ⋅IDᵏ
+//
+// share.Value is secret, the scalar multiplication runs in constant time.
+func (c FeldmanCommitment) Verify(share *Share) error {
+	if len(c) == 0 {
+		return ErrInvalidCommitment
+	}
+	if share.ID == 0 {
+		return ErrInvalidID
+	}
+	var lhs curve.G1Affine
+	var b big.Int
+	lhs.ScalarMultiplicationBaseConstantTime(share.Value.BigInt(&b))
+	rhs := c.Eval(share.ID)
+	if !lhs.Equal(&rhs) {
+		return ErrInvalidShare
+	}
+	return nil
+}
+
+// Eval returns ∑ Cₖ⋅xᵏ, that is f(x)⋅G where f is the sharing polynomial: the
+// public verification share of the participant x.
+func (c FeldmanCommitment) Eval(x uint64) curve.G1Affine {
+	return evalCommitment(c, x)
+}
+
+// Verify checks that share is consistent with the commitment:
+//
+//	share.Value⋅G + share.Blinding⋅H == ∑ Cₖ⋅IDᵏ
+func (c PedersenCommitment) Verify(share *PedersenShare) error {
+	if len(c) == 0 {
+		return ErrInvalidCommitment
+	}
+	if share.ID == 0 {
+		return ErrInvalidID
+	}
+	lhs := commitPedersenValue(&share.Value, &share.Blinding)
+	rhs := evalCommitment(c, share.ID)
+	if !lhs.Equal(&rhs) {
+		return ErrInvalidShare
+	}
+	return nil
+}
+
+// commitFeldman returns the Feldman commitment to f.
+func commitFeldman(f polynomial.Polynomial) FeldmanCommitment {
+	res := make(FeldmanCommitment, len(f))
+	var b big.Int
+	for i := range f {
+		res[i].ScalarMultiplicationBaseConstantTime(f[i].BigInt(&b))
+	}
+	return res
+}
+
+// commitPedersen returns the Pedersen commitment to f blinded by fBlinding.
+func commitPedersen(f, fBlinding polynomial.Polynomial) PedersenCommitment {
+	res := make(PedersenCommitment, len(f))
+	for i := range f {
+		res[i] = commitPedersenValue(&f[i], &fBlinding[i])
+	}
+	return res
+}
+
+// commitPedersenValue returns v⋅G + r⋅H.
+func commitPedersenValue(v, r *fr.Element) curve.G1Affine {
+	_, h := PedersenGenerators()
+	var res, t curve.G1Affine
+	var b big.Int
+	res.ScalarMultiplicationBaseConstantTime(v.BigInt(&b))
+	t.ScalarMultiplicationConstantTime(&h, r.BigInt(&b))
+	return *res.Add(&res, &t)
+}
+
+func evalPedersenShares(f, fBlinding polynomial.Polynomial, n int) []PedersenShare {
+	shares := make([]PedersenShare, n)
+	var x fr.Element
+	for i := range shares {
+		shares[i].ID = uint64(i + 1)
+		x.SetUint64(shares[i].ID)
+		shares[i].Value = f.Eval(&x)
+		shares[i].Blinding = fBlinding.Eval(&x)
+	}
+	return shares
+}
+
+// evalCommitment returns ∑ cₖ⋅xᵏ.
+func evalCommitment(c []curve.G1Affine, x uint64) curve.G1Affine {
+	var res curve.G1Jac
+	var bx big.Int
+	bx.SetUint64(x)
+	res.FromAffine(&c[len(c)-1])
+	for i := len(c) - 2; i >= 0; i-- {
+		res.ScalarMultiplication(&res, &bx).AddMixed(&c[i])
+	}
+	var p curve.G1Affine
+	p.FromJacobian(&res)
+	return p
+}
+
+// addCommitments returns the coefficient-wise sum of a and b.
+func addCommitments(a, b []curve.G1Affine) []curve.G1Affine {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	res := make([]curve.G1Affine, len(a))
+	copy(res, a)
+	for i := range b {
+		res[i].Add(&res[i], &b[i])
+	}
+	return res
+}
@@ -0,0 +1,511 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrInvalidNbVariables = errors.New("the number of coordinates of the point is not the number of variables of the polynomial")
+	ErrInvalidG2Powers    = errors.New("the powers of τ in G₂ don't match the verifying key or are too few")
+)
+
+// MultilinearVerifyingKey used to verify Zeromorph opening proofs of
+// multilinear polynomials in NbVariables variables, committed with a
+// ProvingKey of N powers of τ in G₁.
+//
+// implements io.ReaderFrom and io.WriterTo
+type MultilinearVerifyingKey struct {
+	Vk VerifyingKey
+
+	// NbVariables number n of variables of the polynomials
+	NbVariables int
+
+	// ShiftedG2 [τ^{N-2ⁿ+1}]G₂, used to check that the opening quotient has
+	// degree < 2ⁿ-1 whatever the size N of the proving key
+	ShiftedG2 bls12378.G2Affine
+}
+
+// NewMultilinearVerifyingKey returns the verifying key of the Zeromorph
+// openings of multilinear polynomials in nbVariables variables, committed with
+// a ProvingKey of srsSize powers of τ in G₁.
+//
+// g2 are the powers [τⁱ]G₂ of the setup, starting from i = 0 (e.g.
+// PowersOfTau.G2). They must go up to i = srsSize-2ⁿ+1.
+func NewMultilinearVerifyingKey(vk VerifyingKey, g2 []bls12378.G2Affine, srsSize, nbVariables int) (MultilinearVerifyingKey, error) {
+	var res MultilinearVerifyingKey
+	if nbVariables < 0 || nbVariables >= bits.UintSize-1 || srsSize < 1<<nbVariables {
+		return res, ErrInvalidPolynomialSize
+	}
+	shift := srsSize - 1<<nbVariables + 1
+	if len(g2) <= shift || len(g2) < 2 || !g2[0].Equal(&vk.G2[0]) || !g2[1].Equal(&vk.G2[1]) {
+		return res, ErrInvalidG2Powers
+	}
+	res.Vk = vk
+	res.NbVariables = nbVariables
+	res.ShiftedG2 = g2[shift]
+	return res, nil
+}
+
+// MultilinearOpeningProof Zeromorph proof of the evaluation of a multilinear
+// polynomial at a point.
+//
+// A multilinear polynomial f in n variables is given by its 2ⁿ evaluations on
+// the hypercube {0,1}ⁿ, with the layout of polynomial.MultiLin, and committed
+// with Commit, as the univariate polynomial U(f) = ∑ᵢf[i]Xⁱ.
+//
+// implements io.ReaderFrom and io.WriterTo
+type MultilinearOpeningProof struct {
+	// Quotients commitments to U(qₖ), k < n, where f - f(u) = ∑ₖ(Xₖ-uₖ)qₖ and qₖ
+	// only depends on X₀, …, Xₖ₋₁
+	Quotients []bls12378.G1Affine
+
+	// BatchedQuotient commitment to ∑ₖyᵏX^{2ⁿ-2ᵏ}U(qₖ), to check the degrees of the U(qₖ)
+	BatchedQuotient bls12378.G1Affine
+
+	// H commitment to X^{N-2ⁿ+1}(ζₓ+zZₓ)/(X-x), where ζₓ and Zₓ vanish at x
+	// and N is the number of powers of τ in G₁ of the proving key
+	H bls12378.G1Affine
+
+	// ClaimedValue purported value f(u)
+	ClaimedValue fr.Element
+}
+
+// MultilinearBatchOpeningProof Zeromorph proof of the evaluations of several
+// multilinear polynomials at the same point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type MultilinearBatchOpeningProof struct {
+	// Folded opening proof of ∑ᵢγⁱfᵢ
+	Folded MultilinearOpeningProof
+
+	// ClaimedValues purported values fᵢ(u)
+	ClaimedValues []fr.Element
+}
+
+// OpenMultilinear creates a Zeromorph opening proof of the multilinear
+// polynomial f at point (KT23, https://eprint.iacr.org/2023/917.pdf, section 5).
+// It's an interactive protocol, made non-interactive using Fiat Shamir.
+//
+// * f is the list of the 2ⁿ evaluations of the polynomial on the hypercube, as a polynomial.MultiLin
+// * digest is the commitment to f, obtained with Commit(f, pk)
+// * point is the point of size n at which f is opened, the first coordinate
+// corresponding to the first variable of polynomial.MultiLin, the most significant bit of the index
+// * dataTranscript extra data that might be needed to derive the challenges
+//
+// The degree of the final quotient is checked by committing to it shifted to
+// the last powers of τ of pk, so the verifier must use a
+// MultilinearVerifyingKey built for the size of pk.
+func OpenMultilinear(f []fr.Element, digest Digest, point []fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (MultilinearOpeningProof, error) {
+
+	var res MultilinearOpeningProof
+	nbVariables, err := checkMultilinearSizes(len(f), point)
+	if err != nil {
+		return res, err
+	}
+	if len(f) > len(pk.G1) {
+		return res, ErrInvalidPolynomialSize
+	}
+	size := len(f)
+
+	// qₖ[i] = fₖ₊₁[i+2ᵏ] - fₖ₊₁[i] where fₖ₊₁ is f partially evaluated at
+	// (uₖ₊₁, …, uₙ₋₁), and fₖ = fₖ₊₁ + uₖqₖ
+	q := make([][]fr.Element, nbVariables)
+	folded := make([]fr.Element, size)
+	copy(folded, f)
+	for k := nbVariables - 1; k >= 0; k-- {
+		half := 1 << k
+		u := point[nbVariables-1-k]
+		q[k] = make([]fr.Element, half)
+		var t fr.Element
+		for i := 0; i < half; i++ {
+			q[k][i].Sub(&folded[i+half], &folded[i])
+			t.Mul(&q[k][i], &u)
+			folded[i].Add(&folded[i], &t)
+		}
+		folded = folded[:half]
+	}
+	res.ClaimedValue = folded[0]
+
+	res.Quotients = make([]bls12378.G1Affine, nbVariables)
+	for k := range q {
+		if res.Quotients[k], err = Commit(q[k], pk); err != nil {
+			return res, err
+		}
+	}
+
+	// derive the challenge y, binded to the digest, the point, the claimed
+	// value and the quotients
+	fs := fiatshamir.NewTranscript(hf, "y", "x", "z")
+	y, err := deriveChallengeMultilinear(fs, &digest, point, res.ClaimedValue, res.Quotients, dataTranscript...)
+	if err != nil {
+		return res, err
+	}
+
+	// q̂ = ∑ₖyᵏX^{2ⁿ-2ᵏ}U(qₖ)
+	qHat := make([]fr.Element, size)
+	var yK, t fr.Element
+	yK.SetOne()
+	for k := range q {
+		offset := size - len(q[k])
+		for i := range q[k] {
+			t.Mul(&q[k][i], &yK)
+			qHat[offset+i].Add(&qHat[offset+i], &t)
+		}
+		yK.Mul(&yK, &y)
+	}
+	if res.BatchedQuotient, err = Commit(qHat, pk); err != nil {
+		return res, err
+	}
+
+	// derive the challenges x and z
+	x, err := deriveChallengeZeromorph(fs, "x", res.BatchedQuotient)
+	if err != nil {
+		return res, err
+	}
+	z, err := deriveChallengeZeromorph(fs, "z")
+	if err != nil {
+		return res, err
+	}
+
+	// W = ζₓ + zZₓ where
+	// ζₓ = q̂ - ∑ₖyᵏx^{2ⁿ-2ᵏ}U(qₖ)
+	// Zₓ = U(f) - vΦₙ(x) - ∑ₖ(x^{2ᵏ}Φₙ₋ₖ₋₁(x^{2ᵏ⁺¹}) - uₖΦₙ₋ₖ(x^{2ᵏ}))U(qₖ)
+	// so that W(x) = 0
+	scalars, phi := zeromorphScalars(point, x, y, z)
+	w := qHat
+	for k := range q {
+		for i := range q[k] {
+			t.Mul(&q[k][i], &scalars[k])
+			w[i].Sub(&w[i], &t)
+		}
+	}
+	for i := range f {
+		t.Mul(&f[i], &z)
+		w[i].Add(&w[i], &t)
+	}
+	t.Mul(&res.ClaimedValue, &phi).Mul(&t, &z)
+	w[0].Sub(&w[0], &t)
+
+	// H = [X^{N-2ⁿ+1}W/(X-x)], W/(X-x) has 2ⁿ-1 coefficients which are
+	// committed with the last powers of τ
+	var zero fr.Element
+	h := dividePolyByXminusA(w, zero, x)
+	if len(h) > 0 {
+		if res.H, err = Commit(h, ProvingKey{G1: pk.G1[len(pk.G1)-len(h):]}); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// VerifyMultilinear verifies a Zeromorph opening proof of the multilinear
+// polynomial committed in digest, at point.
+//
+// * dataTranscript extra data that might be needed to derive the challenges
+func VerifyMultilinear(digest *Digest, proof *MultilinearOpeningProof, point []fr.Element, hf hash.Hash, vk MultilinearVerifyingKey, dataTranscript ...[]byte) error {
+
+	nbVariables := len(point)
+	if nbVariables != vk.NbVariables || len(proof.Quotients) != nbVariables {
+		return ErrInvalidNbVariables
+	}
+
+	// derive the challenges y, x, z
+	fs := fiatshamir.NewTranscript(hf, "y", "x", "z")
+	y, err := deriveChallengeMultilinear(fs, digest, point, proof.ClaimedValue, proof.Quotients, dataTranscript...)
+	if err != nil {
+		return err
+	}
+	x, err := deriveChallengeZeromorph(fs, "x", proof.BatchedQuotient)
+	if err != nil {
+		return err
+	}
+	z, err := deriveChallengeZeromorph(fs, "z")
+	if err != nil {
+		return err
+	}
+
+	// [W] = [q̂] + z[f] - zvΦₙ(x)G₁ - ∑ₖ(yᵏx^{2ⁿ-2ᵏ} + z(x^{2ᵏ}Φₙ₋ₖ₋₁(x^{2ᵏ⁺¹}) - uₖΦₙ₋ₖ(x^{2ᵏ})))[qₖ]
+	scalars, phi := zeromorphScalars(point, x, y, z)
+	bases := make([]bls12378.G1Affine, nbVariables+3)
+	copy(bases, proof.Quotients)
+	for k := range scalars {
+		scalars[k].Neg(&scalars[k])
+	}
+	bases[nbVariables] = proof.BatchedQuotient
+	bases[nbVariables+1] = *digest
+	bases[nbVariables+2] = vk.Vk.G1
+	var t fr.Element
+	t.Mul(&proof.ClaimedValue, &phi).Mul(&t, &z).Neg(&t)
+	var one fr.Element
+	one.SetOne()
+	scalars = append(scalars, one, z, t)
+
+	var w Digest
+	if _, err := w.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	// W(x) = 0 and deg(W) < 2ⁿ:
+	// e([W], [τ^{N-2ⁿ+1}]G₂).e(x[H], G₂).e(-[H], [τ]G₂) == 1
+	var xH, negH bls12378.G1Affine
+	var bx big.Int
+	xH.ScalarMultiplication(&proof.H, x.BigInt(&bx))
+	negH.Neg(&proof.H)
+	check, err := bls12378.PairingCheck(
+		[]bls12378.G1Affine{w, xH, negH},
+		[]bls12378.G2Affine{vk.ShiftedG2, vk.Vk.G2[0], vk.Vk.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// BatchOpenMultilinear creates a Zeromorph opening proof of several multilinear
+// polynomials, with the same number of variables, at the same point.
+//
+// * polynomials is the list of multilinear polynomials to open, see OpenMultilinear
+// * digests is the list of committed polynomials to open, need to derive the challenges using Fiat Shamir
+// * dataTranscript extra data that might be needed to derive the challenges
+func BatchOpenMultilinear(polynomials [][]fr.Element, digests []Digest, point []fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (MultilinearBatchOpeningProof, error) {
+
+	var res MultilinearBatchOpeningProof
+	nbDigests := len(digests)
+	if nbDigests != len(polynomials) {
+		return res, ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return res, ErrZeroNbDigests
+	}
+	size := len(polynomials[0])
+	for i := range polynomials {
+		if len(polynomials[i]) != size {
+			return res, ErrInvalidPolynomialSize
+		}
+	}
+	if _, err := checkMultilinearSizes(size, point); err != nil {
+		return res, err
+	}
+
+	// compute the purported values
+	res.ClaimedValues = make([]fr.Element, nbDigests)
+	for i := range polynomials {
+		res.ClaimedValues[i] = evalMultilinear(polynomials[i], point)
+	}
+
+	// fold the polynomials and the digests
+	gamma, err := deriveGammaMultilinear(digests, point, res.ClaimedValues, hf, dataTranscript...)
+	if err != nil {
+		return res, err
+	}
+	folded := make([]fr.Element, size)
+	var t fr.Element
+	for i := nbDigests - 1; i >= 0; i-- {
+		for j := range folded {
+			t.Mul(&folded[j], &gamma)
+			folded[j].Add(&t, &polynomials[i][j])
+		}
+	}
+	foldedDigest, err := foldDigests(digests, gamma)
+	if err != nil {
+		return res, err
+	}
+
+	res.Folded, err = OpenMultilinear(folded, foldedDigest, point, hf, pk, dataTranscript...)
+	return res, err
+}
+
+// BatchVerifyMultilinear verifies a Zeromorph opening proof of several
+// multilinear polynomials at the same point.
+//
+// * dataTranscript extra data that might be needed to derive the challenges
+func BatchVerifyMultilinear(digests []Digest, proof *MultilinearBatchOpeningProof, point []fr.Element, hf hash.Hash, vk MultilinearVerifyingKey, dataTranscript ...[]byte) error {
+
+	nbDigests := len(digests)
+	if nbDigests != len(proof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return ErrZeroNbDigests
+	}
+
+	// fold the claimed values and the digests
+	gamma, err := deriveGammaMultilinear(digests, point, proof.ClaimedValues, hf, dataTranscript...)
+	if err != nil {
+		return err
+	}
+	var foldedValue fr.Element
+	for i := nbDigests - 1; i >= 0; i-- {
+		foldedValue.Mul(&foldedValue, &gamma).Add(&foldedValue, &proof.ClaimedValues[i])
+	}
+	if !foldedValue.Equal(&proof.Folded.ClaimedValue) {
+		return ErrVerifyOpeningProof
+	}
+	foldedDigest, err := foldDigests(digests, gamma)
+	if err != nil {
+		return err
+	}
+
+	return VerifyMultilinear(&foldedDigest, &proof.Folded, point, hf, vk, dataTranscript...)
+}
+
+// checkMultilinearSizes checks that size is 2ⁿ where n is the number of
+// coordinates of point, and returns n.
+func checkMultilinearSizes(size int, point []fr.Element) (int, error) {
+	if size == 0 || bits.OnesCount(uint(size)) != 1 {
+		return 0, ErrInvalidPolynomialSize
+	}
+	nbVariables := bits.TrailingZeros(uint(size))
+	if len(point) != nbVariables {
+		return 0, ErrInvalidNbVariables
+	}
+	return nbVariables, nil
+}
+
+// evalMultilinear returns the evaluation at point of the multilinear
+// polynomial whose evaluations on the hypercube are f.
+func evalMultilinear(f []fr.Element, point []fr.Element) fr.Element {
+	folded := make([]fr.Element, len(f))
+	copy(folded, f)
+	var t fr.Element
+	for _, u := range point {
+		half := len(folded) / 2
+		for i := 0; i < half; i++ {
+			t.Sub(&folded[i+half], &folded[i]).Mul(&t, &u)
+			folded[i].Add(&folded[i], &t)
+		}
+		folded = folded[:half]
+	}
+	return folded[0]
+}
+
+// zeromorphScalars returns the scalars yᵏx^{2ⁿ-2ᵏ} + z(x^{2ᵏ}Φₙ₋ₖ₋₁(x^{2ᵏ⁺¹}) - uₖΦₙ₋ₖ(x^{2ᵏ}))
+// by which the commitments to the quotients qₖ are multiplied, and Φₙ(x).
+func zeromorphScalars(point []fr.Element, x, y, z fr.Element) ([]fr.Element, fr.Element) {
+	nbVariables := len(point)
+
+	// xPow[k] = x^{2ᵏ}
+	xPow := make([]fr.Element, nbVariables+1)
+	xPow[0] = x
+	for k := 1; k <= nbVariables; k++ {
+		xPow[k].Square(&xPow[k-1])
+	}
+
+	// Φₙ₋ₖ(x^{2ᵏ}) = ∏_{k≤j<n}(1+x^{2ʲ}) = phi[k]
+	phi := make([]fr.Element, nbVariables+1)
+	phi[nbVariables].SetOne()
+	var one fr.Element
+	one.SetOne()
+	for k := nbVariables - 1; k >= 0; k-- {
+		phi[k].Add(&xPow[k], &one).Mul(&phi[k], &phi[k+1])
+	}
+
+	res := make([]fr.Element, nbVariables)
+	size := uint64(1) << nbVariables
+	var yK, t, e fr.Element
+	var exp big.Int
+	yK.SetOne()
+	for k := range res {
+		exp.SetUint64(size - (uint64(1) << k))
+		e.Exp(x, &exp)
+		res[k].Mul(&yK, &e)
+
+		u := point[nbVariables-1-k]
+		e.Mul(&xPow[k], &phi[k+1])
+		t.Mul(&u, &phi[k])
+		e.Sub(&e, &t).Mul(&e, &z)
+		res[k].Add(&res[k], &e)
+
+		yK.Mul(&yK, &y)
+	}
+
+	return res, phi[0]
+}
+
+// foldDigests returns ∑ᵢγⁱdigests[i]
+func foldDigests(digests []Digest, gamma fr.Element) (Digest, error) {
+	gammai := make([]fr.Element, len(digests))
+	gammai[0].SetOne()
+	for i := 1; i < len(digests); i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	var res Digest
+	if _, err := res.MultiExp(digests, gammai, ecc.MultiExpConfig{}); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// deriveGammaMultilinear derives the challenge γ used to fold the
+// polynomials, binded to the digests, the point and the claimed values.
+func deriveGammaMultilinear(digests []Digest, point, claimedValues []fr.Element, hf hash.Hash, dataTranscript ...[]byte) (fr.Element, error) {
+	fs := fiatshamir.NewTranscript(hf, "gamma")
+	return deriveChallengeMultiPoints(fs, fiatshamir.WithHash(hf, dataTranscript...), digests, [][]fr.Element{point}, [][]fr.Element{claimedValues})
+}
+
+// deriveChallengeMultilinear derives the challenge y, binded to the digest,
+// the point, the claimed value and the commitments to the quotients.
+func deriveChallengeMultilinear(fs *fiatshamir.Transcript, digest *Digest, point []fr.Element, claimedValue fr.Element, quotients []bls12378.G1Affine, dataTranscript ...[]byte) (fr.Element, error) {
+	if err := fs.Bind("y", digest.Marshal()); err != nil {
+		return fr.Element{}, err
+	}
+	for i := range point {
+		if err := fs.Bind("y", point[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	if err := fs.Bind("y", claimedValue.Marshal()); err != nil {
+		return fr.Element{}, err
+	}
+	for i := 0; i < len(dataTranscript); i++ {
+		if err := fs.Bind("y", dataTranscript[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	return deriveChallengeZeromorph(fs, "y", quotients...)
+}
+
+// deriveChallengeZeromorph derives the challenge named challenge, binded to
+// the points.
+func deriveChallengeZeromorph(fs *fiatshamir.Transcript, challenge string, points ...bls12378.G1Affine) (fr.Element, error) {
+	for i := range points {
+		if err := fs.Bind(challenge, points[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	b, err := fs.ComputeChallenge(challenge)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
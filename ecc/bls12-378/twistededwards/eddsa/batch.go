@@ -0,0 +1,137 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/twistededwards"
+)
+
+var errBatchSize = errors.New("the numbers of public keys, signatures and messages differ")
+
+// sizeRandomizer is the size in bytes of the random coefficients of the
+// batch verification, so that an invalid batch passes with probability 2⁻¹²⁸.
+const sizeRandomizer = 16
+
+// BatchVerify verifies the signatures sigs[i] of messages msgs[i] for the
+// public keys pubs[i] at once. It returns true and -1 if all the signatures are
+// valid, and false and the index of the first invalid signature otherwise.
+//
+// It checks a random linear combination of the verification equations
+//
+//	cofactor*(∑ zᵢSᵢ)*Base = cofactor*(∑ zᵢRᵢ + ∑ zᵢH(Rᵢ,Aᵢ,Mᵢ)Aᵢ)
+//
+// with a single multi-scalar multiplication, and falls back to verifying the
+// signatures one by one to find the invalid one if the check fails.
+func BatchVerify(pubs []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) (bool, int, error) {
+
+	// hFunc cannot be nil.
+	// We need a hash function for the Fiat-Shamir.
+	if hFunc == nil {
+		return false, -1, errHashNeeded
+	}
+	if len(pubs) != len(sigs) || len(pubs) != len(msgs) {
+		return false, -1, errBatchSize
+	}
+	if len(pubs) == 0 {
+		return true, -1, nil
+	}
+
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	// the points are Base, then -Rᵢ, then -Aᵢ
+	n := len(pubs)
+	points := make([]twistededwards.PointAffine, 2*n+1)
+	scalars := make([]big.Int, 2*n+1)
+	points[0].Set(&curveParams.Base)
+
+	var randomizer [sizeRandomizer]byte
+	var sig Signature
+	var s big.Int
+	for i := 0; i < n; i++ {
+		if !pubs[i].A.IsOnCurve() {
+			return false, i, errNotOnCurve
+		}
+		if _, err := sig.SetBytes(sigs[i]); err != nil {
+			return false, i, err
+		}
+		h, err := hram(hFunc, &sig.R, &pubs[i].A, msgs[i])
+		if err != nil {
+			return false, i, err
+		}
+
+		z := &scalars[1+i]
+		if _, err := rand.Read(randomizer[:]); err != nil {
+			return false, -1, err
+		}
+		z.SetBytes(randomizer[:])
+
+		s.SetBytes(sig.S[:])
+		s.Mul(&s, z)
+		scalars[0].Add(&scalars[0], &s)
+
+		scalars[1+n+i].Mul(z, &h).Mod(&scalars[1+n+i], &curveParams.Order)
+		points[1+i].Neg(&sig.R)
+		points[1+n+i].Neg(&pubs[i].A)
+	}
+	scalars[0].Mod(&scalars[0], &curveParams.Order)
+
+	// the small order components are cleared by the cofactor, so that the
+	// scalars can be reduced modulo the order of the subgroup
+	var bCofactor big.Int
+	curveParams.Cofactor.BigInt(&bCofactor)
+	var res twistededwards.PointExtended
+	if _, err := res.MultiExp(points, scalars); err != nil {
+		return false, -1, err
+	}
+	res.ScalarMultiplication(&res, &bCofactor)
+	if res.IsZero() {
+		return true, -1, nil
+	}
+
+	// find the invalid signature
+	for i := 0; i < n; i++ {
+		ok, err := pubs[i].Verify(sigs[i], msgs[i], hFunc)
+		if err != nil || !ok {
+			return false, i, err
+		}
+	}
+	// not reachable, unless the randomizers are not random
+	return false, -1, nil
+}
+
+// hram returns H(R, A, M), as in Sign and Verify.
+func hram(hFunc hash.Hash, r, a *twistededwards.PointAffine, message []byte) (big.Int, error) {
+	hFunc.Reset()
+	rX := r.X.Bytes()
+	rY := r.Y.Bytes()
+	aX := a.X.Bytes()
+	aY := a.Y.Bytes()
+	toWrite := [][]byte{rX[:], rY[:], aX[:], aY[:], message}
+	for _, bytes := range toWrite {
+		if _, err := hFunc.Write(bytes); err != nil {
+			return big.Int{}, err
+		}
+	}
+	var res big.Int
+	res.SetBytes(hFunc.Sum(nil))
+	return res, nil
+}
@@ -709,6 +709,41 @@ func TestOps(t *testing.T) {
 		genS1,
 	))
 
+	properties.Property("constant time scalar multiplication should match ScalarMultiplication", prop.ForAll(
+		func(s big.Int) bool {
+
+			params := GetEdwardsCurve()
+
+			var p1, p2 PointAffine
+			p1.ScalarMultiplication(&params.Base, &s)
+			p2.ScalarMultiplicationConstantTime(&params.Base, &s)
+
+			return p1.Equal(&p2)
+		},
+		genS1,
+	))
+
+	properties.Property("constant time scalar multiplication by 0, order and order-1", prop.ForAll(
+		func() bool {
+
+			params := GetEdwardsCurve()
+
+			var p, zero, neg PointAffine
+			zero.setInfinity()
+			neg.Neg(&params.Base)
+
+			var s big.Int
+			if !p.ScalarMultiplicationConstantTime(&params.Base, &s).Equal(&zero) {
+				return false
+			}
+			if !p.ScalarMultiplicationConstantTime(&params.Base, &params.Order).Equal(&zero) {
+				return false
+			}
+			s.Sub(&params.Order, big.NewInt(1))
+			return p.ScalarMultiplicationConstantTime(&params.Base, &s).Equal(&neg)
+		},
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
@@ -729,6 +764,41 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMultiExp(t *testing.T) {
+	t.Parallel()
+	initOnce.Do(initCurveParams)
+
+	for _, size := range []int{1, 5, 70} {
+		points := make([]PointAffine, size)
+		scalars := make([]big.Int, size)
+		var expected, p PointExtended
+		expected.setInfinity()
+		var b [fr.Bytes]byte
+		for i := 0; i < size; i++ {
+			_, _ = rand.Read(b[:]) //#nosec G404 weak rng is fine here
+			scalars[i].SetBytes(b[:i%fr.Bytes+1])
+			_, _ = rand.Read(b[:]) //#nosec G404 weak rng is fine here
+			var s big.Int
+			s.SetBytes(b[:])
+			points[i].ScalarMultiplication(&curveParams.Base, &s)
+			p.FromAffine(&points[i])
+			p.ScalarMultiplication(&p, &scalars[i])
+			expected.Add(&expected, &p)
+		}
+		if _, err := p.MultiExp(points, scalars); err != nil {
+			t.Fatal(err)
+		}
+		if !p.Equal(&expected) {
+			t.Fatal("MultiExp is not consistent with ScalarMultiplication")
+		}
+	}
+
+	var p PointExtended
+	if _, err := p.MultiExp(make([]PointAffine, 2), make([]big.Int, 1)); err == nil {
+		t.Fatal("expected an error when len(points) != len(scalars)")
+	}
+}
+
 // GenBigInt generates a big.Int
 // TODO @thomas we use fr size as max bound here
 func GenBigInt() gopter.Gen {
@@ -810,6 +880,22 @@ func BenchmarkScalarMulExtended(b *testing.B) {
 	}
 }
 
+func BenchmarkScalarMulConstantTime(b *testing.B) {
+	params := GetEdwardsCurve()
+	var a PointExtended
+	var s big.Int
+	a.FromAffine(&params.Base)
+	s.SetString("52435875175126190479447705081859658376581184513", 10)
+	s.Add(&s, &params.Order)
+
+	var res PointExtended
+
+	b.ResetTimer()
+	for j := 0; j < b.N; j++ {
+		res.ScalarMultiplicationConstantTime(&a, &s)
+	}
+}
+
 func BenchmarkScalarMulProjective(b *testing.B) {
 	params := GetEdwardsCurve()
 	var a PointProj
@@ -0,0 +1,665 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/twistededwards"
+	"golang.org/x/crypto/blake2b"
+)
+
+// FROST threshold signatures (https://eprint.iacr.org/2020/852).
+//
+// A group key is split between n participants, identified by 1..n, so that
+// any threshold of them can produce a signature which verifies with
+// PublicKey.Verify for the group key. The key is either split by a trusted
+// dealer (GenerateKeyShares), or generated by the participants themselves
+// (NewDKGParticipant). Signing takes two rounds:
+//
+//  1. each signer calls KeyShare.Commit and sends the NonceCommitment to the
+//     coordinator, who forwards the list of commitments to the signers;
+//  2. each signer calls KeyShare.SignShare and sends the SignatureShare to the
+//     coordinator, who aggregates the shares with Aggregate.
+//
+// The scalars are taken modulo the order of the prime subgroup. The nonces,
+// the binding factors and the DKG challenges are hashed with blake2b, domain
+// separated by frostDST; the challenge is computed with the hash function of
+// the eddsa signature, so that the aggregated signature is a regular eddsa
+// signature.
+// RFC 9591 defines no ciphersuite for this curve, and this hashing is not the
+// one of its ciphersuites: the protocol messages are not interoperable with
+// RFC 9591 implementations.
+
+var (
+	errInvalidThreshold        = errors.New("invalid threshold: must be in [1, n]")
+	errInvalidParticipant      = errors.New("invalid participant identifier")
+	errInvalidCommitment       = errors.New("invalid commitment")
+	errInvalidKeyShare         = errors.New("key share doesn't match the commitment")
+	errInvalidProofOfKnowledge = errors.New("invalid proof of knowledge")
+	errInvalidSecretShare      = errors.New("secret share doesn't match the commitment")
+	errInvalidSignatureShare   = errors.New("invalid signature share")
+	errNoncesUsed              = errors.New("signing nonces already used")
+)
+
+const frostDST = "FROST-EDDSA-bls12-381-"
+
+// KeyShare is the share of a participant of a threshold key.
+type KeyShare struct {
+	ID                uint64                     // identifier of the participant, in [1, n]
+	Secret            big.Int                    // secret share f(ID) of the group secret f(0)
+	VerificationShare twistededwards.PointAffine // Secret*Base
+	GroupKey          PublicKey                  // f(0)*Base
+}
+
+// GenerateKeyShares generates a random key, and splits it between n
+// participants so that any threshold of them can sign. It returns the key
+// shares, where shares[i] is the share of the participant i+1, and the
+// commitment to the sharing polynomial, that the participants use to check
+// their share with VerifyKeyShare.
+//
+// The dealer learns the secret key, and must erase it.
+func GenerateKeyShares(r io.Reader, threshold, n int) ([]KeyShare, []twistededwards.PointAffine, error) {
+	if threshold < 1 || threshold > n {
+		return nil, nil, errInvalidThreshold
+	}
+	coefficients, err := randomPolynomial(r, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	commitment := commitPolynomial(coefficients)
+
+	curveParams := twistededwards.GetEdwardsCurve()
+	shares := make([]KeyShare, n)
+	for i := range shares {
+		shares[i].ID = uint64(i + 1)
+		shares[i].Secret = evalPolynomial(coefficients, shares[i].ID)
+		shares[i].VerificationShare.ScalarMultiplicationConstantTime(&curveParams.Base, &shares[i].Secret)
+		shares[i].GroupKey.A.Set(&commitment[0])
+	}
+	return shares, commitment, nil
+}
+
+// VerifyKeyShare checks that the key share is consistent with the commitment
+// to the sharing polynomial.
+func VerifyKeyShare(share *KeyShare, commitment []twistededwards.PointAffine) error {
+	if len(commitment) == 0 || share.ID == 0 {
+		return errInvalidKeyShare
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+	var expected twistededwards.PointAffine
+	expected.ScalarMultiplicationConstantTime(&curveParams.Base, &share.Secret)
+	if !expected.Equal(&share.VerificationShare) || !share.GroupKey.A.Equal(&commitment[0]) {
+		return errInvalidKeyShare
+	}
+	if !evalCommitment(commitment, share.ID).Equal(&expected) {
+		return errInvalidKeyShare
+	}
+	return nil
+}
+
+// ComputeVerificationShare returns the verification share of the participant
+// id from the commitment to the sharing polynomial. The coordinator uses it to
+// check the signature shares with VerifySignatureShare.
+func ComputeVerificationShare(commitment []twistededwards.PointAffine, id uint64) twistededwards.PointAffine {
+	return *evalCommitment(commitment, id)
+}
+
+// DKGCommitment returns the commitment to the sharing polynomial of the group
+// key generated by the distributed key generation: the sum of the commitments
+// of the participants. The messages must have been checked by Round2 or
+// Finalize.
+func DKGCommitment(round1 []DKGRound1) []twistededwards.PointAffine {
+	if len(round1) == 0 {
+		return nil
+	}
+	res := make([]twistededwards.PointAffine, len(round1[0].Commitment))
+	for i := range res {
+		res[i].Set(&round1[0].Commitment[i])
+		for j := 1; j < len(round1); j++ {
+			res[i].Add(&res[i], &round1[j].Commitment[i])
+		}
+	}
+	return res
+}
+
+// DKGParticipant is the state of a participant in the Pedersen distributed
+// key generation, in which no party learns the group secret.
+//
+// Each participant calls NewDKGParticipant and broadcasts the returned
+// DKGRound1 message. Once it received the n messages, it calls Round2 and
+// sends each DKGShare privately to its recipient. Finally, it calls Finalize
+// with the n round 1 messages and the n-1 shares it received.
+type DKGParticipant struct {
+	id           uint64
+	threshold, n int
+	coefficients []big.Int
+}
+
+// DKGRound1 is the message broadcast by a participant in the first round of
+// the distributed key generation: the commitment to its secret polynomial, and
+// a Schnorr proof (R, Z) of knowledge of its constant term.
+type DKGRound1 struct {
+	ID         uint64
+	Commitment []twistededwards.PointAffine
+	R          twistededwards.PointAffine
+	Z          big.Int
+}
+
+// DKGShare is the message sent privately by a participant to another one in
+// the second round of the distributed key generation: the evaluation of the
+// secret polynomial of From at To.
+type DKGShare struct {
+	From, To uint64
+	Value    big.Int
+}
+
+// NewDKGParticipant returns the state of the participant id in [1, n] of the
+// distributed generation of a threshold-of-n key, and its round 1 message.
+func NewDKGParticipant(r io.Reader, id uint64, threshold, n int) (*DKGParticipant, DKGRound1, error) {
+	if threshold < 1 || threshold > n {
+		return nil, DKGRound1{}, errInvalidThreshold
+	}
+	if id == 0 || id > uint64(n) {
+		return nil, DKGRound1{}, errInvalidParticipant
+	}
+	coefficients, err := randomPolynomial(r, threshold)
+	if err != nil {
+		return nil, DKGRound1{}, err
+	}
+	msg := DKGRound1{
+		ID:         id,
+		Commitment: commitPolynomial(coefficients),
+	}
+
+	// proof of knowledge of coefficients[0], to prevent rogue key attacks
+	k, err := randomScalar(r)
+	if err != nil {
+		return nil, DKGRound1{}, err
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+	msg.R.ScalarMultiplicationConstantTime(&curveParams.Base, &k)
+	c := dkgChallenge(id, &msg.Commitment[0], &msg.R)
+	msg.Z.Mul(&c, &coefficients[0]).
+		Add(&msg.Z, &k).
+		Mod(&msg.Z, &curveParams.Order)
+
+	p := &DKGParticipant{
+		id:           id,
+		threshold:    threshold,
+		n:            n,
+		coefficients: coefficients,
+	}
+	return p, msg, nil
+}
+
+// Round2 checks the round 1 messages of all the participants, and returns
+// the shares to send to the other participants.
+func (p *DKGParticipant) Round2(round1 []DKGRound1) ([]DKGShare, error) {
+	if p.coefficients == nil {
+		return nil, errInvalidParticipant
+	}
+	if err := p.checkRound1(round1); err != nil {
+		return nil, err
+	}
+	shares := make([]DKGShare, 0, p.n-1)
+	for to := uint64(1); to <= uint64(p.n); to++ {
+		if to == p.id {
+			continue
+		}
+		shares = append(shares, DKGShare{
+			From:  p.id,
+			To:    to,
+			Value: evalPolynomial(p.coefficients, to),
+		})
+	}
+	return shares, nil
+}
+
+// Finalize checks the shares received from the other participants against
+// their round 1 messages, and returns the key share of the participant. The
+// secret polynomial of the participant is erased.
+func (p *DKGParticipant) Finalize(round1 []DKGRound1, shares []DKGShare) (KeyShare, error) {
+	if p.coefficients == nil {
+		return KeyShare{}, errInvalidParticipant
+	}
+	if err := p.checkRound1(round1); err != nil {
+		return KeyShare{}, err
+	}
+	if len(shares) != p.n-1 {
+		return KeyShare{}, errInvalidSecretShare
+	}
+
+	curveParams := twistededwards.GetEdwardsCurve()
+	res := KeyShare{ID: p.id}
+	res.Secret = evalPolynomial(p.coefficients, p.id)
+	received := make(map[uint64]bool, len(shares))
+	for i := range shares {
+		from := shares[i].From
+		if shares[i].To != p.id || from == 0 || from > uint64(p.n) || from == p.id || received[from] {
+			return KeyShare{}, errInvalidSecretShare
+		}
+		received[from] = true
+		var expected twistededwards.PointAffine
+		expected.ScalarMultiplicationConstantTime(&curveParams.Base, &shares[i].Value)
+		if !evalCommitment(round1[from-1].Commitment, p.id).Equal(&expected) {
+			return KeyShare{}, fmt.Errorf("participant %d: %w", from, errInvalidSecretShare)
+		}
+		res.Secret.Add(&res.Secret, &shares[i].Value)
+	}
+	res.Secret.Mod(&res.Secret, &curveParams.Order)
+	res.VerificationShare.ScalarMultiplicationConstantTime(&curveParams.Base, &res.Secret)
+
+	res.GroupKey.A.Set(&round1[0].Commitment[0])
+	for i := 1; i < len(round1); i++ {
+		res.GroupKey.A.Add(&res.GroupKey.A, &round1[i].Commitment[0])
+	}
+
+	for i := range p.coefficients {
+		p.coefficients[i].SetUint64(0)
+	}
+	p.coefficients = nil
+	return res, nil
+}
+
+// checkRound1 checks that round1 contains the messages of the participants
+// 1..n, in order, and verifies their commitments and proofs of knowledge.
+func (p *DKGParticipant) checkRound1(round1 []DKGRound1) error {
+	if len(round1) != p.n {
+		return errInvalidCommitment
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+	for i := range round1 {
+		msg := &round1[i]
+		if msg.ID != uint64(i+1) || len(msg.Commitment) != p.threshold {
+			return fmt.Errorf("participant %d: %w", i+1, errInvalidCommitment)
+		}
+		for j := range msg.Commitment {
+			if !isInSubGroup(&msg.Commitment[j]) {
+				return fmt.Errorf("participant %d: %w", msg.ID, errInvalidCommitment)
+			}
+		}
+		if !isInSubGroup(&msg.R) || msg.Z.Sign() < 0 || msg.Z.Cmp(&curveParams.Order) >= 0 {
+			return fmt.Errorf("participant %d: %w", msg.ID, errInvalidProofOfKnowledge)
+		}
+
+		// Z*Base == R + c*Commitment[0]
+		c := dkgChallenge(msg.ID, &msg.Commitment[0], &msg.R)
+		var lhs, rhs twistededwards.PointAffine
+		lhs.ScalarMultiplication(&curveParams.Base, &msg.Z)
+		rhs.ScalarMultiplication(&msg.Commitment[0], &c).Add(&rhs, &msg.R)
+		if !lhs.Equal(&rhs) {
+			return fmt.Errorf("participant %d: %w", msg.ID, errInvalidProofOfKnowledge)
+		}
+	}
+	return nil
+}
+
+// NonceCommitment is the message sent by a signer in the first round of the
+// signature: the commitments to its hiding and binding nonces.
+type NonceCommitment struct {
+	ID              uint64
+	Hiding, Binding twistededwards.PointAffine
+}
+
+// SigningNonces are the secret nonces of a signer for a single signature.
+// They must not be reused.
+type SigningNonces struct {
+	hiding, binding big.Int
+	commitment      NonceCommitment
+	used            bool
+}
+
+// SignatureShare is the message sent by a signer in the second round of the
+// signature.
+type SignatureShare struct {
+	ID uint64
+	Z  big.Int
+}
+
+// Commit generates the nonces of the signer for a new signature, and returns
+// them with the commitment to send to the coordinator.
+func (ks *KeyShare) Commit(r io.Reader) (*SigningNonces, NonceCommitment, error) {
+	var res SigningNonces
+	var err error
+	if res.hiding, err = ks.nonce(r); err != nil {
+		return nil, NonceCommitment{}, err
+	}
+	if res.binding, err = ks.nonce(r); err != nil {
+		return nil, NonceCommitment{}, err
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+	res.commitment.ID = ks.ID
+	res.commitment.Hiding.ScalarMultiplicationConstantTime(&curveParams.Base, &res.hiding)
+	res.commitment.Binding.ScalarMultiplicationConstantTime(&curveParams.Base, &res.binding)
+	return &res, res.commitment, nil
+}
+
+// nonce returns H(random || secret), so that a weak source of randomness
+// doesn't leak the secret share.
+func (ks *KeyShare) nonce(r io.Reader) (big.Int, error) {
+	var random [32]byte
+	if _, err := io.ReadFull(r, random[:]); err != nil {
+		return big.Int{}, err
+	}
+	return hashToScalar("nonce", random[:], scalarBytes(&ks.Secret)), nil
+}
+
+// SignShare returns the signature share of the signer on message, given the
+// commitments of all the signers. The nonces are erased, and can't be used
+// again.
+func (ks *KeyShare) SignShare(nonces *SigningNonces, commitments []NonceCommitment, message []byte, hFunc hash.Hash) (SignatureShare, error) {
+	if hFunc == nil {
+		return SignatureShare{}, errHashNeeded
+	}
+	if nonces == nil || nonces.used {
+		return SignatureShare{}, errNoncesUsed
+	}
+	s, err := newSigningSession(&ks.GroupKey, commitments, message, hFunc)
+	if err != nil {
+		return SignatureShare{}, err
+	}
+	i := s.index(ks.ID)
+	if i < 0 || s.commitments[i] != nonces.commitment {
+		return SignatureShare{}, errInvalidCommitment
+	}
+
+	// z = hiding + binding*rho + lambda*secret*c
+	curveParams := twistededwards.GetEdwardsCurve()
+	lambda := s.lagrangeCoefficient(i)
+	res := SignatureShare{ID: ks.ID}
+	res.Z.Mul(&lambda, &ks.Secret).
+		Mul(&res.Z, &s.challenge).
+		Add(&res.Z, &nonces.hiding)
+	var t big.Int
+	t.Mul(&nonces.binding, &s.bindingFactors[i])
+	res.Z.Add(&res.Z, &t).Mod(&res.Z, &curveParams.Order)
+
+	nonces.hiding.SetUint64(0)
+	nonces.binding.SetUint64(0)
+	nonces.used = true
+	return res, nil
+}
+
+// VerifySignatureShare checks the signature share of a signer, with
+// verification share verificationShare, on message. It allows the
+// coordinator to identify a misbehaving signer.
+func VerifySignatureShare(share *SignatureShare, verificationShare *twistededwards.PointAffine, groupKey *PublicKey, commitments []NonceCommitment, message []byte, hFunc hash.Hash) error {
+	if hFunc == nil {
+		return errHashNeeded
+	}
+	s, err := newSigningSession(groupKey, commitments, message, hFunc)
+	if err != nil {
+		return err
+	}
+	i := s.index(share.ID)
+	if i < 0 {
+		return errInvalidParticipant
+	}
+
+	// z*Base == Hiding + rho*Binding + (c*lambda)*VerificationShare
+	curveParams := twistededwards.GetEdwardsCurve()
+	lambda := s.lagrangeCoefficient(i)
+	lambda.Mul(&lambda, &s.challenge).Mod(&lambda, &curveParams.Order)
+	var lhs, rhs, t twistededwards.PointAffine
+	lhs.ScalarMultiplication(&curveParams.Base, &share.Z)
+	rhs.ScalarMultiplication(verificationShare, &lambda)
+	t.ScalarMultiplication(&s.commitments[i].Binding, &s.bindingFactors[i])
+	rhs.Add(&rhs, &t).Add(&rhs, &s.commitments[i].Hiding)
+	if !lhs.Equal(&rhs) {
+		return fmt.Errorf("participant %d: %w", share.ID, errInvalidSignatureShare)
+	}
+	return nil
+}
+
+// Aggregate returns the signature on message for the group key, from the
+// signature shares of all the signers. The signature is an eddsa signature,
+// to verify with groupKey.Verify(signature, message, hFunc). If it doesn't
+// verify, VerifySignatureShare identifies the misbehaving signers.
+func Aggregate(groupKey *PublicKey, commitments []NonceCommitment, shares []SignatureShare, message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return nil, errHashNeeded
+	}
+	s, err := newSigningSession(groupKey, commitments, message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) != len(s.commitments) {
+		return nil, errInvalidSignatureShare
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+	seen := make([]bool, len(s.commitments))
+	var z big.Int
+	for i := range shares {
+		j := s.index(shares[i].ID)
+		if j < 0 || seen[j] {
+			return nil, errInvalidSignatureShare
+		}
+		seen[j] = true
+		z.Add(&z, &shares[i].Z)
+	}
+	z.Mod(&z, &curveParams.Order)
+
+	var sig Signature
+	sig.R.Set(&s.groupCommitment)
+	z.FillBytes(sig.S[:])
+	return sig.Bytes(), nil
+}
+
+// signingSession stores the values derived from the commitments of the
+// signers, common to SignShare, VerifySignatureShare and Aggregate.
+type signingSession struct {
+	commitments     []NonceCommitment // sorted by ID
+	bindingFactors  []big.Int
+	groupCommitment twistededwards.PointAffine
+	challenge       big.Int
+}
+
+func newSigningSession(groupKey *PublicKey, commitments []NonceCommitment, message []byte, hFunc hash.Hash) (*signingSession, error) {
+	if len(commitments) == 0 {
+		return nil, errInvalidCommitment
+	}
+	s := &signingSession{
+		commitments:    make([]NonceCommitment, len(commitments)),
+		bindingFactors: make([]big.Int, len(commitments)),
+	}
+	copy(s.commitments, commitments)
+	sort.Slice(s.commitments, func(i, j int) bool {
+		return s.commitments[i].ID < s.commitments[j].ID
+	})
+	for i := range s.commitments {
+		c := &s.commitments[i]
+		if c.ID == 0 || (i > 0 && c.ID == s.commitments[i-1].ID) {
+			return nil, errInvalidParticipant
+		}
+		if !isInSubGroup(&c.Hiding) || !isInSubGroup(&c.Binding) {
+			return nil, fmt.Errorf("participant %d: %w", c.ID, errInvalidCommitment)
+		}
+	}
+
+	// rho_i = H(A || H(message) || H(commitments) || i)
+	pk := groupKey.A.Bytes()
+	msgHash := blake2b.Sum512(message)
+	h, _ := blake2b.New512(nil)
+	var buf [8]byte
+	for i := range s.commitments {
+		binary.BigEndian.PutUint64(buf[:], s.commitments[i].ID)
+		hiding := s.commitments[i].Hiding.Bytes()
+		binding := s.commitments[i].Binding.Bytes()
+		h.Write(buf[:])
+		h.Write(hiding[:])
+		h.Write(binding[:])
+	}
+	commitmentsHash := h.Sum(nil)
+
+	// R = sum(Hiding_i + rho_i*Binding_i)
+	var t twistededwards.PointAffine
+	s.groupCommitment.X.SetZero()
+	s.groupCommitment.Y.SetOne()
+	for i := range s.commitments {
+		binary.BigEndian.PutUint64(buf[:], s.commitments[i].ID)
+		s.bindingFactors[i] = hashToScalar("rho", pk[:], msgHash[:], commitmentsHash, buf[:])
+		t.ScalarMultiplication(&s.commitments[i].Binding, &s.bindingFactors[i])
+		s.groupCommitment.Add(&s.groupCommitment, &t).
+			Add(&s.groupCommitment, &s.commitments[i].Hiding)
+	}
+
+	// c = H(R, A, M), as in Sign
+	var err error
+	if s.challenge, err = hram(hFunc, &s.groupCommitment, &groupKey.A, message); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// index returns the position of the signer id in s.commitments, or -1.
+func (s *signingSession) index(id uint64) int {
+	i := sort.Search(len(s.commitments), func(i int) bool {
+		return s.commitments[i].ID >= id
+	})
+	if i == len(s.commitments) || s.commitments[i].ID != id {
+		return -1
+	}
+	return i
+}
+
+// lagrangeCoefficient returns the Lagrange coefficient at 0 of the i-th
+// signer, relatively to the set of signers.
+func (s *signingSession) lagrangeCoefficient(i int) big.Int {
+	curveParams := twistededwards.GetEdwardsCurve()
+	var num, den, xi, xj, t big.Int
+	num.SetUint64(1)
+	den.SetUint64(1)
+	xi.SetUint64(s.commitments[i].ID)
+	for j := range s.commitments {
+		if j == i {
+			continue
+		}
+		xj.SetUint64(s.commitments[j].ID)
+		num.Mul(&num, &xj).Mod(&num, &curveParams.Order)
+		t.Sub(&xj, &xi)
+		den.Mul(&den, &t).Mod(&den, &curveParams.Order)
+	}
+	den.ModInverse(&den, &curveParams.Order)
+	num.Mul(&num, &den).Mod(&num, &curveParams.Order)
+	return num
+}
+
+// randomPolynomial returns the coefficients of a random polynomial of degree
+// threshold-1.
+func randomPolynomial(r io.Reader, threshold int) ([]big.Int, error) {
+	res := make([]big.Int, threshold)
+	for i := range res {
+		var err error
+		if res[i], err = randomScalar(r); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func randomScalar(r io.Reader) (big.Int, error) {
+	var buf [64]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return big.Int{}, err
+	}
+	return hashToScalar("random", buf[:]), nil
+}
+
+// evalPolynomial returns the evaluation at x of the polynomial of the given
+// coefficients, modulo the order of the curve.
+func evalPolynomial(coefficients []big.Int, x uint64) big.Int {
+	curveParams := twistededwards.GetEdwardsCurve()
+	var res, bx big.Int
+	bx.SetUint64(x)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		res.Mul(&res, &bx).
+			Add(&res, &coefficients[i]).
+			Mod(&res, &curveParams.Order)
+	}
+	return res
+}
+
+func commitPolynomial(coefficients []big.Int) []twistededwards.PointAffine {
+	curveParams := twistededwards.GetEdwardsCurve()
+	res := make([]twistededwards.PointAffine, len(coefficients))
+	for i := range coefficients {
+		res[i].ScalarMultiplicationConstantTime(&curveParams.Base, &coefficients[i])
+	}
+	return res
+}
+
+// evalCommitment returns sum(commitment[k]*x^k), which is f(x)*Base if
+// commitment is the commitment to f.
+func evalCommitment(commitment []twistededwards.PointAffine, x uint64) *twistededwards.PointAffine {
+	var res twistededwards.PointAffine
+	var bx big.Int
+	bx.SetUint64(x)
+	res.Set(&commitment[len(commitment)-1])
+	for i := len(commitment) - 2; i >= 0; i-- {
+		res.ScalarMultiplication(&res, &bx).Add(&res, &commitment[i])
+	}
+	return &res
+}
+
+// dkgChallenge returns the challenge of the proof of knowledge of the constant
+// term of the polynomial of participant id.
+func dkgChallenge(id uint64, commitment, r *twistededwards.PointAffine) big.Int {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], id)
+	c := commitment.Bytes()
+	rb := r.Bytes()
+	return hashToScalar("dkg", buf[:], c[:], rb[:])
+}
+
+// hashToScalar returns blake2b(frostDST || tag || data) modulo the order of
+// the curve.
+func hashToScalar(tag string, data ...[]byte) big.Int {
+	curveParams := twistededwards.GetEdwardsCurve()
+	h, _ := blake2b.New512(nil)
+	h.Write([]byte(frostDST + tag))
+	for i := range data {
+		h.Write(data[i])
+	}
+	var res big.Int
+	res.SetBytes(h.Sum(nil)).Mod(&res, &curveParams.Order)
+	return res
+}
+
+func scalarBytes(s *big.Int) []byte {
+	res := make([]byte, sizeFr)
+	s.FillBytes(res)
+	return res
+}
+
+// isInSubGroup returns true if p is on the curve and in the prime subgroup.
+func isInSubGroup(p *twistededwards.PointAffine) bool {
+	if !p.IsOnCurve() {
+		return false
+	}
+	curveParams := twistededwards.GetEdwardsCurve()
+	var q twistededwards.PointAffine
+	q.ScalarMultiplication(p, &curveParams.Order)
+	return q.IsZero()
+}
@@ -0,0 +1,553 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidSRSSize        = errors.New("the size of the SRS must be a power of two")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (larger than SRS or == 0)")
+	ErrInvalidNbRounds       = errors.New("the number of rounds of the proof does not match the size of the SRS")
+	ErrInvalidNbProofs       = errors.New("the numbers of digests, proofs and points differ")
+	ErrZeroChallenge         = errors.New("a challenge of the transcript is zero")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+)
+
+// domain separation tags of the hash to curve of the bases
+const (
+	dstG = "IPA-BLS12-381-BANDERSNATCH-G"
+	dstQ = "IPA-BLS12-381-BANDERSNATCH-Q"
+)
+
+// Digest commitment of a polynomial.
+type Digest = bandersnatch.PointAffine
+
+// SRS public parameters of the scheme. They are derived from a public seed, no
+// trusted setup is needed.
+type SRS struct {
+	// G bases of the Pedersen vector commitment
+	G []bandersnatch.PointAffine
+
+	// Q base to which the inner product is bound
+	Q bandersnatch.PointAffine
+}
+
+// OpeningProof IPA proof of the evaluation of a polynomial at a point.
+type OpeningProof struct {
+	// L, R cross terms of the successive rounds
+	L, R []bandersnatch.PointAffine
+
+	// A the polynomial, folded to a single coefficient
+	A big.Int
+
+	// ClaimedValue purported value
+	ClaimedValue big.Int
+}
+
+// NewSRS returns public parameters to commit to polynomials of degree < size.
+// size must be a power of two. The bases are the hashes to the curve of
+// seed||i, in the prime order subgroup.
+func NewSRS(size uint64, seed []byte) (*SRS, error) {
+	if size == 0 || bits.OnesCount64(size) != 1 {
+		return nil, ErrInvalidSRSSize
+	}
+
+	var srs SRS
+	var err error
+	if srs.Q, err = hashToCurve(seed, []byte(dstQ)); err != nil {
+		return nil, err
+	}
+
+	srs.G = make([]bandersnatch.PointAffine, size)
+	errs := make([]error, size)
+	parallel.Execute(int(size), func(start, end int) {
+		msg := make([]byte, len(seed)+8)
+		copy(msg, seed)
+		for i := start; i < end; i++ {
+			binary.BigEndian.PutUint64(msg[len(seed):], uint64(i))
+			srs.G[i], errs[i] = hashToCurve(msg, []byte(dstG))
+		}
+	})
+	for i := range errs {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+	}
+
+	return &srs, nil
+}
+
+// Commit commits to a polynomial p, given by its coefficients, as ∑ᵢpᵢGᵢ.
+func Commit(p []big.Int, srs *SRS) (Digest, error) {
+	if len(p) == 0 || len(p) > len(srs.G) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	curve := bandersnatch.GetEdwardsCurve()
+	scalars := make([]big.Int, len(p))
+	for i := range p {
+		scalars[i].Mod(&p[i], &curve.Order)
+	}
+
+	var res bandersnatch.PointExtended
+	if _, err := res.MultiExp(srs.G[:len(p)], scalars); err != nil {
+		return Digest{}, err
+	}
+	var digest Digest
+	digest.FromExtended(&res)
+	return digest, nil
+}
+
+// Open computes an opening proof of the polynomial p at a given point.
+// It's an interactive protocol, made non-interactive using Fiat Shamir.
+//
+// * digest is the commitment to p, needed to derive the challenges
+// * dataTranscript extra data that might be needed to derive the challenges
+//
+// With a = p, b = (1, point, point², …) and G = srs.G, each round halves the
+// vectors: a ← a_L + xa_R, b ← b_L + x⁻¹b_R and G ← G_L + x⁻¹G_R, where x is
+// derived from the cross terms L = ⟨a_R, G_L⟩ + ⟨a_R, b_L⟩Q' and
+// R = ⟨a_L, G_R⟩ + ⟨a_L, b_R⟩Q', with Q' = wQ.
+func Open(p []big.Int, digest Digest, point *big.Int, hf hash.Hash, srs *SRS, dataTranscript ...[]byte) (OpeningProof, error) {
+
+	var res OpeningProof
+	n := len(srs.G)
+	if len(p) == 0 || len(p) > n {
+		return res, ErrInvalidPolynomialSize
+	}
+	nbRounds := bits.TrailingZeros(uint(n))
+	curve := bandersnatch.GetEdwardsCurve()
+	order := &curve.Order
+
+	var z big.Int
+	z.Mod(point, order)
+	a := make([]big.Int, n)
+	for i := range p {
+		a[i].Mod(&p[i], order)
+	}
+	b := make([]big.Int, n)
+	b[0].SetUint64(1)
+	for i := 1; i < n; i++ {
+		b[i].Mul(&b[i-1], &z).Mod(&b[i], order)
+	}
+	innerProduct(&res.ClaimedValue, a, b, order)
+
+	// Q' = wQ
+	fs := fiatshamir.NewTranscript(hf, challengeNames(nbRounds)...)
+	w, err := deriveChallengeW(fs, &digest, &z, &res.ClaimedValue, order, dataTranscript...)
+	if err != nil {
+		return res, err
+	}
+	var q bandersnatch.PointAffine
+	q.ScalarMultiplication(&srs.Q, &w)
+
+	g := make([]bandersnatch.PointAffine, n)
+	copy(g, srs.G)
+	res.L = make([]bandersnatch.PointAffine, nbRounds)
+	res.R = make([]bandersnatch.PointAffine, nbRounds)
+	var x, xInv, t big.Int
+	for round := 0; round < nbRounds; round++ {
+		m := len(a) / 2
+		aL, aR := a[:m], a[m:]
+		bL, bR := b[:m], b[m:]
+		gL, gR := g[:m], g[m:]
+
+		if res.L[round], err = crossTerm(gL, aR, bL, &q, order); err != nil {
+			return res, err
+		}
+		if res.R[round], err = crossTerm(gR, aL, bR, &q, order); err != nil {
+			return res, err
+		}
+		if x, err = deriveChallengeX(fs, round, &res.L[round], &res.R[round], order); err != nil {
+			return res, err
+		}
+		xInv.ModInverse(&x, order)
+
+		for i := 0; i < m; i++ {
+			t.Mul(&aR[i], &x)
+			aL[i].Add(&aL[i], &t).Mod(&aL[i], order)
+			t.Mul(&bR[i], &xInv)
+			bL[i].Add(&bL[i], &t).Mod(&bL[i], order)
+		}
+		g = foldBases(gL, gR, &xInv)
+		a, b = aL, bL
+	}
+	res.A.Set(&a[0])
+
+	return res, nil
+}
+
+// Verify verifies an opening proof of the polynomial committed in digest at
+// point.
+//
+// * dataTranscript extra data that might be needed to derive the challenges
+func Verify(digest *Digest, proof *OpeningProof, point *big.Int, hf hash.Hash, srs *SRS, dataTranscript ...[]byte) error {
+	v := newVerifier(srs, 1)
+	if err := v.addProof(digest, proof, point, big.NewInt(1), hf, dataTranscript...); err != nil {
+		return err
+	}
+	return v.check()
+}
+
+// BatchVerify verifies the opening proofs proofs[i] of the polynomials
+// committed in digests[i] at points[i], with a single multi-scalar
+// multiplication, by checking a random linear combination of the verification
+// equations.
+//
+// * dataTranscript extra data that might be needed to derive the challenges, shared by all the proofs
+func BatchVerify(digests []Digest, proofs []OpeningProof, points []big.Int, hf hash.Hash, srs *SRS, dataTranscript ...[]byte) error {
+	if len(digests) != len(proofs) || len(digests) != len(points) {
+		return ErrInvalidNbProofs
+	}
+	if len(digests) == 0 {
+		return nil
+	}
+
+	v := newVerifier(srs, len(proofs))
+	r := big.NewInt(1)
+	for i := range proofs {
+		if err := v.addProof(&digests[i], &proofs[i], &points[i], r, hf, dataTranscript...); err != nil {
+			return err
+		}
+		var err error
+		if r, err = rand.Int(rand.Reader, &v.order); err != nil {
+			return err
+		}
+	}
+	return v.check()
+}
+
+// verifier accumulates the verification equations
+//
+//	r(C + vQ' + ∑ᵢ(xᵢLᵢ + xᵢ⁻¹Rᵢ) - a⟨s, G⟩ - a⟨s, b⟩Q') = 0
+//
+// where sⱼ = ∏ᵢxᵢ^{-bitᵢ(j)}, so that they are checked with a single
+// multi-scalar multiplication.
+type verifier struct {
+	srs   *SRS
+	order big.Int
+
+	// scalars by which srs.G and srs.Q are multiplied
+	gScalars []big.Int
+	qScalar  big.Int
+
+	// the other terms
+	bases   []bandersnatch.PointAffine
+	scalars []big.Int
+}
+
+func newVerifier(srs *SRS, nbProofs int) *verifier {
+	nbRounds := bits.TrailingZeros(uint(len(srs.G)))
+	curve := bandersnatch.GetEdwardsCurve()
+	v := &verifier{
+		srs:      srs,
+		gScalars: make([]big.Int, len(srs.G)),
+		bases:    make([]bandersnatch.PointAffine, 0, nbProofs*(2*nbRounds+1)),
+		scalars:  make([]big.Int, 0, nbProofs*(2*nbRounds+1)),
+	}
+	v.order.Set(&curve.Order)
+	return v
+}
+
+// addProof adds the verification equation of proof, multiplied by r.
+func (v *verifier) addProof(digest *Digest, proof *OpeningProof, point, r *big.Int, hf hash.Hash, dataTranscript ...[]byte) error {
+	n := len(v.srs.G)
+	nbRounds := bits.TrailingZeros(uint(n))
+	if len(proof.L) != nbRounds || len(proof.R) != nbRounds {
+		return ErrInvalidNbRounds
+	}
+	order := &v.order
+	if !isReduced(&proof.A, order) || !isReduced(&proof.ClaimedValue, order) {
+		return ErrVerifyOpeningProof
+	}
+	// points with a small order component would give other valid proofs of
+	// the same opening
+	if !isInSubGroup(digest, order) {
+		return ErrVerifyOpeningProof
+	}
+	for i := range proof.L {
+		if !isInSubGroup(&proof.L[i], order) || !isInSubGroup(&proof.R[i], order) {
+			return ErrVerifyOpeningProof
+		}
+	}
+
+	// derive the challenges
+	var z big.Int
+	z.Mod(point, order)
+	fs := fiatshamir.NewTranscript(hf, challengeNames(nbRounds)...)
+	w, err := deriveChallengeW(fs, digest, &z, &proof.ClaimedValue, order, dataTranscript...)
+	if err != nil {
+		return err
+	}
+	x := make([]big.Int, nbRounds)
+	xInv := make([]big.Int, nbRounds)
+	for i := range x {
+		if x[i], err = deriveChallengeX(fs, i, &proof.L[i], &proof.R[i], order); err != nil {
+			return err
+		}
+		xInv[i].ModInverse(&x[i], order)
+	}
+
+	// r(C + ∑ᵢ(xᵢLᵢ + xᵢ⁻¹Rᵢ))
+	var t big.Int
+	v.bases = append(v.bases, *digest)
+	v.scalars = append(v.scalars, *new(big.Int).Set(r))
+	for i := range x {
+		v.bases = append(v.bases, proof.L[i], proof.R[i])
+		v.scalars = append(v.scalars,
+			*new(big.Int).Mod(t.Mul(r, &x[i]), order),
+			*new(big.Int).Mod(t.Mul(r, &xInv[i]), order))
+	}
+
+	// s, in the order of the indices of G: the i-th round splits the indices
+	// according to their (nbRounds-1-i)-th bit
+	s := make([]big.Int, n)
+	s[0].SetUint64(1)
+	for i := range xInv {
+		for j := (1 << i) - 1; j >= 0; j-- {
+			s[2*j+1].Mul(&s[j], &xInv[i]).Mod(&s[2*j+1], order)
+			s[2*j].Set(&s[j])
+		}
+	}
+
+	// ⟨s, b⟩ = ∏ᵢ(1 + xᵢ⁻¹point^{2^{nbRounds-1-i}})
+	var b, pointPow big.Int
+	b.SetUint64(1)
+	pointPow.Set(&z)
+	for i := nbRounds - 1; i >= 0; i-- {
+		t.Mul(&xInv[i], &pointPow).Add(&t, big.NewInt(1))
+		b.Mul(&b, &t).Mod(&b, order)
+		pointPow.Mul(&pointPow, &pointPow).Mod(&pointPow, order)
+	}
+
+	// -ra s
+	var ra big.Int
+	ra.Mul(r, &proof.A).Mod(&ra, order)
+	for j := range s {
+		t.Mul(&s[j], &ra)
+		v.gScalars[j].Sub(&v.gScalars[j], &t).Mod(&v.gScalars[j], order)
+	}
+
+	// rw(v - ab)
+	t.Mul(&proof.A, &b)
+	t.Sub(&proof.ClaimedValue, &t).Mul(&t, &w).Mul(&t, r)
+	v.qScalar.Add(&v.qScalar, &t).Mod(&v.qScalar, order)
+
+	return nil
+}
+
+// check checks that the sum of the verification equations is zero.
+func (v *verifier) check() error {
+	bases := make([]bandersnatch.PointAffine, 0, len(v.srs.G)+1+len(v.bases))
+	bases = append(bases, v.srs.G...)
+	bases = append(bases, v.srs.Q)
+	bases = append(bases, v.bases...)
+	scalars := make([]big.Int, 0, len(bases))
+	scalars = append(scalars, v.gScalars...)
+	scalars = append(scalars, v.qScalar)
+	scalars = append(scalars, v.scalars...)
+
+	var res bandersnatch.PointExtended
+	if _, err := res.MultiExp(bases, scalars); err != nil {
+		return err
+	}
+	if !res.IsZero() {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// hashToCurve hashes msg to a point of the prime order subgroup, by try and
+// increment: y is hashed to the field, x is recovered from the curve equation
+// ax² + y² = 1 + dx²y² if possible, and the cofactor is cleared.
+func hashToCurve(msg, dst []byte) (bandersnatch.PointAffine, error) {
+	curve := bandersnatch.GetEdwardsCurve()
+	var cofactor big.Int
+	curve.Cofactor.BigInt(&cofactor)
+
+	var res bandersnatch.PointAffine
+	buf := make([]byte, len(msg)+4)
+	copy(buf, msg)
+	var one, num, den fr.Element
+	one.SetOne()
+	for counter := uint32(0); ; counter++ {
+		binary.BigEndian.PutUint32(buf[len(msg):], counter)
+		y, err := fr.Hash(buf, dst, 1)
+		if err != nil {
+			return res, err
+		}
+
+		// x² = (1 - y²) / (a - dy²)
+		num.Square(&y[0])
+		den.Mul(&num, &curve.D)
+		den.Sub(&curve.A, &den)
+		num.Sub(&one, &num)
+		if den.IsZero() {
+			continue
+		}
+		num.Div(&num, &den)
+		if res.X.Sqrt(&num) == nil {
+			continue
+		}
+		res.Y.Set(&y[0])
+
+		// the endomorphism of the scalar multiplication only acts as a scalar
+		// on the prime order subgroup, so the cofactor is cleared with double
+		// and add
+		var p bandersnatch.PointExtended
+		p.FromAffine(&res)
+		for i := cofactor.BitLen() - 2; i >= 0; i-- {
+			p.Double(&p)
+			if cofactor.Bit(i) == 1 {
+				p.MixedAdd(&p, &res)
+			}
+		}
+		res.FromExtended(&p)
+		if !res.IsZero() {
+			return res, nil
+		}
+	}
+}
+
+// isInSubGroup returns true if p is on the curve and [order]p = 0. The
+// constant time scalar multiplication is used because it doesn't decompose
+// the scalar with the endomorphism, which assumes that p is in the subgroup.
+func isInSubGroup(p *bandersnatch.PointAffine, order *big.Int) bool {
+	if !p.IsOnCurve() {
+		return false
+	}
+	var q bandersnatch.PointAffine
+	q.ScalarMultiplicationConstantTime(p, order)
+	return q.IsZero()
+}
+
+// isReduced returns true if 0 ≤ x < order
+func isReduced(x, order *big.Int) bool {
+	return x.Sign() >= 0 && x.Cmp(order) < 0
+}
+
+// crossTerm returns ⟨a, g⟩ + ⟨a, b⟩q
+func crossTerm(g []bandersnatch.PointAffine, a, b []big.Int, q *bandersnatch.PointAffine, order *big.Int) (bandersnatch.PointAffine, error) {
+	bases := make([]bandersnatch.PointAffine, len(g)+1)
+	copy(bases, g)
+	bases[len(g)] = *q
+	scalars := make([]big.Int, len(a)+1)
+	for i := range a {
+		scalars[i].Set(&a[i])
+	}
+	innerProduct(&scalars[len(a)], a, b, order)
+
+	var resExtended bandersnatch.PointExtended
+	var res bandersnatch.PointAffine
+	if _, err := resExtended.MultiExp(bases, scalars); err != nil {
+		return res, err
+	}
+	res.FromExtended(&resExtended)
+	return res, nil
+}
+
+// foldBases returns gL + x⁻¹gR
+func foldBases(gL, gR []bandersnatch.PointAffine, xInv *big.Int) []bandersnatch.PointAffine {
+	res := make([]bandersnatch.PointAffine, len(gL))
+	parallel.Execute(len(gL), func(start, end int) {
+		var p bandersnatch.PointExtended
+		for i := start; i < end; i++ {
+			p.FromAffine(&gR[i])
+			p.ScalarMultiplication(&p, xInv).
+				MixedAdd(&p, &gL[i])
+			res[i].FromExtended(&p)
+		}
+	})
+	return res
+}
+
+// innerProduct sets res to ⟨a, b⟩ mod order
+func innerProduct(res *big.Int, a, b []big.Int, order *big.Int) {
+	var t big.Int
+	res.SetUint64(0)
+	for i := range a {
+		t.Mul(&a[i], &b[i])
+		res.Add(res, &t)
+	}
+	res.Mod(res, order)
+}
+
+// challengeNames returns the names of the challenges of the transcript, w
+// and the challenges xᵢ of the rounds.
+func challengeNames(nbRounds int) []string {
+	res := make([]string, nbRounds+1)
+	res[0] = "w"
+	for i := 0; i < nbRounds; i++ {
+		res[i+1] = "x" + strconv.Itoa(i)
+	}
+	return res
+}
+
+// deriveChallengeW derives the challenge w, binded to the digest, the point,
+// the claimed value and dataTranscript.
+func deriveChallengeW(fs *fiatshamir.Transcript, digest *Digest, point, claimedValue, order *big.Int, dataTranscript ...[]byte) (big.Int, error) {
+	var bPoint, bClaimedValue [fr.Bytes]byte
+	point.FillBytes(bPoint[:])
+	claimedValue.FillBytes(bClaimedValue[:])
+	toBind := [][]byte{digest.Marshal(), bPoint[:], bClaimedValue[:]}
+	toBind = append(toBind, dataTranscript...)
+	for i := range toBind {
+		if err := fs.Bind("w", toBind[i]); err != nil {
+			return big.Int{}, err
+		}
+	}
+	return computeChallenge(fs, "w", order)
+}
+
+// deriveChallengeX derives the challenge of the given round, binded to the
+// cross terms.
+func deriveChallengeX(fs *fiatshamir.Transcript, round int, l, r *bandersnatch.PointAffine, order *big.Int) (big.Int, error) {
+	name := "x" + strconv.Itoa(round)
+	if err := fs.Bind(name, l.Marshal()); err != nil {
+		return big.Int{}, err
+	}
+	if err := fs.Bind(name, r.Marshal()); err != nil {
+		return big.Int{}, err
+	}
+	return computeChallenge(fs, name, order)
+}
+
+func computeChallenge(fs *fiatshamir.Transcript, name string, order *big.Int) (big.Int, error) {
+	b, err := fs.ComputeChallenge(name)
+	if err != nil {
+		return big.Int{}, err
+	}
+	var res big.Int
+	res.SetBytes(b).Mod(&res, order)
+	if res.Sign() == 0 {
+		return res, ErrZeroChallenge
+	}
+	return res, nil
+}
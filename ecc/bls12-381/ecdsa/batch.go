@@ -0,0 +1,96 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"errors"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+	"hash"
+)
+
+var (
+	errBatchSize  = errors.New("the numbers of public keys, signatures and messages differ")
+	errNotOnCurve = errors.New("public key is not on the curve")
+)
+
+// BatchVerify verifies the signatures sigs[i] of messages msgs[i] for the
+// public keys pubs[i] at once. It returns true and -1 if all the signatures
+// are valid, and false and the index of the first invalid signature otherwise.
+//
+// sigs[i] is a signature r||s (see Signature).
+//
+// A plain ECDSA signature only contains the x-coordinate r of the point R, so
+// its verification equation can't be combined with the others: such
+// signatures are verified one by one, split across the CPUs.
+func BatchVerify(pubs []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) (bool, int, error) {
+	if len(pubs) != len(sigs) || len(pubs) != len(msgs) {
+		return false, -1, errBatchSize
+	}
+	n := len(pubs)
+	if n == 0 {
+		return true, -1, nil
+	}
+
+	// decode the signatures and hash the messages first: hFunc can't be
+	// shared between go routines.
+	var sig Signature
+	digests := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		if !pubs[i].A.IsInSubGroup() {
+			return false, i, errNotOnCurve
+		}
+		if _, err := sig.SetBytes(sigs[i]); err != nil {
+			return false, i, err
+		}
+		d, err := messageDigest(msgs[i], hFunc)
+		if err != nil {
+			return false, i, err
+		}
+		digests[i] = d
+	}
+
+	valid := make([]bool, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			if valid[i] {
+				continue
+			}
+			// the signature and the digest are already checked
+			valid[i], _ = pubs[i].Verify(sigs[i], digests[i], nil)
+		}
+	})
+
+	for i := range valid {
+		if !valid[i] {
+			return false, i, nil
+		}
+	}
+	return true, -1, nil
+}
+
+// messageDigest returns the message hashed with hFunc if it's not nil, as in
+// Sign and Verify, and the message itself otherwise.
+func messageDigest(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
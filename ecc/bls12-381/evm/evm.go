@@ -0,0 +1,343 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package evm implements the encodings of the BLS12-381 points used by the
+// Ethereum precompiled contracts of EIP-2537, and the precompiles themselves:
+//
+//   - G1Add (0x0b), G1MSM (0x0c), G2Add (0x0d), G2MSM (0x0e)
+//   - PairingCheck (0x0f)
+//   - MapFpToG1 (0x10), MapFp2ToG2 (0x11)
+//
+// A field element is encoded on 64 bytes: 16 zero bytes followed by its
+// 48-byte big-endian encoding, smaller than the modulus. An element
+// c0 + c1⋅u of Fp2 is encoded as c0 || c1, a point as x || y, and the point at
+// infinity with zeroes. Scalars are 32-byte big-endian integers.
+//
+// The gas costs are not computed by this package.
+package evm
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// SizeOfFp is the size of an encoded field element.
+	SizeOfFp = 64
+
+	// SizeOfFp2 is the size of an encoded element of Fp2.
+	SizeOfFp2 = 2 * SizeOfFp
+
+	// SizeOfG1 is the size of an encoded G1 point.
+	SizeOfG1 = 2 * SizeOfFp
+
+	// SizeOfG2 is the size of an encoded G2 point.
+	SizeOfG2 = 2 * SizeOfFp2
+
+	// SizeOfScalar is the size of an encoded scalar.
+	SizeOfScalar = 32
+)
+
+const (
+	fpPaddingSize      = SizeOfFp - fp.Bytes
+	g1MSMElementSize   = SizeOfG1 + SizeOfScalar
+	g2MSMElementSize   = SizeOfG2 + SizeOfScalar
+	pairingElementSize = SizeOfG1 + SizeOfG2
+)
+
+var (
+	ErrInvalidInputLength  = errors.New("invalid input length")
+	ErrInvalidFieldElement = errors.New("invalid field element encoding")
+	ErrPointNotOnCurve     = errors.New("point is not on the curve")
+	ErrPointNotInSubgroup  = errors.New("point is not in the prime order subgroup")
+)
+
+// EncodeFp returns the encoding of e.
+func EncodeFp(e *fp.Element) [SizeOfFp]byte {
+	var res [SizeOfFp]byte
+	fp.BigEndian.PutElement((*[fp.Bytes]byte)(res[fpPaddingSize:]), *e)
+	return res
+}
+
+// DecodeFp decodes a field element.
+func DecodeFp(b []byte) (fp.Element, error) {
+	if len(b) != SizeOfFp {
+		return fp.Element{}, ErrInvalidInputLength
+	}
+	for i := 0; i < fpPaddingSize; i++ {
+		if b[i] != 0 {
+			return fp.Element{}, ErrInvalidFieldElement
+		}
+	}
+	e, err := fp.BigEndian.Element((*[fp.Bytes]byte)(b[fpPaddingSize:]))
+	if err != nil {
+		return e, ErrInvalidFieldElement
+	}
+	return e, nil
+}
+
+// EncodeFp2 returns the encoding of e.
+func EncodeFp2(e *bls12381.E2) [SizeOfFp2]byte {
+	var res [SizeOfFp2]byte
+	a0, a1 := EncodeFp(&e.A0), EncodeFp(&e.A1)
+	copy(res[:SizeOfFp], a0[:])
+	copy(res[SizeOfFp:], a1[:])
+	return res
+}
+
+// DecodeFp2 decodes an element of Fp2.
+func DecodeFp2(b []byte) (bls12381.E2, error) {
+	var e bls12381.E2
+	if len(b) != SizeOfFp2 {
+		return e, ErrInvalidInputLength
+	}
+	var err error
+	if e.A0, err = DecodeFp(b[:SizeOfFp]); err != nil {
+		return e, err
+	}
+	if e.A1, err = DecodeFp(b[SizeOfFp:]); err != nil {
+		return e, err
+	}
+	return e, nil
+}
+
+// EncodeG1 returns the encoding of p.
+func EncodeG1(p *bls12381.G1Affine) [SizeOfG1]byte {
+	var res [SizeOfG1]byte
+	x, y := EncodeFp(&p.X), EncodeFp(&p.Y)
+	copy(res[:SizeOfFp], x[:])
+	copy(res[SizeOfFp:], y[:])
+	return res
+}
+
+// DecodeG1 decodes a G1 point, checked to be on the curve. The callers check,
+// when required, that the point is in the prime order subgroup.
+func DecodeG1(b []byte) (bls12381.G1Affine, error) {
+	var p bls12381.G1Affine
+	if len(b) != SizeOfG1 {
+		return p, ErrInvalidInputLength
+	}
+	var err error
+	if p.X, err = DecodeFp(b[:SizeOfFp]); err != nil {
+		return p, err
+	}
+	if p.Y, err = DecodeFp(b[SizeOfFp:]); err != nil {
+		return p, err
+	}
+	if !p.IsOnCurve() {
+		return p, ErrPointNotOnCurve
+	}
+	return p, nil
+}
+
+// EncodeG2 returns the encoding of p.
+func EncodeG2(p *bls12381.G2Affine) [SizeOfG2]byte {
+	var res [SizeOfG2]byte
+	x, y := EncodeFp2(&p.X), EncodeFp2(&p.Y)
+	copy(res[:SizeOfFp2], x[:])
+	copy(res[SizeOfFp2:], y[:])
+	return res
+}
+
+// DecodeG2 decodes a G2 point, checked to be on the curve. The callers check,
+// when required, that the point is in the prime order subgroup.
+func DecodeG2(b []byte) (bls12381.G2Affine, error) {
+	var p bls12381.G2Affine
+	if len(b) != SizeOfG2 {
+		return p, ErrInvalidInputLength
+	}
+	var err error
+	if p.X, err = DecodeFp2(b[:SizeOfFp2]); err != nil {
+		return p, err
+	}
+	if p.Y, err = DecodeFp2(b[SizeOfFp2:]); err != nil {
+		return p, err
+	}
+	if !p.IsOnCurve() {
+		return p, ErrPointNotOnCurve
+	}
+	return p, nil
+}
+
+// G1Add implements the BLS12_G1ADD precompile: the input is the encoding of 2
+// G1 points, and the output the encoding of their sum. The points are not
+// checked to be in the prime order subgroup.
+func G1Add(input []byte) ([]byte, error) {
+	if len(input) != 2*SizeOfG1 {
+		return nil, ErrInvalidInputLength
+	}
+	p, err := DecodeG1(input[:SizeOfG1])
+	if err != nil {
+		return nil, err
+	}
+	q, err := DecodeG1(input[SizeOfG1:])
+	if err != nil {
+		return nil, err
+	}
+	p.Add(&p, &q)
+	res := EncodeG1(&p)
+	return res[:], nil
+}
+
+// G2Add implements the BLS12_G2ADD precompile: the input is the encoding of 2
+// G2 points, and the output the encoding of their sum. The points are not
+// checked to be in the prime order subgroup.
+func G2Add(input []byte) ([]byte, error) {
+	if len(input) != 2*SizeOfG2 {
+		return nil, ErrInvalidInputLength
+	}
+	p, err := DecodeG2(input[:SizeOfG2])
+	if err != nil {
+		return nil, err
+	}
+	q, err := DecodeG2(input[SizeOfG2:])
+	if err != nil {
+		return nil, err
+	}
+	p.Add(&p, &q)
+	res := EncodeG2(&p)
+	return res[:], nil
+}
+
+// G1MSM implements the BLS12_G1MSM precompile: the input is a non-empty
+// sequence of G1 points and scalars (P₁, s₁, …, Pₖ, sₖ), and the output is the
+// encoding of ∑ [sᵢ]Pᵢ. The points must be in the prime order subgroup.
+func G1MSM(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%g1MSMElementSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	k := len(input) / g1MSMElementSize
+	points := make([]bls12381.G1Affine, k)
+	scalars := make([]fr.Element, k)
+	for i := 0; i < k; i++ {
+		b := input[i*g1MSMElementSize : (i+1)*g1MSMElementSize]
+		var err error
+		if points[i], err = DecodeG1(b[:SizeOfG1]); err != nil {
+			return nil, err
+		}
+		if !points[i].IsInSubGroup() {
+			return nil, ErrPointNotInSubgroup
+		}
+		// the points have order r, the scalars are reduced modulo r
+		scalars[i].SetBytes(b[SizeOfG1:])
+	}
+
+	var p bls12381.G1Affine
+	if _, err := p.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+	res := EncodeG1(&p)
+	return res[:], nil
+}
+
+// G2MSM implements the BLS12_G2MSM precompile: the input is a non-empty
+// sequence of G2 points and scalars (Q₁, s₁, …, Qₖ, sₖ), and the output is the
+// encoding of ∑ [sᵢ]Qᵢ. The points must be in the prime order subgroup.
+func G2MSM(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%g2MSMElementSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	k := len(input) / g2MSMElementSize
+	points := make([]bls12381.G2Affine, k)
+	scalars := make([]fr.Element, k)
+	for i := 0; i < k; i++ {
+		b := input[i*g2MSMElementSize : (i+1)*g2MSMElementSize]
+		var err error
+		if points[i], err = DecodeG2(b[:SizeOfG2]); err != nil {
+			return nil, err
+		}
+		if !points[i].IsInSubGroup() {
+			return nil, ErrPointNotInSubgroup
+		}
+		scalars[i].SetBytes(b[SizeOfG2:])
+	}
+
+	var p bls12381.G2Affine
+	if _, err := p.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return nil, err
+	}
+	res := EncodeG2(&p)
+	return res[:], nil
+}
+
+// PairingCheck implements the BLS12_PAIRING_CHECK precompile: the input is a
+// non-empty sequence of G1 and G2 points (P₁, Q₁, …, Pₖ, Qₖ), in the prime
+// order subgroups, and the output is the 32-byte encoding of 1 if
+// ∏ e(Pᵢ, Qᵢ) = 1, of 0 otherwise.
+func PairingCheck(input []byte) ([]byte, error) {
+	if len(input) == 0 || len(input)%pairingElementSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	k := len(input) / pairingElementSize
+	P := make([]bls12381.G1Affine, k)
+	Q := make([]bls12381.G2Affine, k)
+	for i := 0; i < k; i++ {
+		b := input[i*pairingElementSize : (i+1)*pairingElementSize]
+		var err error
+		if P[i], err = DecodeG1(b[:SizeOfG1]); err != nil {
+			return nil, err
+		}
+		if !P[i].IsInSubGroup() {
+			return nil, ErrPointNotInSubgroup
+		}
+		if Q[i], err = DecodeG2(b[SizeOfG1:]); err != nil {
+			return nil, err
+		}
+		if !Q[i].IsInSubGroup() {
+			return nil, ErrPointNotInSubgroup
+		}
+	}
+
+	ok, err := bls12381.PairingCheck(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, 32)
+	if ok {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+// MapFpToG1 implements the BLS12_MAP_FP_TO_G1 precompile: the input is the
+// encoding of a field element u, and the output the encoding of the G1 point
+// it maps to, with the simplified SWU map and the cofactor clearing of the
+// hash-to-curve suite BLS12381G1_XMD:SHA-256_SSWU_RO_.
+func MapFpToG1(input []byte) ([]byte, error) {
+	u, err := DecodeFp(input)
+	if err != nil {
+		return nil, err
+	}
+	p := bls12381.MapToG1(u)
+	res := EncodeG1(&p)
+	return res[:], nil
+}
+
+// MapFp2ToG2 implements the BLS12_MAP_FP2_TO_G2 precompile: the input is the
+// encoding of an element u of Fp2, and the output the encoding of the G2 point
+// it maps to, with the simplified SWU map and the cofactor clearing of the
+// hash-to-curve suite BLS12381G2_XMD:SHA-256_SSWU_RO_.
+func MapFp2ToG2(input []byte) ([]byte, error) {
+	u, err := DecodeFp2(input)
+	if err != nil {
+		return nil, err
+	}
+	p := bls12381.MapToG2(u)
+	res := EncodeG2(&p)
+	return res[:], nil
+}
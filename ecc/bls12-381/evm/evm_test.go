@@ -0,0 +1,316 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evm
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomG1() bls12381.G1Affine {
+	var s fr.Element
+	s.SetRandom()
+	var p bls12381.G1Affine
+	var b big.Int
+	p.ScalarMultiplicationBase(s.BigInt(&b))
+	return p
+}
+
+func randomG2() bls12381.G2Affine {
+	var s fr.Element
+	s.SetRandom()
+	var p bls12381.G2Affine
+	var b big.Int
+	p.ScalarMultiplicationBase(s.BigInt(&b))
+	return p
+}
+
+// g1NotInSubgroup returns a point of E(Fp) which is not in G1.
+func g1NotInSubgroup() bls12381.G1Affine {
+	var p bls12381.G1Affine
+	var four fp.Element
+	four.SetUint64(4)
+	for {
+		p.X.SetRandom()
+		p.Y.Square(&p.X).Mul(&p.Y, &p.X).Add(&p.Y, &four)
+		if p.Y.Legendre() == 1 {
+			p.Y.Sqrt(&p.Y)
+			if !p.IsInSubGroup() {
+				return p
+			}
+		}
+	}
+}
+
+// g2NotInSubgroup returns a point of E'(Fp2) which is not in G2.
+func g2NotInSubgroup() bls12381.G2Affine {
+	var p bls12381.G2Affine
+	var b bls12381.E2
+	b.A0.SetUint64(4)
+	b.A1.SetUint64(4)
+	for {
+		p.X.SetRandom()
+		p.Y.Square(&p.X).Mul(&p.Y, &p.X).Add(&p.Y, &b)
+		if p.Y.Legendre() == 1 {
+			p.Y.Sqrt(&p.Y)
+			if !p.IsInSubGroup() {
+				return p
+			}
+		}
+	}
+}
+
+func concat(b ...[]byte) []byte {
+	var res []byte
+	for i := range b {
+		res = append(res, b[i]...)
+	}
+	return res
+}
+
+func TestEncoding(t *testing.T) {
+	p := randomG1()
+	b := EncodeG1(&p)
+	pp, err := DecodeG1(b[:])
+	require.NoError(t, err)
+	assert.True(t, pp.Equal(&p))
+	assert.Equal(t, make([]byte, fpPaddingSize), b[:fpPaddingSize])
+
+	q := randomG2()
+	c := EncodeG2(&q)
+	qq, err := DecodeG2(c[:])
+	require.NoError(t, err)
+	assert.True(t, qq.Equal(&q))
+
+	// c0 comes first
+	x := EncodeFp(&q.X.A0)
+	assert.Equal(t, x[:], c[:SizeOfFp])
+
+	// the point at infinity is encoded with zeroes
+	var inf1 bls12381.G1Affine
+	var inf2 bls12381.G2Affine
+	assert.Equal(t, [SizeOfG1]byte{}, EncodeG1(&inf1))
+	assert.Equal(t, [SizeOfG2]byte{}, EncodeG2(&inf2))
+	pp, err = DecodeG1(make([]byte, SizeOfG1))
+	require.NoError(t, err)
+	assert.True(t, pp.IsInfinity())
+	qq, err = DecodeG2(make([]byte, SizeOfG2))
+	require.NoError(t, err)
+	assert.True(t, qq.IsInfinity())
+
+	// invalid encodings
+	_, err = DecodeG1(b[1:])
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+	bad := b
+	bad[0] = 1
+	_, err = DecodeG1(bad[:])
+	assert.ErrorIs(t, err, ErrInvalidFieldElement)
+	bad = b
+	copy(bad[fpPaddingSize:SizeOfFp], fp.Modulus().Bytes())
+	_, err = DecodeG1(bad[:])
+	assert.ErrorIs(t, err, ErrInvalidFieldElement)
+	bad = b
+	bad[SizeOfG1-1] ^= 1
+	_, err = DecodeG1(bad[:])
+	assert.ErrorIs(t, err, ErrPointNotOnCurve)
+	badG2 := c
+	badG2[SizeOfG2-1] ^= 1
+	_, err = DecodeG2(badG2[:])
+	assert.ErrorIs(t, err, ErrPointNotOnCurve)
+}
+
+func TestAdd(t *testing.T) {
+	p1, q1 := randomG1(), randomG1()
+	var e1 bls12381.G1Affine
+	e1.Add(&p1, &q1)
+	bp1, bq1, be1 := EncodeG1(&p1), EncodeG1(&q1), EncodeG1(&e1)
+	res, err := G1Add(concat(bp1[:], bq1[:]))
+	require.NoError(t, err)
+	assert.Equal(t, be1[:], res)
+
+	p2, q2 := randomG2(), randomG2()
+	var e2 bls12381.G2Affine
+	e2.Add(&p2, &q2)
+	bp2, bq2, be2 := EncodeG2(&p2), EncodeG2(&q2), EncodeG2(&e2)
+	res, err = G2Add(concat(bp2[:], bq2[:]))
+	require.NoError(t, err)
+	assert.Equal(t, be2[:], res)
+
+	// P + 0 and P + (-P)
+	res, err = G1Add(concat(bp1[:], make([]byte, SizeOfG1)))
+	require.NoError(t, err)
+	assert.Equal(t, bp1[:], res)
+	q1.Neg(&p1)
+	bq1 = EncodeG1(&q1)
+	res, err = G1Add(concat(bp1[:], bq1[:]))
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, SizeOfG1), res)
+
+	// the points are not checked to be in the subgroups
+	n1 := g1NotInSubgroup()
+	bn1 := EncodeG1(&n1)
+	_, err = G1Add(concat(bp1[:], bn1[:]))
+	assert.NoError(t, err)
+	n2 := g2NotInSubgroup()
+	bn2 := EncodeG2(&n2)
+	_, err = G2Add(concat(bp2[:], bn2[:]))
+	assert.NoError(t, err)
+
+	// the input length is not padded
+	_, err = G1Add(bp1[:])
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+	_, err = G2Add(concat(bp2[:], bq2[:], []byte{0}))
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+}
+
+func TestMSM(t *testing.T) {
+	const k = 5
+	var input1, input2 []byte
+	var e1 bls12381.G1Jac
+	var e2 bls12381.G2Jac
+	for i := 0; i < k; i++ {
+		p1, p2 := randomG1(), randomG2()
+		var s fr.Element
+		s.SetRandom()
+		var b big.Int
+		s.BigInt(&b)
+
+		var t1 bls12381.G1Jac
+		var t2 bls12381.G2Jac
+		t1.FromAffine(&p1)
+		t1.ScalarMultiplication(&t1, &b)
+		e1.AddAssign(&t1)
+		t2.FromAffine(&p2)
+		t2.ScalarMultiplication(&t2, &b)
+		e2.AddAssign(&t2)
+
+		// the scalars are not reduced
+		if i%2 == 1 {
+			b.Add(&b, fr.Modulus())
+		}
+		scalar := b.FillBytes(make([]byte, SizeOfScalar))
+		bp1, bp2 := EncodeG1(&p1), EncodeG2(&p2)
+		input1 = concat(input1, bp1[:], scalar)
+		input2 = concat(input2, bp2[:], scalar)
+	}
+
+	var a1 bls12381.G1Affine
+	var a2 bls12381.G2Affine
+	a1.FromJacobian(&e1)
+	a2.FromJacobian(&e2)
+	be1, be2 := EncodeG1(&a1), EncodeG2(&a2)
+	res, err := G1MSM(input1)
+	require.NoError(t, err)
+	assert.Equal(t, be1[:], res)
+	res, err = G2MSM(input2)
+	require.NoError(t, err)
+	assert.Equal(t, be2[:], res)
+
+	// invalid inputs
+	_, err = G1MSM(nil)
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+	_, err = G2MSM(input2[1:])
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+	n1 := g1NotInSubgroup()
+	bn1 := EncodeG1(&n1)
+	_, err = G1MSM(concat(bn1[:], make([]byte, SizeOfScalar)))
+	assert.ErrorIs(t, err, ErrPointNotInSubgroup)
+	n2 := g2NotInSubgroup()
+	bn2 := EncodeG2(&n2)
+	_, err = G2MSM(concat(bn2[:], make([]byte, SizeOfScalar)))
+	assert.ErrorIs(t, err, ErrPointNotInSubgroup)
+}
+
+func TestPairingCheck(t *testing.T) {
+	one := make([]byte, 32)
+	one[31] = 1
+	zero := make([]byte, 32)
+
+	// e(P, [s]Q)⋅e(-[s]P, Q) = 1
+	p, q := randomG1(), randomG2()
+	var s fr.Element
+	s.SetRandom()
+	var b big.Int
+	var sp bls12381.G1Affine
+	var sq bls12381.G2Affine
+	sp.ScalarMultiplication(&p, s.BigInt(&b)).Neg(&sp)
+	sq.ScalarMultiplication(&q, &b)
+
+	bp, bsp := EncodeG1(&p), EncodeG1(&sp)
+	bq, bsq := EncodeG2(&q), EncodeG2(&sq)
+	res, err := PairingCheck(concat(bp[:], bsq[:], bsp[:], bq[:]))
+	require.NoError(t, err)
+	assert.Equal(t, one, res)
+	res, err = PairingCheck(concat(bp[:], bsq[:], bp[:], bq[:]))
+	require.NoError(t, err)
+	assert.Equal(t, zero, res)
+
+	// points at infinity
+	res, err = PairingCheck(concat(make([]byte, SizeOfG1), bq[:]))
+	require.NoError(t, err)
+	assert.Equal(t, one, res)
+
+	// invalid inputs
+	_, err = PairingCheck(nil)
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+	n1 := g1NotInSubgroup()
+	bn1 := EncodeG1(&n1)
+	_, err = PairingCheck(concat(bn1[:], bq[:]))
+	assert.ErrorIs(t, err, ErrPointNotInSubgroup)
+	n2 := g2NotInSubgroup()
+	bn2 := EncodeG2(&n2)
+	_, err = PairingCheck(concat(bp[:], bn2[:]))
+	assert.ErrorIs(t, err, ErrPointNotInSubgroup)
+}
+
+func TestMapToCurve(t *testing.T) {
+	var u fp.Element
+	u.SetRandom()
+	bu := EncodeFp(&u)
+	res, err := MapFpToG1(bu[:])
+	require.NoError(t, err)
+	p, err := DecodeG1(res)
+	require.NoError(t, err)
+	assert.True(t, p.IsInSubGroup())
+	expected := bls12381.MapToG1(u)
+	assert.True(t, p.Equal(&expected))
+
+	var u2 bls12381.E2
+	u2.SetRandom()
+	bu2 := EncodeFp2(&u2)
+	res, err = MapFp2ToG2(bu2[:])
+	require.NoError(t, err)
+	q, err := DecodeG2(res)
+	require.NoError(t, err)
+	assert.True(t, q.IsInSubGroup())
+	expected2 := bls12381.MapToG2(u2)
+	assert.True(t, q.Equal(&expected2))
+
+	// invalid inputs
+	_, err = MapFpToG1(bu[1:])
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+	bu[0] = 1
+	_, err = MapFpToG1(bu[:])
+	assert.ErrorIs(t, err, ErrInvalidFieldElement)
+	copy(bu2[SizeOfFp+fpPaddingSize:], fp.Modulus().Bytes())
+	_, err = MapFp2ToG2(bu2[:])
+	assert.ErrorIs(t, err, ErrInvalidFieldElement)
+}
@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
@@ -212,6 +213,34 @@ func TestFRI(t *testing.T) {
 
 }
 
+func TestFRIDuplex(t *testing.T) {
+
+	size := 256
+	p := randomPolynomial(uint64(size), 7)
+
+	newDuplex := func(absorbed string) *fiatshamir.Duplex {
+		d := fiatshamir.NewDuplex(sha256.New(), []byte("fri"))
+		d.Absorb([]byte(absorbed))
+		return d
+	}
+
+	proof, err := RADIX_2_FRI.NewWithDuplex(uint64(size), sha256.New(), newDuplex("a")).BuildProofOfProximity(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RADIX_2_FRI.NewWithDuplex(uint64(size), sha256.New(), newDuplex("a")).VerifyProofOfProximity(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the challenges are bound to the state of the duplex
+	err = RADIX_2_FRI.NewWithDuplex(uint64(size), sha256.New(), newDuplex("b")).VerifyProofOfProximity(proof)
+	if err == nil {
+		t.Fatal("verifying a proof with another duplex should fail")
+	}
+}
+
 // Benchmarks
 
 func BenchmarkProximityVerification(b *testing.B) {
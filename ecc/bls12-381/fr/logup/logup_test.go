@@ -0,0 +1,203 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package logup
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+func TestLookupVector(t *testing.T) {
+
+	lookupVector := make(fr.Vector, 8)
+	fvector := make(fr.Vector, 7)
+	for i := 0; i < 8; i++ {
+		lookupVector[i].SetUint64(uint64(2 * i))
+	}
+	for i := 0; i < 7; i++ {
+		fvector[i].Set(&lookupVector[(4*i+1)%8])
+	}
+
+	kzgSrs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// correct proof vector
+	{
+		proof, err := ProveLookupVector(kzgSrs.Pk, fvector, lookupVector)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = Verify(kzgSrs.Vk, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// wrong proofs vector
+	{
+		fvector[0].SetRandom()
+
+		_, err := ProveLookupVector(kzgSrs.Pk, fvector, lookupVector)
+		if err != ErrNotInTable {
+			t.Fatal("the prover should detect that f is not in t")
+		}
+	}
+
+}
+
+func TestLookupVectorMultiplicities(t *testing.T) {
+
+	kzgSrs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// f is larger than t, and looks up the same entries many times
+	lookupVector := make(fr.Vector, 5)
+	fvector := make(fr.Vector, 19)
+	for i := 0; i < 5; i++ {
+		lookupVector[i].SetUint64(uint64(3*i + 1))
+	}
+	for i := 0; i < 19; i++ {
+		fvector[i].Set(&lookupVector[(i*i)%3])
+	}
+
+	proof, err := ProveLookupVector(kzgSrs.Pk, fvector, lookupVector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Verify(kzgSrs.Vk, proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tampered multiplicities
+	{
+		_proof := proof
+		_proof.m.Add(&proof.m, &proof.z)
+		if Verify(kzgSrs.Vk, _proof) == nil {
+			t.Fatal("verification should fail with tampered multiplicities")
+		}
+	}
+
+	// tampered size
+	{
+		_proof := proof
+		_proof.size *= 2
+		if Verify(kzgSrs.Vk, _proof) == nil {
+			t.Fatal("verification should fail with a wrong size")
+		}
+		_proof.size = 3
+		if Verify(kzgSrs.Vk, _proof) != ErrDomainSize {
+			t.Fatal("verification should fail with a size which is not a power of two")
+		}
+	}
+
+	// tampered claimed value
+	{
+		_proof := proof
+		_proof.batchedProof.ClaimedValues = make([]fr.Element, len(proof.batchedProof.ClaimedValues))
+		copy(_proof.batchedProof.ClaimedValues, proof.batchedProof.ClaimedValues)
+		_proof.batchedProof.ClaimedValues[2].SetRandom()
+		if Verify(kzgSrs.Vk, _proof) == nil {
+			t.Fatal("verification should fail with a wrong claimed value")
+		}
+	}
+}
+
+func TestLookupTable(t *testing.T) {
+
+	kzgSrs, err := kzg.NewSRS(64, big.NewInt(13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookupTable := make([]fr.Vector, 3)
+	fTable := make([]fr.Vector, 3)
+	for i := 0; i < 3; i++ {
+		lookupTable[i] = make(fr.Vector, 8)
+		fTable[i] = make(fr.Vector, 11)
+		for j := 0; j < 8; j++ {
+			lookupTable[i][j].SetUint64(uint64(2*i + j))
+		}
+		for j := 0; j < 11; j++ {
+			fTable[i][j].Set(&lookupTable[i][(4*j+1)%8])
+		}
+	}
+
+	// correct proof
+	{
+		proof, err := ProveLookupTables(kzgSrs.Pk, fTable, lookupTable)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = Verify(kzgSrs.Vk, proof)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// wrong proof: each column of f is in the corresponding column of t, but a
+	// row of f is not a row of t
+	{
+		fTable[0][0].Set(&lookupTable[0][2])
+
+		_, err := ProveLookupTables(kzgSrs.Pk, fTable, lookupTable)
+		if err != ErrNotInTable {
+			t.Fatal("the prover should detect that f is not in t")
+		}
+	}
+
+	// incompatible sizes
+	{
+		fTable[1] = fTable[1][1:]
+
+		_, err := ProveLookupTables(kzgSrs.Pk, fTable, lookupTable)
+		if err != ErrIncompatibleSize {
+			t.Fatal("the prover should detect the incompatible sizes")
+		}
+	}
+
+}
+
+func BenchmarkLookupVector(b *testing.B) {
+
+	const size = 1 << 12
+	kzgSrs, err := kzg.NewSRS(2*size, big.NewInt(13))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	lookupVector := make(fr.Vector, size)
+	fvector := make(fr.Vector, size)
+	for i := 0; i < size; i++ {
+		lookupVector[i].SetUint64(uint64(i))
+		fvector[i].SetUint64(uint64((7 * i) % 256))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ProveLookupVector(kzgSrs.Pk, fvector, lookupVector)
+	}
+}
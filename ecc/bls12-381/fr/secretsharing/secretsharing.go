@@ -0,0 +1,210 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package secretsharing
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+)
+
+var (
+	ErrInvalidThreshold  = errors.New("invalid threshold: must be in [1, n]")
+	ErrInvalidID         = errors.New("invalid participant identifier")
+	ErrDuplicateID       = errors.New("duplicate participant identifier")
+	ErrNotEnoughShares   = errors.New("not enough shares")
+	ErrInvalidShare      = errors.New("share doesn't match the commitment")
+	ErrInvalidCommitment = errors.New("invalid commitment")
+)
+
+// Share is the share of the participant ID (non zero) of a secret: the
+// evaluation at ID of the sharing polynomial.
+type Share struct {
+	ID    uint64
+	Value fr.Element
+}
+
+// Split splits secret between n participants, so that any threshold of them can
+// reconstruct it with Reconstruct. shares[i] is the share of the participant
+// i+1.
+func Split(secret *fr.Element, threshold, n int) ([]Share, error) {
+	f, err := randomPolynomial(secret, threshold, n)
+	if err != nil {
+		return nil, err
+	}
+	defer erase(f)
+	return evalShares(f, n), nil
+}
+
+// Reconstruct returns the secret shared by shares, which must contain at least
+// threshold shares with distinct identifiers. It doesn't detect invalid
+// shares, use verifiable secret sharing for this.
+func Reconstruct(shares []Share, threshold int) (fr.Element, error) {
+	if threshold < 1 {
+		return fr.Element{}, ErrInvalidThreshold
+	}
+	if len(shares) < threshold {
+		return fr.Element{}, ErrNotEnoughShares
+	}
+	shares = shares[:threshold]
+	ids := make([]uint64, len(shares))
+	for i := range shares {
+		ids[i] = shares[i].ID
+	}
+	lambdas, err := LagrangeCoefficients(ids)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res, t fr.Element
+	for i := range shares {
+		t.Mul(&lambdas[i], &shares[i].Value)
+		res.Add(&res, &t)
+	}
+	return res, nil
+}
+
+// LagrangeCoefficients returns the coefficients λᵢ such that f(0) = ∑ λᵢ⋅f(idᵢ)
+// for any polynomial f of degree less than len(ids):
+//
+//	λᵢ = ∏_{j≠i} idⱼ / (idⱼ - idᵢ)
+//
+// The identifiers must be distinct and non zero.
+func LagrangeCoefficients(ids []uint64) ([]fr.Element, error) {
+	xs := make([]fr.Element, len(ids))
+	seen := make(map[uint64]bool, len(ids))
+	for i, id := range ids {
+		if id == 0 {
+			return nil, ErrInvalidID
+		}
+		if seen[id] {
+			return nil, ErrDuplicateID
+		}
+		seen[id] = true
+		xs[i].SetUint64(id)
+	}
+
+	num := make([]fr.Element, len(ids))
+	den := make([]fr.Element, len(ids))
+	var t fr.Element
+	for i := range xs {
+		num[i].SetOne()
+		den[i].SetOne()
+		for j := range xs {
+			if j == i {
+				continue
+			}
+			num[i].Mul(&num[i], &xs[j])
+			t.Sub(&xs[j], &xs[i])
+			den[i].Mul(&den[i], &t)
+		}
+	}
+	den = fr.BatchInvert(den)
+	for i := range num {
+		num[i].Mul(&num[i], &den[i])
+	}
+	return num, nil
+}
+
+// interpolate returns the polynomial of degree less than len(shares) which
+// takes the value shares[i].Value at shares[i].ID. The identifiers must be
+// distinct and non zero.
+func interpolate(shares []Share) polynomial.Polynomial {
+	n := len(shares)
+	xs := make([]fr.Element, n)
+	for i := range shares {
+		xs[i].SetUint64(shares[i].ID)
+	}
+
+	// m = ∏ (X - xᵢ)
+	m := make(polynomial.Polynomial, n+1)
+	m[0].SetOne()
+	var t fr.Element
+	for i := range xs {
+		for k := i + 1; k > 0; k-- {
+			t.Mul(&m[k], &xs[i])
+			m[k].Sub(&m[k-1], &t)
+		}
+		m[0].Mul(&m[0], &xs[i]).Neg(&m[0])
+	}
+
+	// denominators ∏_{j≠i} (xᵢ - xⱼ)
+	den := make([]fr.Element, n)
+	for i := range xs {
+		den[i].SetOne()
+		for j := range xs {
+			if j != i {
+				t.Sub(&xs[i], &xs[j])
+				den[i].Mul(&den[i], &t)
+			}
+		}
+	}
+	den = fr.BatchInvert(den)
+
+	res := make(polynomial.Polynomial, n)
+	q := make([]fr.Element, n)
+	for i := range xs {
+		// q = m / (X - xᵢ), by synthetic division
+		q[n-1].Set(&m[n])
+		for k := n - 1; k > 0; k-- {
+			t.Mul(&q[k], &xs[i])
+			q[k-1].Add(&m[k], &t)
+		}
+		t.Mul(&shares[i].Value, &den[i])
+		for k := range q {
+			var c fr.Element
+			c.Mul(&q[k], &t)
+			res[k].Add(&res[k], &c)
+		}
+	}
+	return res
+}
+
+// randomPolynomial returns a random polynomial of degree threshold-1, whose
+// constant term is secret.
+func randomPolynomial(secret *fr.Element, threshold, n int) (polynomial.Polynomial, error) {
+	if threshold < 1 || threshold > n {
+		return nil, ErrInvalidThreshold
+	}
+	f := make(polynomial.Polynomial, threshold)
+	f[0].Set(secret)
+	for i := 1; i < threshold; i++ {
+		if _, err := f[i].SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// evalShares returns the evaluations of f at 1..n.
+func evalShares(f polynomial.Polynomial, n int) []Share {
+	shares := make([]Share, n)
+	var x fr.Element
+	for i := range shares {
+		shares[i].ID = uint64(i + 1)
+		x.SetUint64(shares[i].ID)
+		shares[i].Value = f.Eval(&x)
+	}
+	return shares
+}
+
+// erase sets the coefficients of f to zero.
+func erase(f polynomial.Polynomial) {
+	for i := range f {
+		f[i].SetZero()
+	}
+}
@@ -18,13 +18,17 @@ package bls12381
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"math/rand"
+	"os"
+	"strconv"
 	"testing"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/internal/dudect"
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/prop"
 )
@@ -388,6 +392,44 @@ func TestG1AffineOps(t *testing.T) {
 		genScalar,
 	))
 
+	properties.Property("[BLS12-381] constant time scalar multiplication and ScalarMultiplication should output the same result", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var scalar big.Int
+			s.BigInt(&scalar)
+			var op1, op2 G1Jac
+			op1.ScalarMultiplicationConstantTime(&g1Gen, &scalar)
+			op2.ScalarMultiplication(&g1Gen, &scalar)
+
+			// the result of an addition of equal points and of the point at
+			// infinity are computed without special cases
+			var a, b G1Affine
+			a.ScalarMultiplicationConstantTime(&g1GenAff, &scalar)
+			b.FromJacobian(&op2)
+
+			return op1.Equal(&op2) && a.Equal(&b)
+		},
+		genScalar,
+	))
+
+	properties.Property("[BLS12-381] constant time scalar multiplication should handle 0, r and the point at infinity", prop.ForAll(
+		func(s fr.Element) bool {
+
+			var scalar big.Int
+			s.BigInt(&scalar)
+			var op1, op2, op3, op4 G1Jac
+			op1.ScalarMultiplicationConstantTime(&g1Gen, big.NewInt(0))
+			op2.ScalarMultiplicationConstantTime(&g1Gen, fr.Modulus())
+			op3.ScalarMultiplicationConstantTime(&g1Infinity, &scalar)
+			op4.ScalarMultiplicationConstantTime(&g1Gen, new(big.Int).Sub(fr.Modulus(), big.NewInt(1)))
+			var gneg G1Jac
+			gneg.Neg(&g1Gen)
+
+			return op1.Equal(&g1Infinity) && op2.Equal(&g1Infinity) && op3.Equal(&g1Infinity) && op4.Equal(&gneg)
+		},
+		genScalar,
+	))
+
 	properties.Property("[BLS12-381] scalar multiplication (GLV) should depend only on the scalar mod r", prop.ForAll(
 		func(s fr.Element) bool {
 
@@ -445,6 +487,40 @@ func TestG1AffineOps(t *testing.T) {
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
+// TestG1JacScalarMultiplicationConstantTimeLeakage runs a dudect-style
+// fixed-vs-random timing test on ScalarMultiplicationConstantTime. It is slow
+// and sensitive to the environment, so it only runs when DUDECT_MEASUREMENTS is
+// set to the number of measurements to perform.
+func TestG1JacScalarMultiplicationConstantTimeLeakage(t *testing.T) {
+	nbMeasurements, err := strconv.Atoi(os.Getenv("DUDECT_MEASUREMENTS"))
+	if err != nil || nbMeasurements <= 0 {
+		t.Skip("set DUDECT_MEASUREMENTS to run the timing leakage test")
+	}
+
+	// fix-vs-random: the fixed scalar is drawn once, so that both classes have
+	// the same size
+	var fixedFr fr.Element
+	fixedFr.SetRandom()
+	fixed := fixedFr.BigInt(new(big.Int))
+	scalars := make([]big.Int, nbMeasurements)
+	var res G1Jac
+	tStat := dudect.Run(nbMeasurements, 0.9, func(i, class int) {
+		if class == 0 {
+			scalars[i].Set(fixed)
+			return
+		}
+		var r fr.Element
+		r.SetRandom()
+		r.BigInt(&scalars[i])
+	}, func(i int) {
+		res.ScalarMultiplicationConstantTime(&g1Gen, &scalars[i])
+	})
+	t.Logf("t = %f", tStat)
+	if math.Abs(tStat) > dudect.Threshold {
+		t.Fatalf("timing leak detected, |t| = %f > %d", math.Abs(tStat), dudect.Threshold)
+	}
+}
+
 func TestG1AffineCofactorCleaning(t *testing.T) {
 	t.Parallel()
 	parameters := gopter.DefaultTestParameters()
@@ -670,6 +746,14 @@ func BenchmarkG1JacScalarMultiplication(b *testing.B) {
 		}
 	})
 
+	var constantTime G1Jac
+	b.Run("constant time", func(b *testing.B) {
+		b.ResetTimer()
+		for j := 0; j < b.N; j++ {
+			constantTime.ScalarMultiplicationConstantTime(&g1Gen, &scalar)
+		}
+	})
+
 }
 
 func BenchmarkG1AffineCofactorClearing(b *testing.B) {
@@ -0,0 +1,28 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package ipa provides an inner product argument (IPA) polynomial
+// commitment scheme over G₁, in the style of Bulletproofs (BCCGP16, BBBPWM18)
+// as used in Halo.
+//
+// The setup is transparent: the bases of the Pedersen vector commitment are
+// derived from a public seed with hash-to-curve, so that no discrete logarithm
+// relation between them is known. An opening proof is made of 2log₂(n) points
+// and two scalars, and is checked with a single multi-scalar multiplication of
+// size O(n). Several proofs can be checked at once with BatchVerify.
+//
+// The commitments are not hiding.
+package ipa
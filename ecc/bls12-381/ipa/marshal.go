@@ -0,0 +1,123 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"io"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// WriteTo writes binary encoding of the SRS
+func (srs *SRS) WriteTo(w io.Writer) (int64, error) {
+	return srs.writeTo(w)
+}
+
+// WriteRawTo writes binary encoding of the SRS to w without point compression
+func (srs *SRS) WriteRawTo(w io.Writer) (int64, error) {
+	return srs.writeTo(w, bls12381.RawEncoding())
+}
+
+func (srs *SRS) writeTo(w io.Writer, options ...func(*bls12381.Encoder)) (int64, error) {
+	enc := bls12381.NewEncoder(w, options...)
+
+	toEncode := []interface{}{
+		srs.G,
+		&srs.Q,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes SRS data from reader. The number of bases must be a
+// power of two, as in NewSRS.
+func (srs *SRS) ReadFrom(r io.Reader) (int64, error) {
+	return srs.readFrom(r)
+}
+
+// UnsafeReadFrom decodes SRS data from reader without checking
+// that point are in the correct subgroup.
+func (srs *SRS) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return srs.readFrom(r, bls12381.NoSubgroupChecks())
+}
+
+func (srs *SRS) readFrom(r io.Reader, options ...func(*bls12381.Decoder)) (int64, error) {
+	dec := bls12381.NewDecoder(r, options...)
+
+	toDecode := []interface{}{
+		&srs.G,
+		&srs.Q,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	if n := len(srs.G); n == 0 || bits.OnesCount(uint(n)) != 1 {
+		return dec.BytesRead(), ErrInvalidSRSSize
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a OpeningProof
+func (proof *OpeningProof) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12381.NewEncoder(w)
+
+	toEncode := []interface{}{
+		proof.L,
+		proof.R,
+		&proof.A,
+		&proof.ClaimedValue,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes OpeningProof data from reader.
+func (proof *OpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12381.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.L,
+		&proof.R,
+		&proof.A,
+		&proof.ClaimedValue,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
@@ -0,0 +1,396 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// This file implements the formats of the Ethereum KZG ceremony, see
+// https://github.com/ethereum/kzg-ceremony-specs. The ceremony runs 4
+// sub-ceremonies in parallel, of 4096, 8192, 16384 and 32768 G1 powers and 65
+// G2 powers. Points are encoded as 0x-prefixed hexadecimal strings of their
+// compressed encodings.
+//
+// The BLS signatures of the participants' identities and the ECDSA signatures
+// of the contributions are carried as is, they are not checked.
+
+var ErrInvalidEthereumCeremony = errors.New("invalid ethereum ceremony")
+
+// EthereumCeremony is the transcript of the Ethereum KZG ceremony
+// (transcript.json).
+type EthereumCeremony struct {
+	Transcripts                []EthereumTranscript
+	ParticipantIDs             []string
+	ParticipantECDSASignatures []string
+}
+
+// EthereumTranscript is the transcript of a sub-ceremony: the current powers of
+// tau, and for each contribution the running product [τ]G₁ and the public key
+// [x]G₂. The first running product and public key are the generators.
+type EthereumTranscript struct {
+	PowersOfTau     PowersOfTau
+	RunningProducts []bls12381.G1Affine
+	PotPubkeys      []bls12381.G2Affine
+	BLSSignatures   []string
+}
+
+// EthereumBatchContribution is a contribution to the Ethereum KZG ceremony
+// (contribution.json): a contribution to each sub-ceremony.
+type EthereumBatchContribution struct {
+	Contributions  []EthereumContribution
+	ECDSASignature string
+}
+
+// EthereumContribution is a contribution to a sub-ceremony: the powers of tau
+// after the contribution x, and the public key [x]G₂.
+type EthereumContribution struct {
+	PowersOfTau  PowersOfTau
+	PotPubkey    bls12381.G2Affine
+	BLSSignature string
+}
+
+// NewEthereumCeremony returns the initial transcript of a ceremony with a
+// sub-ceremony of numG1Powers[i] and numG2Powers[i] powers for each i.
+func NewEthereumCeremony(numG1Powers, numG2Powers []int) (*EthereumCeremony, error) {
+	if len(numG1Powers) != len(numG2Powers) {
+		return nil, ErrInvalidEthereumCeremony
+	}
+	_, _, gen1Aff, gen2Aff := bls12381.Generators()
+	c := &EthereumCeremony{Transcripts: make([]EthereumTranscript, len(numG1Powers))}
+	for i := range c.Transcripts {
+		p, err := NewPowersOfTau(numG1Powers[i], numG2Powers[i])
+		if err != nil {
+			return nil, err
+		}
+		c.Transcripts[i] = EthereumTranscript{
+			PowersOfTau:     *p,
+			RunningProducts: []bls12381.G1Affine{gen1Aff},
+			PotPubkeys:      []bls12381.G2Affine{gen2Aff},
+			BLSSignatures:   []string{""},
+		}
+	}
+	return c, nil
+}
+
+// Verify checks the transcript: each sub-ceremony is checked as by
+// VerifyContributions, without the proofs of knowledge which the Ethereum
+// ceremony doesn't have.
+func (c *EthereumCeremony) Verify() error {
+	for i := range c.Transcripts {
+		if err := c.Transcripts[i].Verify(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Verify checks the transcript of a sub-ceremony.
+func (t *EthereumTranscript) Verify() error {
+	if len(t.RunningProducts) == 0 || len(t.RunningProducts) != len(t.PotPubkeys) {
+		return ErrInvalidEthereumCeremony
+	}
+	_, _, gen1Aff, gen2Aff := bls12381.Generators()
+	if !t.RunningProducts[0].Equal(&gen1Aff) || !t.PotPubkeys[0].Equal(&gen2Aff) {
+		return ErrInvalidEthereumCeremony
+	}
+	contributions := make([]Contribution, len(t.RunningProducts)-1)
+	for i := range contributions {
+		contributions[i].RunningProduct = t.RunningProducts[i+1]
+		contributions[i].PubKey = t.PotPubkeys[i+1]
+	}
+	return verifyContributions(&t.PowersOfTau, contributions, false)
+}
+
+// BatchContribution returns the current state of the ceremony, to which a
+// participant contributes with (*EthereumBatchContribution).Contribute.
+func (c *EthereumCeremony) BatchContribution() EthereumBatchContribution {
+	var b EthereumBatchContribution
+	b.Contributions = make([]EthereumContribution, len(c.Transcripts))
+	for i := range c.Transcripts {
+		p := &c.Transcripts[i].PowersOfTau
+		b.Contributions[i].PowersOfTau.G1 = append([]bls12381.G1Affine(nil), p.G1...)
+		b.Contributions[i].PowersOfTau.G2 = append([]bls12381.G2Affine(nil), p.G2...)
+	}
+	return b
+}
+
+// Contribute updates each sub-ceremony of b with an independent random secret.
+func (b *EthereumBatchContribution) Contribute() error {
+	for i := range b.Contributions {
+		c, err := b.Contributions[i].PowersOfTau.Contribute()
+		if err != nil {
+			return err
+		}
+		b.Contributions[i].PotPubkey = c.PubKey
+		b.Contributions[i].BLSSignature = ""
+	}
+	b.ECDSASignature = ""
+	return nil
+}
+
+// Apply checks the batch contribution b of the participant id against the
+// current state of the ceremony, and adds it to the transcript.
+func (c *EthereumCeremony) Apply(b *EthereumBatchContribution, id string) error {
+	if len(b.Contributions) != len(c.Transcripts) {
+		return ErrInvalidEthereumCeremony
+	}
+	for i := range c.Transcripts {
+		previous := &c.Transcripts[i].PowersOfTau
+		next := &b.Contributions[i].PowersOfTau
+		if len(previous.G1) != len(next.G1) || len(previous.G2) != len(next.G2) || len(next.G1) < 2 {
+			return ErrInvalidEthereumCeremony
+		}
+		contribution := Contribution{
+			RunningProduct: next.G1[1],
+			PubKey:         b.Contributions[i].PotPubkey,
+		}
+		if err := verifyContribution(&previous.G1[1], &contribution, false); err != nil {
+			return err
+		}
+		if err := next.Verify(); err != nil {
+			return err
+		}
+	}
+	for i := range c.Transcripts {
+		t := &c.Transcripts[i]
+		t.PowersOfTau = b.Contributions[i].PowersOfTau
+		t.RunningProducts = append(t.RunningProducts, b.Contributions[i].PowersOfTau.G1[1])
+		t.PotPubkeys = append(t.PotPubkeys, b.Contributions[i].PotPubkey)
+		t.BLSSignatures = append(t.BLSSignatures, b.Contributions[i].BLSSignature)
+	}
+	c.ParticipantIDs = append(c.ParticipantIDs, id)
+	c.ParticipantECDSASignatures = append(c.ParticipantECDSASignatures, b.ECDSASignature)
+	return nil
+}
+
+type ethereumPowersOfTauJSON struct {
+	G1Powers []string `json:"G1Powers"`
+	G2Powers []string `json:"G2Powers"`
+}
+
+type ethereumTranscriptJSON struct {
+	NumG1Powers int                     `json:"numG1Powers"`
+	NumG2Powers int                     `json:"numG2Powers"`
+	PowersOfTau ethereumPowersOfTauJSON `json:"powersOfTau"`
+	Witness     struct {
+		RunningProducts []string `json:"runningProducts"`
+		PotPubkeys      []string `json:"potPubkeys"`
+		BLSSignatures   []string `json:"blsSignatures"`
+	} `json:"witness"`
+}
+
+type ethereumCeremonyJSON struct {
+	Transcripts                []ethereumTranscriptJSON `json:"transcripts"`
+	ParticipantIDs             []string                 `json:"participantIds"`
+	ParticipantECDSASignatures []string                 `json:"participantEcdsaSignatures"`
+}
+
+type ethereumContributionJSON struct {
+	NumG1Powers  int                     `json:"numG1Powers"`
+	NumG2Powers  int                     `json:"numG2Powers"`
+	PowersOfTau  ethereumPowersOfTauJSON `json:"powersOfTau"`
+	PotPubkey    string                  `json:"potPubkey"`
+	BLSSignature string                  `json:"blsSignature"`
+}
+
+type ethereumBatchContributionJSON struct {
+	Contributions  []ethereumContributionJSON `json:"contributions"`
+	ECDSASignature string                     `json:"ecdsaSignature"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (c *EthereumCeremony) MarshalJSON() ([]byte, error) {
+	res := ethereumCeremonyJSON{
+		Transcripts:                make([]ethereumTranscriptJSON, len(c.Transcripts)),
+		ParticipantIDs:             c.ParticipantIDs,
+		ParticipantECDSASignatures: c.ParticipantECDSASignatures,
+	}
+	for i := range c.Transcripts {
+		t := &c.Transcripts[i]
+		r := &res.Transcripts[i]
+		r.NumG1Powers = len(t.PowersOfTau.G1)
+		r.NumG2Powers = len(t.PowersOfTau.G2)
+		r.PowersOfTau = encodeEthereumPowersOfTau(&t.PowersOfTau)
+		r.Witness.RunningProducts = encodeEthereumG1(t.RunningProducts)
+		r.Witness.PotPubkeys = encodeEthereumG2(t.PotPubkeys)
+		r.Witness.BLSSignatures = t.BLSSignatures
+	}
+	return json.Marshal(&res)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The points are checked to be in
+// the prime order subgroups, see Verify for the other checks.
+func (c *EthereumCeremony) UnmarshalJSON(data []byte) error {
+	var v ethereumCeremonyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	c.Transcripts = make([]EthereumTranscript, len(v.Transcripts))
+	c.ParticipantIDs = v.ParticipantIDs
+	c.ParticipantECDSASignatures = v.ParticipantECDSASignatures
+	for i := range v.Transcripts {
+		t := &c.Transcripts[i]
+		r := &v.Transcripts[i]
+		var err error
+		if t.PowersOfTau, err = decodeEthereumPowersOfTau(&r.PowersOfTau, r.NumG1Powers, r.NumG2Powers); err != nil {
+			return err
+		}
+		if t.RunningProducts, err = decodeEthereumG1(r.Witness.RunningProducts); err != nil {
+			return err
+		}
+		if t.PotPubkeys, err = decodeEthereumG2(r.Witness.PotPubkeys); err != nil {
+			return err
+		}
+		t.BLSSignatures = r.Witness.BLSSignatures
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (b *EthereumBatchContribution) MarshalJSON() ([]byte, error) {
+	res := ethereumBatchContributionJSON{
+		Contributions:  make([]ethereumContributionJSON, len(b.Contributions)),
+		ECDSASignature: b.ECDSASignature,
+	}
+	for i := range b.Contributions {
+		c := &b.Contributions[i]
+		r := &res.Contributions[i]
+		r.NumG1Powers = len(c.PowersOfTau.G1)
+		r.NumG2Powers = len(c.PowersOfTau.G2)
+		r.PowersOfTau = encodeEthereumPowersOfTau(&c.PowersOfTau)
+		r.PotPubkey = encodeEthereumG2([]bls12381.G2Affine{c.PotPubkey})[0]
+		r.BLSSignature = c.BLSSignature
+	}
+	return json.Marshal(&res)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The points are checked to be in
+// the prime order subgroups.
+func (b *EthereumBatchContribution) UnmarshalJSON(data []byte) error {
+	var v ethereumBatchContributionJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	b.Contributions = make([]EthereumContribution, len(v.Contributions))
+	b.ECDSASignature = v.ECDSASignature
+	for i := range v.Contributions {
+		c := &b.Contributions[i]
+		r := &v.Contributions[i]
+		var err error
+		if c.PowersOfTau, err = decodeEthereumPowersOfTau(&r.PowersOfTau, r.NumG1Powers, r.NumG2Powers); err != nil {
+			return err
+		}
+		// the public key is absent from the contribution sent by the sequencer
+		if r.PotPubkey != "" {
+			pubKey, err := decodeEthereumG2([]string{r.PotPubkey})
+			if err != nil {
+				return err
+			}
+			c.PotPubkey = pubKey[0]
+		}
+		c.BLSSignature = r.BLSSignature
+	}
+	return nil
+}
+
+func encodeEthereumPowersOfTau(p *PowersOfTau) ethereumPowersOfTauJSON {
+	return ethereumPowersOfTauJSON{
+		G1Powers: encodeEthereumG1(p.G1),
+		G2Powers: encodeEthereumG2(p.G2),
+	}
+}
+
+func decodeEthereumPowersOfTau(v *ethereumPowersOfTauJSON, numG1Powers, numG2Powers int) (PowersOfTau, error) {
+	var p PowersOfTau
+	if len(v.G1Powers) != numG1Powers || len(v.G2Powers) != numG2Powers {
+		return p, ErrInvalidEthereumCeremony
+	}
+	var err error
+	if p.G1, err = decodeEthereumG1(v.G1Powers); err != nil {
+		return p, err
+	}
+	if p.G2, err = decodeEthereumG2(v.G2Powers); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+func encodeEthereumG1(points []bls12381.G1Affine) []string {
+	res := make([]string, len(points))
+	for i := range points {
+		b := points[i].Bytes()
+		res[i] = "0x" + hex.EncodeToString(b[:])
+	}
+	return res
+}
+
+func encodeEthereumG2(points []bls12381.G2Affine) []string {
+	res := make([]string, len(points))
+	for i := range points {
+		b := points[i].Bytes()
+		res[i] = "0x" + hex.EncodeToString(b[:])
+	}
+	return res
+}
+
+func decodeEthereumG1(s []string) ([]bls12381.G1Affine, error) {
+	res := make([]bls12381.G1Affine, len(s))
+	for i := range s {
+		b, err := decodeEthereumHex(s[i], bls12381.SizeOfG1AffineCompressed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = res[i].SetBytes(b); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func decodeEthereumG2(s []string) ([]bls12381.G2Affine, error) {
+	res := make([]bls12381.G2Affine, len(s))
+	for i := range s {
+		b, err := decodeEthereumHex(s[i], bls12381.SizeOfG2AffineCompressed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = res[i].SetBytes(b); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func decodeEthereumHex(s string, size int) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, ErrInvalidEthereumCeremony
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, ErrInvalidEthereumCeremony
+	}
+	return b, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthereumCeremony(t *testing.T) {
+	c, err := NewEthereumCeremony([]int{8, 16}, []int{3, 3})
+	require.NoError(t, err)
+	require.NoError(t, c.Verify())
+
+	for _, id := range []string{"eth|0x01", "git|42|alice"} {
+		// the participant receives the current state as JSON, contributes and
+		// sends it back
+		b := c.BatchContribution()
+		data, err := json.Marshal(&b)
+		require.NoError(t, err)
+		var received EthereumBatchContribution
+		require.NoError(t, json.Unmarshal(data, &received))
+		require.NoError(t, received.Contribute())
+		data, err = json.Marshal(&received)
+		require.NoError(t, err)
+
+		b = EthereumBatchContribution{}
+		require.NoError(t, json.Unmarshal(data, &b))
+		require.NoError(t, c.Apply(&b, id))
+	}
+	require.NoError(t, c.Verify())
+	assert.Len(t, c.Transcripts[1].RunningProducts, 3)
+
+	// transcript round trip
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), `"numG1Powers":16`))
+	var decoded EthereumCeremony
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, c, &decoded)
+	require.NoError(t, decoded.Verify())
+
+	// the resulting SRS is usable
+	_, err = c.Transcripts[0].PowersOfTau.SRS()
+	require.NoError(t, err)
+
+	t.Run("invalid", func(t *testing.T) {
+		// contribution not built on the current state
+		b := c.BatchContribution()
+		require.NoError(t, b.Contribute())
+		other := c.BatchContribution()
+		require.NoError(t, other.Contribute())
+		b.Contributions[1] = other.Contributions[1]
+		b.Contributions[1].PotPubkey.Double(&b.Contributions[1].PotPubkey)
+		assert.ErrorIs(t, c.Apply(&b, "bad"), ErrInvalidContribution)
+
+		// contribution with inconsistent powers
+		b = c.BatchContribution()
+		require.NoError(t, b.Contribute())
+		b.Contributions[0].PowersOfTau.G1[3] = b.Contributions[0].PowersOfTau.G1[2]
+		assert.ErrorIs(t, c.Apply(&b, "bad"), ErrInvalidPowersOfTau)
+
+		// transcript with a missing witness
+		var t2 EthereumCeremony
+		require.NoError(t, json.Unmarshal(data, &t2))
+		t2.Transcripts[0].RunningProducts = append(t2.Transcripts[0].RunningProducts[:1], t2.Transcripts[0].RunningProducts[2:]...)
+		t2.Transcripts[0].PotPubkeys = append(t2.Transcripts[0].PotPubkeys[:1], t2.Transcripts[0].PotPubkeys[2:]...)
+		assert.Error(t, t2.Verify())
+
+		// malformed points
+		assert.Error(t, json.Unmarshal([]byte(strings.Replace(string(data), `"0x`, `"0y`, 1)), &t2))
+		assert.Error(t, json.Unmarshal([]byte(strings.Replace(string(data), `"0x`, `"0x00`, 1)), &t2))
+	})
+}
+
+// TestEthereumCeremonyInitialState reads the initial state of a ceremony in
+// the format of the sequencer, with smaller sub-ceremonies: all the powers are
+// the generators, given by their compressed encodings.
+func TestEthereumCeremonyInitialState(t *testing.T) {
+	data, err := os.ReadFile("testdata/ethereum_initial_ceremony_state.json")
+	require.NoError(t, err)
+
+	var c EthereumCeremony
+	require.NoError(t, json.Unmarshal(data, &c))
+	require.NoError(t, c.Verify())
+
+	expected, err := NewEthereumCeremony([]int{4, 8, 16, 32}, []int{3, 3, 3, 3})
+	require.NoError(t, err)
+	expected.ParticipantIDs = []string{}
+	expected.ParticipantECDSASignatures = []string{}
+	assert.Equal(t, expected, &c)
+
+	encoded, err := json.Marshal(&c)
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(data)), string(encoded))
+
+	// a first participant contributes
+	b := c.BatchContribution()
+	require.NoError(t, b.Contribute())
+	require.NoError(t, c.Apply(&b, "eth|0x01"))
+	require.NoError(t, c.Verify())
+}
@@ -0,0 +1,513 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip4844 implements the KZG commitments to the blobs of EIP-4844, as
+// specified in the polynomial commitments of the Deneb consensus specs:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md
+//
+// A blob is a polynomial of degree < 4096 in evaluation form, over the 4096-th
+// roots of unity in bit-reversed order. The Fiat-Shamir challenges, the
+// encodings and the validations of the inputs follow the specs, so that the
+// results match the ones of c-kzg-4844 and of the other clients.
+package eip4844
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+const (
+	// ScalarsPerBlob is the number of field elements of a blob
+	// (FIELD_ELEMENTS_PER_BLOB).
+	ScalarsPerBlob = 4096
+
+	// SerializedScalarSize is the size of an encoded field element
+	// (BYTES_PER_FIELD_ELEMENT).
+	SerializedScalarSize = fr.Bytes
+
+	// BlobSize is the size of a blob (BYTES_PER_BLOB).
+	BlobSize = ScalarsPerBlob * SerializedScalarSize
+
+	// CompressedG1Size is the size of a commitment or a proof.
+	CompressedG1Size = bls12381.SizeOfG1AffineCompressed
+)
+
+const (
+	fiatShamirProtocolDomain      = "FSBLOBVERIFY_V1_"
+	randomChallengeKZGBatchDomain = "RCKZGBATCH___V1_"
+
+	// primitiveRootOfUnity generates the multiplicative group of fr, the roots
+	// of unity of the domain are its powers (PRIMITIVE_ROOT_OF_UNITY).
+	primitiveRootOfUnity = 7
+)
+
+var (
+	ErrInvalidScalar     = errors.New("invalid field element encoding: not canonical")
+	ErrInvalidCommitment = errors.New("invalid commitment encoding")
+	ErrInvalidProof      = errors.New("invalid proof encoding")
+	ErrVerifyProof       = errors.New("can't verify opening proof")
+	ErrBatchLength       = errors.New("blobs, commitments and proofs don't have the same length")
+	ErrInvalidSetup      = errors.New("invalid trusted setup")
+)
+
+// Blob is a blob of EIP-4844: 4096 big-endian encoded field elements.
+type Blob [BlobSize]byte
+
+// KZGCommitment is the compressed encoding of a commitment to a blob.
+type KZGCommitment [CompressedG1Size]byte
+
+// KZGProof is the compressed encoding of an opening proof.
+type KZGProof [CompressedG1Size]byte
+
+// Scalar is the big-endian encoding of a field element.
+type Scalar [SerializedScalarSize]byte
+
+// Context holds the trusted setup and the evaluation domain. It is safe for
+// concurrent use.
+type Context struct {
+	pk    kzg.ProvingKeyLagrange // Lagrange form of the SRS, in bit-reversed order
+	vk    kzg.VerifyingKey
+	roots []fr.Element // roots of unity, in bit-reversed order
+
+	rootsIndex map[fr.Element]int
+}
+
+// NewContext returns a context from the Lagrange form (in natural order) of
+// the G1 points of a trusted setup, and its first 2 G2 points.
+func NewContext(g1Lagrange []bls12381.G1Affine, g2 [2]bls12381.G2Affine) (*Context, error) {
+	if len(g1Lagrange) != ScalarsPerBlob {
+		return nil, ErrInvalidSetup
+	}
+	_, _, g1Gen, g2Gen := bls12381.Generators()
+	if !g2[0].Equal(&g2Gen) {
+		return nil, ErrInvalidSetup
+	}
+
+	ctx := &Context{}
+	ctx.pk.G1 = make([]bls12381.G1Affine, ScalarsPerBlob)
+	copy(ctx.pk.G1, g1Lagrange)
+	bitReverse(ctx.pk.G1)
+
+	ctx.vk.G1 = g1Gen
+	ctx.vk.G2 = g2
+	ctx.vk.Lines[0] = bls12381.PrecomputeLines(ctx.vk.G2[0])
+	ctx.vk.Lines[1] = bls12381.PrecomputeLines(ctx.vk.G2[1])
+
+	// ω = 7^((r-1)/4096)
+	var omega fr.Element
+	var exp big.Int
+	exp.Sub(fr.Modulus(), big.NewInt(1)).Div(&exp, big.NewInt(ScalarsPerBlob))
+	omega.SetUint64(primitiveRootOfUnity).Exp(omega, &exp)
+	ctx.roots = make([]fr.Element, ScalarsPerBlob)
+	ctx.roots[0].SetOne()
+	for i := 1; i < len(ctx.roots); i++ {
+		ctx.roots[i].Mul(&ctx.roots[i-1], &omega)
+	}
+	bitReverse(ctx.roots)
+	ctx.rootsIndex = make(map[fr.Element]int, len(ctx.roots))
+	for i := range ctx.roots {
+		ctx.rootsIndex[ctx.roots[i]] = i
+	}
+
+	return ctx, nil
+}
+
+// BlobToKZGCommitment returns the commitment to blob.
+func (ctx *Context) BlobToKZGCommitment(blob *Blob) (KZGCommitment, error) {
+	p, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	digest, err := kzg.CommitLagrange(p, ctx.pk)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	return digest.Bytes(), nil
+}
+
+// ComputeKZGProof returns the proof of the evaluation of blob at z, and the
+// evaluation.
+func (ctx *Context) ComputeKZGProof(blob *Blob, z Scalar) (KZGProof, Scalar, error) {
+	p, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+	zz, err := scalarToField(&z)
+	if err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+	proof, y, err := ctx.computeKZGProof(p, &zz)
+	if err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+	return proof, y.Bytes(), nil
+}
+
+// ComputeBlobKZGProof returns the proof of the evaluation of blob at the
+// challenge derived from blob and its commitment.
+func (ctx *Context) ComputeBlobKZGProof(blob *Blob, commitment KZGCommitment) (KZGProof, error) {
+	if _, err := commitmentToPoint(&commitment); err != nil {
+		return KZGProof{}, err
+	}
+	p, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	z := computeChallenge(blob, &commitment)
+	proof, _, err := ctx.computeKZGProof(p, &z)
+	return proof, err
+}
+
+// VerifyKZGProof checks that proof opens commitment to y at z.
+func (ctx *Context) VerifyKZGProof(commitment KZGCommitment, z, y Scalar, proof KZGProof) error {
+	c, err := commitmentToPoint(&commitment)
+	if err != nil {
+		return err
+	}
+	pr, err := proofToPoint(&proof)
+	if err != nil {
+		return err
+	}
+	zz, err := scalarToField(&z)
+	if err != nil {
+		return err
+	}
+	yy, err := scalarToField(&y)
+	if err != nil {
+		return err
+	}
+	return ctx.verifyKZGProof(&c, &zz, &yy, &pr)
+}
+
+// VerifyBlobKZGProof checks that proof opens commitment to the evaluation of
+// blob at the challenge derived from blob and commitment.
+func (ctx *Context) VerifyBlobKZGProof(blob *Blob, commitment KZGCommitment, proof KZGProof) error {
+	c, err := commitmentToPoint(&commitment)
+	if err != nil {
+		return err
+	}
+	pr, err := proofToPoint(&proof)
+	if err != nil {
+		return err
+	}
+	p, err := blobToPolynomial(blob)
+	if err != nil {
+		return err
+	}
+	z := computeChallenge(blob, &commitment)
+	y := ctx.evaluate(p, &z)
+	return ctx.verifyKZGProof(&c, &z, &y, &pr)
+}
+
+// VerifyBlobKZGProofBatch checks the proofs of the blobs as VerifyBlobKZGProof,
+// with a random linear combination of the pairing equations.
+func (ctx *Context) VerifyBlobKZGProofBatch(blobs []Blob, commitments []KZGCommitment, proofs []KZGProof) error {
+	n := len(blobs)
+	if len(commitments) != n || len(proofs) != n {
+		return ErrBatchLength
+	}
+	if n == 0 {
+		return nil
+	}
+	if n == 1 {
+		return ctx.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[0])
+	}
+
+	cs := make([]bls12381.G1Affine, n)
+	prs := make([]bls12381.G1Affine, n)
+	zs := make([]fr.Element, n)
+	ys := make([]fr.Element, n)
+	errs := make([]error, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			if cs[i], errs[i] = commitmentToPoint(&commitments[i]); errs[i] != nil {
+				return
+			}
+			if prs[i], errs[i] = proofToPoint(&proofs[i]); errs[i] != nil {
+				return
+			}
+			var p []fr.Element
+			if p, errs[i] = blobToPolynomial(&blobs[i]); errs[i] != nil {
+				return
+			}
+			zs[i] = computeChallenge(&blobs[i], &commitments[i])
+			ys[i] = ctx.evaluate(p, &zs[i])
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.verifyKZGProofBatch(commitments, cs, zs, ys, proofs, prs)
+}
+
+// computeKZGProof returns the proof of the evaluation of p at z, and the
+// evaluation (compute_kzg_proof_impl).
+func (ctx *Context) computeKZGProof(p []fr.Element, z *fr.Element) (KZGProof, fr.Element, error) {
+	y := ctx.evaluate(p, z)
+
+	// quotient (p - y) / (X - z) in evaluation form
+	q := make([]fr.Element, ScalarsPerBlob)
+	denominators := make([]fr.Element, ScalarsPerBlob)
+	inDomain := -1
+	for i := range ctx.roots {
+		denominators[i].Sub(&ctx.roots[i], z)
+		if denominators[i].IsZero() {
+			inDomain = i
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+	for i := range q {
+		if i == inDomain {
+			continue
+		}
+		q[i].Sub(&p[i], &y).Mul(&q[i], &denominators[i])
+	}
+	if inDomain >= 0 {
+		q[inDomain] = ctx.quotientWithinDomain(p, inDomain, &y)
+	}
+
+	h, err := kzg.CommitLagrange(q, ctx.pk)
+	if err != nil {
+		return KZGProof{}, fr.Element{}, err
+	}
+	return h.Bytes(), y, nil
+}
+
+// quotientWithinDomain returns the evaluation at z = ωₘ of (p - y) / (X - z),
+// with y = p(z) (compute_quotient_eval_within_domain):
+//
+//	q(z) = ∑_{i≠m} (pᵢ - y)⋅ωᵢ / (z⋅(z - ωᵢ))
+func (ctx *Context) quotientWithinDomain(p []fr.Element, m int, y *fr.Element) fr.Element {
+	z := &ctx.roots[m]
+	denominators := make([]fr.Element, 0, ScalarsPerBlob-1)
+	var d fr.Element
+	for i := range ctx.roots {
+		if i == m {
+			continue
+		}
+		d.Sub(z, &ctx.roots[i]).Mul(&d, z)
+		denominators = append(denominators, d)
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res, t fr.Element
+	j := 0
+	for i := range ctx.roots {
+		if i == m {
+			continue
+		}
+		t.Sub(&p[i], y).Mul(&t, &ctx.roots[i]).Mul(&t, &denominators[j])
+		res.Add(&res, &t)
+		j++
+	}
+	return res
+}
+
+// evaluate returns p(z), where p is in evaluation form on the roots of unity
+// in bit-reversed order (evaluate_polynomial_in_evaluation_form):
+//
+//	p(z) = (zⁿ - 1)/n ⋅ ∑ pᵢ⋅ωᵢ/(z - ωᵢ)
+func (ctx *Context) evaluate(p []fr.Element, z *fr.Element) fr.Element {
+	if i, ok := ctx.rootsIndex[*z]; ok {
+		return p[i]
+	}
+	denominators := make([]fr.Element, ScalarsPerBlob)
+	for i := range ctx.roots {
+		denominators[i].Sub(z, &ctx.roots[i])
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res, t fr.Element
+	for i := range p {
+		t.Mul(&p[i], &ctx.roots[i]).Mul(&t, &denominators[i])
+		res.Add(&res, &t)
+	}
+
+	var zn, one, nInv fr.Element
+	one.SetOne()
+	zn.Exp(*z, big.NewInt(ScalarsPerBlob)).Sub(&zn, &one)
+	nInv.SetUint64(ScalarsPerBlob).Inverse(&nInv)
+	res.Mul(&res, &zn).Mul(&res, &nInv)
+	return res
+}
+
+// verifyKZGProof checks e(C - [y]G₁, -G₂)⋅e(H, [τ - z]G₂) == 1
+// (verify_kzg_proof_impl).
+func (ctx *Context) verifyKZGProof(commitment *bls12381.G1Affine, z, y *fr.Element, proof *bls12381.G1Affine) error {
+	if err := kzg.Verify(commitment, &kzg.OpeningProof{H: *proof, ClaimedValue: *y}, *z, ctx.vk); err != nil {
+		return ErrVerifyProof
+	}
+	return nil
+}
+
+// verifyKZGProofBatch checks the openings with the random linear combination
+// of the specs (verify_kzg_proof_batch):
+//
+//	e(∑ rⁱHᵢ, -[τ]G₂)⋅e(∑ rⁱ(Cᵢ - [yᵢ]G₁ + [zᵢ]Hᵢ), G₂) == 1
+func (ctx *Context) verifyKZGProofBatch(commitmentsBytes []KZGCommitment, commitments []bls12381.G1Affine, zs, ys []fr.Element, proofsBytes []KZGProof, proofs []bls12381.G1Affine) error {
+	n := len(commitments)
+
+	// r = hash(domain, degree, n, (commitment, z, y, proof)...)
+	h := sha256.New()
+	h.Write([]byte(randomChallengeKZGBatchDomain))
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], ScalarsPerBlob)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(n))
+	h.Write(buf[:])
+	for i := 0; i < n; i++ {
+		h.Write(commitmentsBytes[i][:])
+		z := zs[i].Bytes()
+		h.Write(z[:])
+		y := ys[i].Bytes()
+		h.Write(y[:])
+		h.Write(proofsBytes[i][:])
+	}
+	r := hashToField(h.Sum(nil))
+
+	rPowers := make([]fr.Element, n)
+	rPowers[0].SetOne()
+	for i := 1; i < n; i++ {
+		rPowers[i].Mul(&rPowers[i-1], &r)
+	}
+
+	config := ecc.MultiExpConfig{}
+
+	// ∑ rⁱHᵢ
+	var proofLincomb bls12381.G1Affine
+	if _, err := proofLincomb.MultiExp(proofs, rPowers, config); err != nil {
+		return err
+	}
+
+	// ∑ rⁱ(Cᵢ + [zᵢ]Hᵢ) - [∑ rⁱyᵢ]G₁
+	points := make([]bls12381.G1Affine, 0, 2*n+1)
+	scalars := make([]fr.Element, 0, 2*n+1)
+	var ry, sumRY fr.Element
+	for i := 0; i < n; i++ {
+		points = append(points, commitments[i], proofs[i])
+		var rz fr.Element
+		rz.Mul(&rPowers[i], &zs[i])
+		scalars = append(scalars, rPowers[i], rz)
+		ry.Mul(&rPowers[i], &ys[i])
+		sumRY.Add(&sumRY, &ry)
+	}
+	sumRY.Neg(&sumRY)
+	points = append(points, ctx.vk.G1)
+	scalars = append(scalars, sumRY)
+	var rhs bls12381.G1Affine
+	if _, err := rhs.MultiExp(points, scalars, config); err != nil {
+		return err
+	}
+
+	// the Miller loop overwrites the lines, work on a copy
+	lines := ctx.vk.Lines
+	proofLincomb.Neg(&proofLincomb)
+	ok, err := bls12381.PairingCheckFixedQ(
+		[]bls12381.G1Affine{rhs, proofLincomb},
+		lines[:],
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyProof
+	}
+	return nil
+}
+
+// computeChallenge returns the Fiat-Shamir challenge of the evaluation of blob
+// (compute_challenge):
+//
+//	hash_to_field(domain || degree (16 bytes) || blob || commitment)
+func computeChallenge(blob *Blob, commitment *KZGCommitment) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(fiatShamirProtocolDomain))
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], ScalarsPerBlob)
+	h.Write(degree[:])
+	h.Write(blob[:])
+	h.Write(commitment[:])
+	return hashToField(h.Sum(nil))
+}
+
+// hashToField interprets the digest as a big-endian integer, reduced modulo r
+// (hash_to_bls_field).
+func hashToField(digest []byte) fr.Element {
+	var res fr.Element
+	res.SetBytes(digest)
+	return res
+}
+
+// blobToPolynomial decodes the field elements of the blob
+// (blob_to_polynomial).
+func blobToPolynomial(blob *Blob) ([]fr.Element, error) {
+	p := make([]fr.Element, ScalarsPerBlob)
+	for i := range p {
+		if err := p[i].SetBytesCanonical(blob[i*SerializedScalarSize : (i+1)*SerializedScalarSize]); err != nil {
+			return nil, ErrInvalidScalar
+		}
+	}
+	return p, nil
+}
+
+// scalarToField decodes a canonical field element (bytes_to_bls_field).
+func scalarToField(s *Scalar) (fr.Element, error) {
+	var res fr.Element
+	if err := res.SetBytesCanonical(s[:]); err != nil {
+		return res, ErrInvalidScalar
+	}
+	return res, nil
+}
+
+// commitmentToPoint decodes a commitment, checked to be in G1 or the point at
+// infinity (validate_kzg_g1).
+func commitmentToPoint(c *KZGCommitment) (bls12381.G1Affine, error) {
+	var p bls12381.G1Affine
+	if _, err := p.SetBytes(c[:]); err != nil {
+		return p, ErrInvalidCommitment
+	}
+	return p, nil
+}
+
+// proofToPoint decodes a proof, checked to be in G1 or the point at infinity.
+func proofToPoint(c *KZGProof) (bls12381.G1Affine, error) {
+	var p bls12381.G1Affine
+	if _, err := p.SetBytes(c[:]); err != nil {
+		return p, ErrInvalidProof
+	}
+	return p, nil
+}
+
+func bitReverse[T any](a []T) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
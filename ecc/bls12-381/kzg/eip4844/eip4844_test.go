@@ -0,0 +1,268 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testTau     = big.NewInt(424242)
+	testCtx     *Context
+	testCtxOnce sync.Once
+)
+
+// newTestSetup returns the trusted setup JSON of τ = testTau.
+func newTestSetup(t testing.TB) []byte {
+	var tau, one, n, zn, omega fr.Element
+	tau.SetBigInt(testTau)
+	one.SetOne()
+	n.SetUint64(ScalarsPerBlob)
+	zn.Exp(tau, big.NewInt(ScalarsPerBlob)).Sub(&zn, &one)
+	var exp big.Int
+	exp.Sub(fr.Modulus(), big.NewInt(1)).Div(&exp, big.NewInt(ScalarsPerBlob))
+	omega.SetUint64(primitiveRootOfUnity).Exp(omega, &exp)
+
+	// Lᵢ(τ) = ωⁱ(τⁿ - 1)/(n(τ - ωⁱ))
+	l := make([]fr.Element, ScalarsPerBlob)
+	roots := make([]fr.Element, ScalarsPerBlob)
+	roots[0].SetOne()
+	for i := range l {
+		if i > 0 {
+			roots[i].Mul(&roots[i-1], &omega)
+		}
+		l[i].Sub(&tau, &roots[i]).Mul(&l[i], &n)
+	}
+	l = fr.BatchInvert(l)
+	for i := range l {
+		l[i].Mul(&l[i], &roots[i]).Mul(&l[i], &zn)
+	}
+
+	_, _, g1Gen, g2Gen := bls12381.Generators()
+	g1 := bls12381.BatchScalarMultiplicationG1(&g1Gen, l)
+	var g2Tau bls12381.G2Affine
+	g2Tau.ScalarMultiplication(&g2Gen, testTau)
+
+	var ts TrustedSetup
+	for i := range g1 {
+		b := g1[i].Bytes()
+		ts.G1Lagrange = append(ts.G1Lagrange, "0x"+hex.EncodeToString(b[:]))
+	}
+	for _, p := range []bls12381.G2Affine{g2Gen, g2Tau} {
+		b := p.Bytes()
+		ts.G2Monomial = append(ts.G2Monomial, "0x"+hex.EncodeToString(b[:]))
+	}
+	data, err := json.Marshal(&ts)
+	require.NoError(t, err)
+	return data
+}
+
+func getTestContext(t testing.TB) *Context {
+	testCtxOnce.Do(func() {
+		var err error
+		testCtx, err = NewContextFromJSON(bytes.NewReader(newTestSetup(t)))
+		require.NoError(t, err)
+	})
+	return testCtx
+}
+
+// randomBlob returns the blob of a random polynomial, and its coefficients.
+func randomBlob(ctx *Context) (*Blob, []fr.Element) {
+	coeffs := make([]fr.Element, ScalarsPerBlob)
+	for i := range coeffs {
+		coeffs[i].SetRandom()
+	}
+	var blob Blob
+	for i := range ctx.roots {
+		y := horner(coeffs, &ctx.roots[i])
+		b := y.Bytes()
+		copy(blob[i*SerializedScalarSize:], b[:])
+	}
+	return &blob, coeffs
+}
+
+func horner(coeffs []fr.Element, z *fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res.Mul(&res, z).Add(&res, &coeffs[i])
+	}
+	return res
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	ctx := getTestContext(t)
+	blob, coeffs := randomBlob(ctx)
+
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+
+	// [p(τ)]G₁
+	var tau fr.Element
+	tau.SetBigInt(testTau)
+	pTau := horner(coeffs, &tau)
+	var expected bls12381.G1Affine
+	var b big.Int
+	expected.ScalarMultiplicationBase(pTau.BigInt(&b))
+	assert.Equal(t, KZGCommitment(expected.Bytes()), commitment)
+
+	// non canonical field element
+	bad := *blob
+	modulus := fr.Modulus().Bytes()
+	copy(bad[SerializedScalarSize:], modulus)
+	_, err = ctx.BlobToKZGCommitment(&bad)
+	assert.ErrorIs(t, err, ErrInvalidScalar)
+}
+
+func TestComputeKZGProof(t *testing.T) {
+	ctx := getTestContext(t)
+	blob, coeffs := randomBlob(ctx)
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	require.NoError(t, err)
+
+	var z fr.Element
+	z.SetRandom()
+	for _, z := range []fr.Element{z, ctx.roots[0], ctx.roots[17]} {
+		proof, y, err := ctx.ComputeKZGProof(blob, z.Bytes())
+		require.NoError(t, err)
+		expected := horner(coeffs, &z)
+		assert.Equal(t, Scalar(expected.Bytes()), y)
+		require.NoError(t, ctx.VerifyKZGProof(commitment, z.Bytes(), y, proof))
+
+		var wrong fr.Element
+		wrong.SetOne().Add(&wrong, &expected)
+		assert.ErrorIs(t, ctx.VerifyKZGProof(commitment, z.Bytes(), wrong.Bytes(), proof), ErrVerifyProof)
+	}
+
+	// non canonical evaluation point
+	_, _, err = ctx.ComputeKZGProof(blob, Scalar{0xff})
+	assert.ErrorIs(t, err, ErrInvalidScalar)
+}
+
+func TestVerifyBlobKZGProof(t *testing.T) {
+	ctx := getTestContext(t)
+
+	const n = 3
+	blobs := make([]Blob, n)
+	commitments := make([]KZGCommitment, n)
+	proofs := make([]KZGProof, n)
+	for i := range blobs {
+		blob, _ := randomBlob(ctx)
+		blobs[i] = *blob
+		var err error
+		commitments[i], err = ctx.BlobToKZGCommitment(&blobs[i])
+		require.NoError(t, err)
+		proofs[i], err = ctx.ComputeBlobKZGProof(&blobs[i], commitments[i])
+		require.NoError(t, err)
+		require.NoError(t, ctx.VerifyBlobKZGProof(&blobs[i], commitments[i], proofs[i]))
+	}
+	require.NoError(t, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	require.NoError(t, ctx.VerifyBlobKZGProofBatch(nil, nil, nil))
+
+	// proofs swapped
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	assert.ErrorIs(t, ctx.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[0]), ErrVerifyProof)
+	assert.ErrorIs(t, ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs), ErrVerifyProof)
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+
+	// the zero blob commits to the point at infinity
+	var zero Blob
+	c, err := ctx.BlobToKZGCommitment(&zero)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0xc0), c[0])
+	proof, err := ctx.ComputeBlobKZGProof(&zero, c)
+	require.NoError(t, err)
+	require.NoError(t, ctx.VerifyBlobKZGProofBatch(append(blobs, zero), append(commitments, c), append(proofs, proof)))
+
+	// invalid inputs
+	assert.ErrorIs(t, ctx.VerifyBlobKZGProofBatch(blobs, commitments[:2], proofs), ErrBatchLength)
+	// x coordinate larger than the modulus
+	bad := KZGCommitment{0x9f}
+	for i := 1; i < len(bad); i++ {
+		bad[i] = 0xff
+	}
+	assert.ErrorIs(t, ctx.VerifyBlobKZGProof(&blobs[2], bad, proofs[2]), ErrInvalidCommitment)
+	_, err = ctx.ComputeBlobKZGProof(&blobs[2], bad)
+	assert.ErrorIs(t, err, ErrInvalidCommitment)
+	badProof := KZGProof{0xc0, 1}
+	assert.ErrorIs(t, ctx.VerifyBlobKZGProof(&blobs[2], commitments[2], badProof), ErrInvalidProof)
+}
+
+func TestTrustedSetup(t *testing.T) {
+	data := newTestSetup(t)
+
+	// legacy layout
+	var ts TrustedSetup
+	require.NoError(t, json.Unmarshal(data, &ts))
+	legacy, err := json.Marshal(&trustedSetupLegacy{SetupG1Lagrange: ts.G1Lagrange, SetupG2: ts.G2Monomial})
+	require.NoError(t, err)
+	ctx, err := NewContextFromJSON(bytes.NewReader(legacy))
+	require.NoError(t, err)
+	assert.Equal(t, getTestContext(t).pk, ctx.pk)
+
+	// the Lagrange points don't sum to the generator
+	ts.G1Lagrange[1], ts.G1Lagrange[2] = ts.G1Lagrange[2], ts.G1Lagrange[0]
+	_, err = ts.Context()
+	assert.ErrorIs(t, err, ErrInvalidSetup)
+	ts.G1Lagrange = ts.G1Lagrange[1:]
+	_, err = ts.Context()
+	assert.ErrorIs(t, err, ErrInvalidSetup)
+}
+
+func BenchmarkBlobToKZGCommitment(b *testing.B) {
+	ctx := getTestContext(b)
+	blob, _ := randomBlob(ctx)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ctx.BlobToKZGCommitment(blob)
+	}
+}
+
+func BenchmarkComputeBlobKZGProof(b *testing.B) {
+	ctx := getTestContext(b)
+	blob, _ := randomBlob(ctx)
+	commitment, _ := ctx.BlobToKZGCommitment(blob)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = ctx.ComputeBlobKZGProof(blob, commitment)
+	}
+}
+
+func BenchmarkVerifyBlobKZGProofBatch(b *testing.B) {
+	ctx := getTestContext(b)
+	const n = 16
+	blobs := make([]Blob, n)
+	commitments := make([]KZGCommitment, n)
+	proofs := make([]KZGProof, n)
+	for i := range blobs {
+		blob, _ := randomBlob(ctx)
+		blobs[i] = *blob
+		commitments[i], _ = ctx.BlobToKZGCommitment(&blobs[i])
+		proofs[i], _ = ctx.ComputeBlobKZGProof(&blobs[i], commitments[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// TrustedSetup is the JSON trusted setup of the Ethereum KZG ceremony, as
+// distributed with the consensus specs and c-kzg-4844 (trusted_setup_4096.json).
+// The points are 0x-prefixed hexadecimal strings of their compressed
+// encodings.
+type TrustedSetup struct {
+	G1Lagrange []string `json:"g1_lagrange"`
+	G1Monomial []string `json:"g1_monomial,omitempty"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// trustedSetupLegacy is the layout of the first releases of the trusted setup
+// JSON.
+type trustedSetupLegacy struct {
+	SetupG1Lagrange []string `json:"setup_G1_lagrange"`
+	SetupG2         []string `json:"setup_G2"`
+}
+
+// NewContextFromJSON returns a context from the trusted setup JSON read from r.
+// The points are checked to be in the prime order subgroups, and the Lagrange
+// points to sum to the generator of G1.
+func NewContextFromJSON(r io.Reader) (*Context, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var ts TrustedSetup
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return nil, err
+	}
+	if len(ts.G1Lagrange) == 0 {
+		var legacy trustedSetupLegacy
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, err
+		}
+		ts.G1Lagrange = legacy.SetupG1Lagrange
+		ts.G2Monomial = legacy.SetupG2
+	}
+	return ts.Context()
+}
+
+// Context decodes the trusted setup and returns the corresponding context.
+func (ts *TrustedSetup) Context() (*Context, error) {
+	if len(ts.G1Lagrange) != ScalarsPerBlob || len(ts.G2Monomial) < 2 {
+		return nil, ErrInvalidSetup
+	}
+
+	g1 := make([]bls12381.G1Affine, len(ts.G1Lagrange))
+	errs := make([]error, len(g1))
+	parallel.Execute(len(g1), func(start, end int) {
+		for i := start; i < end; i++ {
+			b, err := decodeHex(ts.G1Lagrange[i], bls12381.SizeOfG1AffineCompressed)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if _, errs[i] = g1[i].SetBytes(b); errs[i] != nil {
+				return
+			}
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var g2 [2]bls12381.G2Affine
+	for i := range g2 {
+		b, err := decodeHex(ts.G2Monomial[i], bls12381.SizeOfG2AffineCompressed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = g2[i].SetBytes(b); err != nil {
+			return nil, err
+		}
+	}
+
+	// ∑ Lᵢ = 1
+	var sum bls12381.G1Jac
+	for i := range g1 {
+		sum.AddMixed(&g1[i])
+	}
+	_, _, g1Gen, _ := bls12381.Generators()
+	var sumAff bls12381.G1Affine
+	sumAff.FromJacobian(&sum)
+	if !sumAff.Equal(&g1Gen) {
+		return nil, ErrInvalidSetup
+	}
+
+	return NewContext(g1, g2)
+}
+
+func decodeHex(s string, size int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != size {
+		return nil, ErrInvalidSetup
+	}
+	return b, nil
+}
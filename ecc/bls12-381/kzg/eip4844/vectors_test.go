@@ -0,0 +1,305 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// testdata/synthetic/<handler>/<case>/data.yaml are test vectors in the
+// format of the consensus specs, written for this package. Their expected
+// outputs do not depend on the trusted setup: they cover the point at
+// infinity, the constant blobs (committed to c⋅G₁, with the point at infinity
+// as proof), the invalid inputs, and the Fiat-Shamir challenge of
+// compute_challenge. They run against the test setup.
+//
+// The reference vectors of the consensus specs need the ceremony setup, they
+// are not vendored. To run them, copy trusted_setup_4096.json to
+// testdata/trusted_setup_4096.json and the tests/general/deneb/kzg directory of
+// a consensus-spec-tests release to testdata/kzg, that is
+// testdata/kzg/<handler>/kzg-mainnet/<case>/data.yaml.
+const (
+	vectorsTrustedSetup = "testdata/trusted_setup_4096.json"
+	referenceVectors    = "testdata/kzg/%s/*/*/data.yaml"
+	syntheticVectors    = "testdata/synthetic/%s/*/data.yaml"
+)
+
+func TestSyntheticVectors(t *testing.T) {
+	ctx := getTestContext(t)
+	testVectors(t, ctx, syntheticVectors)
+
+	runVectors(t, syntheticVectors, "compute_challenge", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob       string `yaml:"blob"`
+				Commitment string `yaml:"commitment"`
+			} `yaml:"input"`
+			Output string `yaml:"output"`
+		}
+		require.NoError(t, yaml.Unmarshal(data, &test))
+
+		var blob Blob
+		var commitment KZGCommitment
+		require.NoError(t, decodeVector(test.Input.Blob, blob[:]))
+		require.NoError(t, decodeVector(test.Input.Commitment, commitment[:]))
+		challenge := computeChallenge(&blob, &commitment)
+		b := challenge.Bytes()
+		require.Equal(t, test.Output, encodeVector(b[:]))
+	})
+}
+
+func TestReferenceVectors(t *testing.T) {
+	f, err := os.Open(vectorsTrustedSetup)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("ceremony setup not found at " + vectorsTrustedSetup)
+	}
+	require.NoError(t, err)
+	defer f.Close()
+	ctx, err := NewContextFromJSON(f)
+	require.NoError(t, err)
+	testVectors(t, ctx, referenceVectors)
+}
+
+// testVectors runs the vectors of the specs handlers found with pattern, where
+// %s is the name of the handler.
+func testVectors(t *testing.T, ctx *Context, pattern string) {
+	runVectors(t, pattern, "blob_to_kzg_commitment", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob string `yaml:"blob"`
+			} `yaml:"input"`
+			Output *string `yaml:"output"`
+		}
+		require.NoError(t, yaml.Unmarshal(data, &test))
+
+		var blob Blob
+		err := decodeVector(test.Input.Blob, blob[:])
+		var commitment KZGCommitment
+		if err == nil {
+			commitment, err = ctx.BlobToKZGCommitment(&blob)
+		}
+		if test.Output == nil {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, *test.Output, encodeVector(commitment[:]))
+	})
+
+	runVectors(t, pattern, "compute_kzg_proof", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob string `yaml:"blob"`
+				Z    string `yaml:"z"`
+			} `yaml:"input"`
+			Output *[2]string `yaml:"output"`
+		}
+		require.NoError(t, yaml.Unmarshal(data, &test))
+
+		var blob Blob
+		var z, y Scalar
+		var proof KZGProof
+		err := firstError(
+			decodeVector(test.Input.Blob, blob[:]),
+			decodeVector(test.Input.Z, z[:]),
+		)
+		if err == nil {
+			proof, y, err = ctx.ComputeKZGProof(&blob, z)
+		}
+		if test.Output == nil {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, test.Output[0], encodeVector(proof[:]))
+		require.Equal(t, test.Output[1], encodeVector(y[:]))
+	})
+
+	runVectors(t, pattern, "compute_blob_kzg_proof", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob       string `yaml:"blob"`
+				Commitment string `yaml:"commitment"`
+			} `yaml:"input"`
+			Output *string `yaml:"output"`
+		}
+		require.NoError(t, yaml.Unmarshal(data, &test))
+
+		var blob Blob
+		var commitment KZGCommitment
+		var proof KZGProof
+		err := firstError(
+			decodeVector(test.Input.Blob, blob[:]),
+			decodeVector(test.Input.Commitment, commitment[:]),
+		)
+		if err == nil {
+			proof, err = ctx.ComputeBlobKZGProof(&blob, commitment)
+		}
+		if test.Output == nil {
+			require.Error(t, err)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, *test.Output, encodeVector(proof[:]))
+	})
+
+	runVectors(t, pattern, "verify_kzg_proof", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Commitment string `yaml:"commitment"`
+				Z          string `yaml:"z"`
+				Y          string `yaml:"y"`
+				Proof      string `yaml:"proof"`
+			} `yaml:"input"`
+			Output *bool `yaml:"output"`
+		}
+		require.NoError(t, yaml.Unmarshal(data, &test))
+
+		var commitment KZGCommitment
+		var z, y Scalar
+		var proof KZGProof
+		err := firstError(
+			decodeVector(test.Input.Commitment, commitment[:]),
+			decodeVector(test.Input.Z, z[:]),
+			decodeVector(test.Input.Y, y[:]),
+			decodeVector(test.Input.Proof, proof[:]),
+		)
+		if err == nil {
+			err = ctx.VerifyKZGProof(commitment, z, y, proof)
+		}
+		checkVerifyVector(t, test.Output, err)
+	})
+
+	runVectors(t, pattern, "verify_blob_kzg_proof", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blob       string `yaml:"blob"`
+				Commitment string `yaml:"commitment"`
+				Proof      string `yaml:"proof"`
+			} `yaml:"input"`
+			Output *bool `yaml:"output"`
+		}
+		require.NoError(t, yaml.Unmarshal(data, &test))
+
+		var blob Blob
+		var commitment KZGCommitment
+		var proof KZGProof
+		err := firstError(
+			decodeVector(test.Input.Blob, blob[:]),
+			decodeVector(test.Input.Commitment, commitment[:]),
+			decodeVector(test.Input.Proof, proof[:]),
+		)
+		if err == nil {
+			err = ctx.VerifyBlobKZGProof(&blob, commitment, proof)
+		}
+		checkVerifyVector(t, test.Output, err)
+	})
+
+	runVectors(t, pattern, "verify_blob_kzg_proof_batch", func(t *testing.T, data []byte) {
+		var test struct {
+			Input struct {
+				Blobs       []string `yaml:"blobs"`
+				Commitments []string `yaml:"commitments"`
+				Proofs      []string `yaml:"proofs"`
+			} `yaml:"input"`
+			Output *bool `yaml:"output"`
+		}
+		require.NoError(t, yaml.Unmarshal(data, &test))
+
+		blobs := make([]Blob, len(test.Input.Blobs))
+		commitments := make([]KZGCommitment, len(test.Input.Commitments))
+		proofs := make([]KZGProof, len(test.Input.Proofs))
+		var err error
+		for i := range blobs {
+			err = firstError(err, decodeVector(test.Input.Blobs[i], blobs[i][:]))
+		}
+		for i := range commitments {
+			err = firstError(err, decodeVector(test.Input.Commitments[i], commitments[i][:]))
+		}
+		for i := range proofs {
+			err = firstError(err, decodeVector(test.Input.Proofs[i], proofs[i][:]))
+		}
+		if err == nil {
+			err = ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+		}
+		checkVerifyVector(t, test.Output, err)
+	})
+}
+
+// runVectors runs f on the data.yaml files of the handler.
+func runVectors(t *testing.T, pattern, handler string, f func(t *testing.T, data []byte)) {
+	files, err := filepath.Glob(fmt.Sprintf(pattern, handler))
+	require.NoError(t, err)
+	require.NotEmpty(t, files, "no test vectors found for %s", handler)
+	t.Run(handler, func(t *testing.T) {
+		for _, file := range files {
+			file := file
+			t.Run(filepath.Base(filepath.Dir(file)), func(t *testing.T) {
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+				f(t, data)
+			})
+		}
+	})
+}
+
+// checkVerifyVector checks the result of a verification against the expected
+// output: true if the proof is valid, false if it is not, and null if the
+// inputs are invalid.
+func checkVerifyVector(t *testing.T, expected *bool, err error) {
+	switch {
+	case expected == nil:
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrVerifyProof)
+	case *expected:
+		require.NoError(t, err)
+	default:
+		require.ErrorIs(t, err, ErrVerifyProof)
+	}
+}
+
+func decodeVector(s string, dst []byte) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return errors.New("invalid length")
+	}
+	copy(dst, b)
+	return nil
+}
+
+func encodeVector(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
 	"github.com/consensys/gnark-crypto/fiat-shamir"
 
 	"github.com/consensys/gnark-crypto/internal/parallel"
@@ -37,6 +38,8 @@ var (
 	ErrVerifyOpeningProof            = errors.New("can't verify opening proof")
 	ErrVerifyBatchOpeningSinglePoint = errors.New("can't verify batch opening proof at single point")
 	ErrMinSRSSize                    = errors.New("minimum srs size is 2")
+	ErrInvalidPrecomputedBases       = errors.New("precomputed bases don't match the proving key")
+	ErrInvalidDomainSize             = errors.New("domain cardinality is larger than the proving key")
 )
 
 // Digest commitment of a polynomial.
@@ -45,6 +48,49 @@ type Digest = bls12381.G1Affine
 // ProvingKey used to create or open commitments
 type ProvingKey struct {
 	G1 []bls12381.G1Affine // [G₁ [α]G₁ , [α²]G₁, ... ]
+
+	precomputed *bls12381.PrecomputedBases // optional tables of multiples of G1, see UsePrecomputedBases
+}
+
+// UsePrecomputedBases sets the tables of multiples of the points of pk used by
+// Commit (and by the openings) to compute fixed-base multi-exponentiations.
+// The tables are computed from a prefix of pk.G1, for instance with
+//
+//	pb := bls12381.NewPrecomputedBases(pk.G1, maxMemory)
+//
+// and are serialized separately from the ProvingKey. Passing nil disables them.
+func (pk *ProvingKey) UsePrecomputedBases(pb *bls12381.PrecomputedBases) error {
+	if pb != nil {
+		if pb.NbBases() > len(pk.G1) {
+			return ErrInvalidPrecomputedBases
+		}
+		for i := 0; i < pb.NbBases(); i++ {
+			if !pb.Base(i).Equal(&pk.G1[i]) {
+				return ErrInvalidPrecomputedBases
+			}
+		}
+	}
+	pk.precomputed = pb
+	return nil
+}
+
+// ProvingKeyLagrange is a ProvingKey in Lagrange form over a domain, used to
+// commit to polynomials given by their evaluations on the domain.
+//
+// implements io.ReaderFrom and io.WriterTo
+type ProvingKeyLagrange struct {
+	G1 []bls12381.G1Affine // [L₀(α)]G₁, [L₁(α)]G₁, ... where Lᵢ is the i-th Lagrange polynomial of the domain
+}
+
+// Lagrange returns the Lagrange form of pk over domain, computed with an
+// inverse FFT on the first domain.Cardinality points of pk.
+func (pk *ProvingKey) Lagrange(domain *fft.Domain) (ProvingKeyLagrange, error) {
+	if domain.Cardinality > uint64(len(pk.G1)) {
+		return ProvingKeyLagrange{}, ErrInvalidDomainSize
+	}
+	return ProvingKeyLagrange{
+		G1: toLagrangeG1(pk.G1[:domain.Cardinality], domain),
+	}, nil
 }
 
 // VerifyingKey used to verify opening proofs
@@ -128,7 +174,7 @@ func NewSRS(size uint64, bAlpha *big.Int) (*SRS, error) {
 	srs.Pk.G1[0] = gen1Aff
 	srs.Vk.G1 = gen1Aff
 	srs.Vk.G2[0] = gen2Aff
-	srs.Vk.G2[1].ScalarMultiplication(&gen2Aff, bAlpha)
+	srs.Vk.G2[1].ScalarMultiplicationBaseConstantTime(bAlpha)
 	srs.Vk.Lines[0] = bls12381.PrecomputeLines(srs.Vk.G2[0])
 	srs.Vk.Lines[1] = bls12381.PrecomputeLines(srs.Vk.G2[1])
 
@@ -137,8 +183,19 @@ func NewSRS(size uint64, bAlpha *big.Int) (*SRS, error) {
 	for i := 1; i < len(alphas); i++ {
 		alphas[i].Mul(&alphas[i-1], &alpha)
 	}
-	g1s := bls12381.BatchScalarMultiplicationG1(&gen1Aff, alphas)
-	copy(srs.Pk.G1[1:], g1s)
+	// α is toxic waste: use the constant-time scalar multiplication rather than
+	// the (faster) windowed batch scalar multiplication.
+	g1s := make([]bls12381.G1Jac, len(alphas))
+	parallel.Execute(len(alphas), func(start, end int) {
+		var gen1Jac bls12381.G1Jac
+		gen1Jac.FromAffine(&gen1Aff)
+		var b big.Int
+		for i := start; i < end; i++ {
+			alphas[i].BigInt(&b)
+			g1s[i].ScalarMultiplicationConstantTime(&gen1Jac, &b)
+		}
+	})
+	copy(srs.Pk.G1[1:], bls12381.BatchJacobianToAffineG1(g1s))
 
 	return &srs, nil
 }
@@ -179,6 +236,12 @@ func Commit(p []fr.Element, pk ProvingKey, nbTasks ...int) (Digest, error) {
 	if len(nbTasks) > 0 {
 		config.NbTasks = nbTasks[0]
 	}
+	if pk.precomputed != nil && len(p) <= pk.precomputed.NbBases() {
+		if _, err := res.MultiExpPrecomputed(pk.precomputed, p, config); err != nil {
+			return Digest{}, err
+		}
+		return res, nil
+	}
 	if _, err := res.MultiExp(pk.G1[:len(p)], p, config); err != nil {
 		return Digest{}, err
 	}
@@ -186,6 +249,28 @@ func Commit(p []fr.Element, pk ProvingKey, nbTasks ...int) (Digest, error) {
 	return res, nil
 }
 
+// CommitLagrange commits to the polynomial whose evaluations on the domain of
+// pk are p, in natural order. The result is the same as Commit on the
+// canonical form of the polynomial, with the ProvingKey pk was derived from.
+func CommitLagrange(p []fr.Element, pk ProvingKeyLagrange, nbTasks ...int) (Digest, error) {
+
+	if len(p) == 0 || len(p) != len(pk.G1) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	var res bls12381.G1Affine
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	if _, err := res.MultiExp(pk.G1, p, config); err != nil {
+		return Digest{}, err
+	}
+
+	return res, nil
+}
+
 // Open computes an opening proof of polynomial p at given point.
 // fft.Domain Cardinality must be larger than p.Degree()
 func Open(p []fr.Element, point fr.Element, pk ProvingKey) (OpeningProof, error) {
@@ -263,6 +348,14 @@ func Verify(commitment *Digest, proof *OpeningProof, point fr.Element, vk Verify
 // * polynomials is the list of polynomials to open, they are supposed to be of the same size.
 // * dataTranscript extra data that might be needed to derive the challenge used for folding
 func BatchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (BatchOpeningProof, error) {
+	return BatchOpenSinglePointWithSettings(polynomials, digests, point, pk, fiatshamir.WithHash(hf, dataTranscript...))
+}
+
+// BatchOpenSinglePointWithSettings is BatchOpenSinglePoint, deriving the folding
+// challenge as described by transcriptSettings. The challenge is named
+// transcriptSettings.Prefix+"gamma", the base challenges are bound to it after
+// the point, the digests and the claimed values.
+func BatchOpenSinglePointWithSettings(polynomials [][]fr.Element, digests []Digest, point fr.Element, pk ProvingKey, transcriptSettings fiatshamir.Settings) (BatchOpeningProof, error) {
 
 	// check for invalid sizes
 	nbDigests := len(digests)
@@ -298,7 +391,7 @@ func BatchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point fr
 	wg.Wait()
 
 	// derive the challenge γ, binded to the point and the commitments
-	gamma, err := deriveGamma(point, digests, res.ClaimedValues, hf, dataTranscript...)
+	gamma, err := deriveGamma(point, digests, res.ClaimedValues, transcriptSettings)
 	if err != nil {
 		return BatchOpeningProof{}, err
 	}
@@ -358,6 +451,12 @@ func BatchOpenSinglePoint(polynomials [][]fr.Element, digests []Digest, point fr
 // * transcript extra data needed to derive the challenge used for folding.
 // * returns the folded version of batchOpeningProof, Digest, the folded version of digests
 func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.Element, hf hash.Hash, dataTranscript ...[]byte) (OpeningProof, Digest, error) {
+	return FoldProofWithSettings(digests, batchOpeningProof, point, fiatshamir.WithHash(hf, dataTranscript...))
+}
+
+// FoldProofWithSettings is FoldProof, deriving the folding challenge as
+// described by transcriptSettings, see BatchOpenSinglePointWithSettings.
+func FoldProofWithSettings(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.Element, transcriptSettings fiatshamir.Settings) (OpeningProof, Digest, error) {
 
 	nbDigests := len(digests)
 
@@ -367,9 +466,9 @@ func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.
 	}
 
 	// derive the challenge γ, binded to the point and the commitments
-	gamma, err := deriveGamma(point, digests, batchOpeningProof.ClaimedValues, hf, dataTranscript...)
+	gamma, err := deriveGamma(point, digests, batchOpeningProof.ClaimedValues, transcriptSettings)
 	if err != nil {
-		return OpeningProof{}, Digest{}, ErrInvalidNbDigests
+		return OpeningProof{}, Digest{}, err
 	}
 
 	// fold the claimed values and digests
@@ -402,9 +501,16 @@ func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.
 // * batchOpeningProof proof of correct opening on the digests
 // * dataTranscript extra data that might be needed to derive the challenge used for the folding
 func BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.Element, hf hash.Hash, vk VerifyingKey, dataTranscript ...[]byte) error {
+	return BatchVerifySinglePointWithSettings(digests, batchOpeningProof, point, vk, fiatshamir.WithHash(hf, dataTranscript...))
+}
+
+// BatchVerifySinglePointWithSettings is BatchVerifySinglePoint, deriving the
+// folding challenge as described by transcriptSettings, see
+// BatchOpenSinglePointWithSettings.
+func BatchVerifySinglePointWithSettings(digests []Digest, batchOpeningProof *BatchOpeningProof, point fr.Element, vk VerifyingKey, transcriptSettings fiatshamir.Settings) error {
 
 	// fold the proof
-	foldedProof, foldedDigest, err := FoldProof(digests, batchOpeningProof, point, hf, dataTranscript...)
+	foldedProof, foldedDigest, err := FoldProofWithSettings(digests, batchOpeningProof, point, transcriptSettings)
 	if err != nil {
 		return err
 	}
@@ -547,31 +653,42 @@ func fold(di []Digest, fai []fr.Element, ci []fr.Element) (Digest, fr.Element, e
 }
 
 // deriveGamma derives a challenge using Fiat Shamir to fold proofs.
-func deriveGamma(point fr.Element, digests []Digest, claimedValues []fr.Element, hf hash.Hash, dataTranscript ...[]byte) (fr.Element, error) {
+func deriveGamma(point fr.Element, digests []Digest, claimedValues []fr.Element, transcriptSettings fiatshamir.Settings) (fr.Element, error) {
+
+	if transcriptSettings.Transcript != nil && transcriptSettings.Duplex != nil {
+		return fr.Element{}, fiatshamir.ErrTranscriptAndDuplex
+	}
 
 	// derive the challenge gamma, binded to the point and the commitments
-	fs := fiatshamir.NewTranscript(hf, "gamma")
-	if err := fs.Bind("gamma", point.Marshal()); err != nil {
+	challengeName := transcriptSettings.Prefix + "gamma"
+	fs := transcriptSettings.Transcript
+	if fs == nil {
+		var err error
+		if fs, err = transcriptSettings.NewTranscript(challengeName); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	if err := fs.Bind(challengeName, point.Marshal()); err != nil {
 		return fr.Element{}, err
 	}
 	for i := range digests {
-		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
+		if err := fs.Bind(challengeName, digests[i].Marshal()); err != nil {
 			return fr.Element{}, err
 		}
 	}
 	for i := range claimedValues {
-		if err := fs.Bind("gamma", claimedValues[i].Marshal()); err != nil {
+		if err := fs.Bind(challengeName, claimedValues[i].Marshal()); err != nil {
 			return fr.Element{}, err
 		}
 	}
 
-	for i := 0; i < len(dataTranscript); i++ {
-		if err := fs.Bind("gamma", dataTranscript[i]); err != nil {
+	for i := 0; i < len(transcriptSettings.BaseChallenges); i++ {
+		if err := fs.Bind(challengeName, transcriptSettings.BaseChallenges[i]); err != nil {
 			return fr.Element{}, err
 		}
 	}
 
-	gammaByte, err := fs.ComputeChallenge("gamma")
+	gammaByte, err := fs.ComputeChallenge(challengeName)
 	if err != nil {
 		return fr.Element{}, err
 	}
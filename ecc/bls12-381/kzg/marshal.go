@@ -40,6 +40,25 @@ func (pk *ProvingKey) writeTo(w io.Writer, options ...func(*bls12381.Encoder)) (
 	return enc.BytesWritten(), nil
 }
 
+// WriteTo writes binary encoding of the ProvingKeyLagrange
+func (pk *ProvingKeyLagrange) WriteTo(w io.Writer) (int64, error) {
+	return pk.writeTo(w)
+}
+
+// WriteRawTo writes binary encoding of ProvingKeyLagrange to w without point compression
+func (pk *ProvingKeyLagrange) WriteRawTo(w io.Writer) (int64, error) {
+	return pk.writeTo(w, bls12381.RawEncoding())
+}
+
+func (pk *ProvingKeyLagrange) writeTo(w io.Writer, options ...func(*bls12381.Encoder)) (int64, error) {
+	// encode the ProvingKeyLagrange
+	enc := bls12381.NewEncoder(w, options...)
+	if err := enc.Encode(pk.G1); err != nil {
+		return enc.BytesWritten(), err
+	}
+	return enc.BytesWritten(), nil
+}
+
 // WriteRawTo writes binary encoding of VerifyingKey to w without point compression
 func (vk *VerifyingKey) WriteRawTo(w io.Writer) (int64, error) {
 	return vk.writeTo(w, bls12381.RawEncoding())
@@ -121,6 +140,27 @@ func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
 	return dec.BytesRead(), nil
 }
 
+// ReadFrom decodes ProvingKeyLagrange data from reader.
+func (pk *ProvingKeyLagrange) ReadFrom(r io.Reader) (int64, error) {
+	// decode the ProvingKeyLagrange
+	dec := bls12381.NewDecoder(r)
+	if err := dec.Decode(&pk.G1); err != nil {
+		return dec.BytesRead(), err
+	}
+	return dec.BytesRead(), nil
+}
+
+// UnsafeReadFrom decodes ProvingKeyLagrange data from reader without checking
+// that point are in the correct subgroup.
+func (pk *ProvingKeyLagrange) UnsafeReadFrom(r io.Reader) (int64, error) {
+	// decode the ProvingKeyLagrange
+	dec := bls12381.NewDecoder(r, bls12381.NoSubgroupChecks())
+	if err := dec.Decode(&pk.G1); err != nil {
+		return dec.BytesRead(), err
+	}
+	return dec.BytesRead(), nil
+}
+
 // ReadFrom decodes VerifyingKey data from reader.
 func (vk *VerifyingKey) ReadFrom(r io.Reader) (int64, error) {
 	// decode the VerifyingKey
@@ -242,3 +282,155 @@ func (proof *BatchOpeningProof) ReadFrom(r io.Reader) (int64, error) {
 
 	return dec.BytesRead(), nil
 }
+
+// WriteTo writes binary encoding of a MultiPointOpeningProof
+func (proof *MultiPointOpeningProof) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12381.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&proof.W,
+		&proof.WPrime,
+		proof.ClaimedValues,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes MultiPointOpeningProof data from reader.
+func (proof *MultiPointOpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12381.NewDecoder(r)
+	toDecode := []interface{}{
+		&proof.W,
+		&proof.WPrime,
+		&proof.ClaimedValues,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a MultilinearOpeningProof
+func (proof *MultilinearOpeningProof) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12381.NewEncoder(w)
+
+	toEncode := []interface{}{
+		proof.Quotients,
+		&proof.BatchedQuotient,
+		&proof.H,
+		&proof.ClaimedValue,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes MultilinearOpeningProof data from reader.
+func (proof *MultilinearOpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12381.NewDecoder(r)
+	toDecode := []interface{}{
+		&proof.Quotients,
+		&proof.BatchedQuotient,
+		&proof.H,
+		&proof.ClaimedValue,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a MultilinearBatchOpeningProof
+func (proof *MultilinearBatchOpeningProof) WriteTo(w io.Writer) (int64, error) {
+	n, err := proof.Folded.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+
+	enc := bls12381.NewEncoder(w)
+	if err := enc.Encode(proof.ClaimedValues); err != nil {
+		return n + enc.BytesWritten(), err
+	}
+
+	return n + enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes MultilinearBatchOpeningProof data from reader.
+func (proof *MultilinearBatchOpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	n, err := proof.Folded.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+
+	dec := bls12381.NewDecoder(r)
+	if err := dec.Decode(&proof.ClaimedValues); err != nil {
+		return n + dec.BytesRead(), err
+	}
+
+	return n + dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a MultilinearVerifyingKey
+func (vk *MultilinearVerifyingKey) WriteTo(w io.Writer) (int64, error) {
+	n, err := vk.Vk.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+
+	enc := bls12381.NewEncoder(w)
+	toEncode := []interface{}{
+		uint64(vk.NbVariables),
+		&vk.ShiftedG2,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return n + enc.BytesWritten(), err
+		}
+	}
+
+	return n + enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes MultilinearVerifyingKey data from reader.
+func (vk *MultilinearVerifyingKey) ReadFrom(r io.Reader) (int64, error) {
+	n, err := vk.Vk.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+
+	dec := bls12381.NewDecoder(r)
+	var nbVariables uint64
+	toDecode := []interface{}{
+		&nbVariables,
+		&vk.ShiftedG2,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return n + dec.BytesRead(), err
+		}
+	}
+	vk.NbVariables = int(nbVariables)
+
+	return n + dec.BytesRead(), nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12381
+
+import (
+	"errors"
+	"io"
+	"math"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// PrecomputedBases stores multiples of a fixed set of G1 points (typically an
+// SRS), to speed up the multi-exponentiations against these points.
+//
+// The scalars are split in windows of c bits as in MultiExp, and the windows
+// are processed in k rounds. For each base P, the table stores [2^{k·c·m}]P
+// for m < q, where q = ⌈nbWindows/k⌉; a round is then a single bucket
+// accumulation over q·n points, and the bucket reductions and doublings
+// are done k times instead of nbWindows times.
+// The more memory is available, the larger q and the fewer rounds.
+type PrecomputedBases struct {
+	c     uint64     // window size
+	k     uint64     // number of rounds
+	q     uint64     // number of multiples per base
+	table []G1Affine // table[i*q+m] = [2^{k·c·m}]bases[i]
+}
+
+// precomputedCs are the window sizes supported by MultiExpPrecomputed.
+var precomputedCs = []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+
+// NewPrecomputedBases computes the tables of multiples of bases, using at
+// most maxMemory bytes for the tables; the tables take at least the size of
+// bases.
+func NewPrecomputedBases(bases []G1Affine, maxMemory int) *PrecomputedBases {
+	n := len(bases)
+	maxMultiples := 1
+	if n > 0 && maxMemory/(n*SizeOfG1AffineUncompressed) > 1 {
+		maxMultiples = maxMemory / (n * SizeOfG1AffineUncompressed)
+	}
+
+	// we pick the window size minimizing the approximate number of group
+	// operations: the bucket accumulations, plus the bucket reductions
+	// and doublings of each round.
+	pb := &PrecomputedBases{}
+	min := math.MaxInt
+	for _, c := range precomputedCs {
+		nbChunks := int(computeNbChunks(c))
+		q := nbChunks
+		if q > maxMultiples {
+			q = maxMultiples
+		}
+		k := (nbChunks + q - 1) / q
+		q = (nbChunks + k - 1) / k // same number of rounds, less memory
+		cost := n*nbChunks + k*((1<<c)+int(c))
+		if cost < min {
+			min = cost
+			pb.c, pb.k, pb.q = c, uint64(k), uint64(q)
+		}
+	}
+
+	// compute the tables by blocks of bases, to limit the memory needed for
+	// the points in jacobian coordinates
+	const blockSize = 1 << 12
+	q := int(pb.q)
+	shift := int(pb.k * pb.c)
+	pb.table = make([]G1Affine, n*q)
+	jac := make([]G1Jac, blockSize*q)
+	for start := 0; start < n; start += blockSize {
+		end := start + blockSize
+		if end > n {
+			end = n
+		}
+		parallel.Execute(end-start, func(s, e int) {
+			for i := s; i < e; i++ {
+				jac[i*q].FromAffine(&bases[start+i])
+				for m := 1; m < q; m++ {
+					jac[i*q+m].Set(&jac[i*q+m-1])
+					for l := 0; l < shift; l++ {
+						jac[i*q+m].DoubleAssign()
+					}
+				}
+			}
+		})
+		copy(pb.table[start*q:end*q], BatchJacobianToAffineG1(jac[:(end-start)*q]))
+	}
+
+	return pb
+}
+
+// NbBases returns the number of bases of the tables.
+func (pb *PrecomputedBases) NbBases() int {
+	if pb.q == 0 {
+		return 0
+	}
+	return len(pb.table) / int(pb.q)
+}
+
+// Base returns the i-th base of the tables.
+func (pb *PrecomputedBases) Base(i int) *G1Affine {
+	return &pb.table[i*int(pb.q)]
+}
+
+// MultiExpPrecomputed computes the multi-exponentiation of the first
+// len(scalars) bases of pb by scalars, using the precomputed tables.
+//
+// This call return an error if len(scalars) > pb.NbBases() or if provided config is invalid.
+func (p *G1Affine) MultiExpPrecomputed(pb *PrecomputedBases, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Affine, error) {
+	var _p G1Jac
+	if _, err := _p.MultiExpPrecomputed(pb, scalars, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpPrecomputed computes the multi-exponentiation of the first
+// len(scalars) bases of pb by scalars, using the precomputed tables.
+//
+// This call return an error if len(scalars) > pb.NbBases() or if provided config is invalid.
+func (p *G1Jac) MultiExpPrecomputed(pb *PrecomputedBases, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Jac, error) {
+	nbPoints := len(scalars)
+	if nbPoints > pb.NbBases() {
+		return nil, errors.New("len(scalars) > number of precomputed bases")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU() * 2
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	if nbPoints == 0 {
+		p.Set(&g1Infinity)
+		return p, nil
+	}
+
+	c, k, q := pb.c, int(pb.k), int(pb.q)
+	digits, chunkStats := partitionScalars(scalars, c, config.NbTasks)
+	nbChunks := int(computeNbChunks(c))
+
+	// the windows of all the rounds share the same buckets, the last one
+	// may be larger.
+	cProcess := c
+	if lastC(c) > c {
+		cProcess = lastC(c)
+	}
+
+	// each round is split in several tasks, with their own buckets, so that
+	// we use all the CPUs even if there are few rounds.
+	points := pb.table[:nbPoints*q]
+	nbSplits := (config.NbTasks + k - 1) / k
+	if maxSplits := len(points) >> cProcess; nbSplits > maxSplits {
+		nbSplits = maxSplits
+	}
+	if nbSplits < 1 {
+		nbSplits = 1
+	}
+	splitSize := (len(points) + nbSplits - 1) / nbSplits
+
+	// we use a semaphore to limit the number of go routines running concurrently
+	// (only if nbTasks < nbCPU)
+	var sem chan struct{}
+	if config.NbTasks < runtime.NumCPU() {
+		sem = make(chan struct{}, config.NbTasks)
+		for i := 0; i < config.NbTasks; i++ {
+			sem <- struct{}{}
+		}
+		defer func() {
+			close(sem)
+		}()
+	}
+
+	chRounds := make([]chan g1JacExtended, k)
+	for r := 0; r < k; r++ {
+		chRounds[r] = make(chan g1JacExtended, 1)
+
+		// the digits of round r are those of the windows r+k·m, and they
+		// are laid out as the table.
+		roundDigits := make([]uint16, len(points))
+		var stat chunkStat
+		for m := 0; m < q; m++ {
+			j := r + k*m
+			if j >= nbChunks {
+				break
+			}
+			if chunkStats[j].nbBucketFilled > stat.nbBucketFilled {
+				stat = chunkStats[j]
+			}
+			for i := 0; i < nbPoints; i++ {
+				roundDigits[i*q+m] = digits[j*nbPoints+i]
+			}
+		}
+
+		processChunk := getChunkProcessorG1(cProcess, stat)
+		chSplit := make(chan g1JacExtended, nbSplits)
+		for start := 0; start < len(points); start += splitSize {
+			end := start + splitSize
+			if end > len(points) {
+				end = len(points)
+			}
+			go processChunk(uint64(r), chSplit, cProcess, points[start:end], roundDigits[start:end], sem)
+		}
+		go func(r, nbSplits int) {
+			total := <-chSplit
+			for i := 1; i < nbSplits; i++ {
+				s := <-chSplit
+				total.add(&s)
+			}
+			chRounds[r] <- total
+		}(r, (len(points)+splitSize-1)/splitSize)
+	}
+
+	return msmReduceChunkG1Affine(p, int(c), chRounds), nil
+}
+
+// WriteTo writes the binary encoding of the tables (compressed points) to w.
+func (pb *PrecomputedBases) WriteTo(w io.Writer) (int64, error) {
+	return pb.writeTo(w)
+}
+
+// WriteRawTo writes the binary encoding of the tables (uncompressed points) to w.
+func (pb *PrecomputedBases) WriteRawTo(w io.Writer) (int64, error) {
+	return pb.writeTo(w, RawEncoding())
+}
+
+func (pb *PrecomputedBases) writeTo(w io.Writer, options ...func(*Encoder)) (int64, error) {
+	enc := NewEncoder(w, options...)
+	if err := enc.Encode([]uint64{pb.c, pb.k, pb.q}); err != nil {
+		return enc.BytesWritten(), err
+	}
+	if err := enc.Encode(pb.table); err != nil {
+		return enc.BytesWritten(), err
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes the tables from r, checking that the points are in the
+// subgroup.
+func (pb *PrecomputedBases) ReadFrom(r io.Reader) (int64, error) {
+	return pb.readFrom(r)
+}
+
+// UnsafeReadFrom decodes the tables from r without subgroup checks.
+func (pb *PrecomputedBases) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return pb.readFrom(r, NoSubgroupChecks())
+}
+
+func (pb *PrecomputedBases) readFrom(r io.Reader, options ...func(*Decoder)) (int64, error) {
+	dec := NewDecoder(r, options...)
+	var params []uint64
+	if err := dec.Decode(&params); err != nil {
+		return dec.BytesRead(), err
+	}
+	if len(params) != 3 {
+		return dec.BytesRead(), errors.New("invalid precomputed bases parameters")
+	}
+	// k and q are those computed by NewPrecomputedBases, so that a crafted
+	// input can't make MultiExpPrecomputed allocate an arbitrary number of
+	// rounds.
+	c, k, q := params[0], params[1], params[2]
+	if !isPrecomputedC(c) {
+		return dec.BytesRead(), errors.New("invalid precomputed bases parameters")
+	}
+	if nbChunks := computeNbChunks(c); q == 0 || q > nbChunks || k != (nbChunks+q-1)/q {
+		return dec.BytesRead(), errors.New("invalid precomputed bases parameters")
+	}
+	var table []G1Affine
+	if err := dec.Decode(&table); err != nil {
+		return dec.BytesRead(), err
+	}
+	if uint64(len(table))%q != 0 {
+		return dec.BytesRead(), errors.New("invalid precomputed bases table size")
+	}
+	pb.c, pb.k, pb.q, pb.table = c, k, q, table
+	return dec.BytesRead(), nil
+}
+
+// isPrecomputedC returns true if c is one of the window sizes of
+// precomputedCs.
+func isPrecomputedC(c uint64) bool {
+	for _, cc := range precomputedCs {
+		if c == cc {
+			return true
+		}
+	}
+	return false
+}
@@ -18,11 +18,13 @@ package twistededwards
 
 import (
 	"crypto/subtle"
+	"errors"
 	"io"
 	"math/big"
 	"math/bits"
 
 	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
 )
 
 // PointAffine point on a twisted Edwards curve
@@ -266,6 +268,19 @@ func (p *PointAffine) ScalarMultiplication(p1 *PointAffine, scalar *big.Int) *Po
 	return p
 }
 
+// ScalarMultiplicationConstantTime scalar multiplication of a point
+// p1 in affine coordinates with a secret scalar in big.Int.
+// See PointExtended.ScalarMultiplicationConstantTime.
+func (p *PointAffine) ScalarMultiplicationConstantTime(p1 *PointAffine, scalar *big.Int) *PointAffine {
+
+	var p1Extended, resExtended PointExtended
+	p1Extended.FromAffine(p1)
+	resExtended.ScalarMultiplicationConstantTime(&p1Extended, scalar)
+	p.FromExtended(&resExtended)
+
+	return p
+}
+
 // setInfinity sets p to O (0:1)
 func (p *PointAffine) setInfinity() *PointAffine {
 	p.X.SetZero()
@@ -657,3 +672,130 @@ func (p *PointExtended) ScalarMultiplication(p1 *PointExtended, scalar *big.Int)
 	p.Set(&resExtended)
 	return p
 }
+
+// ScalarMultiplicationConstantTime scalar multiplication of a point
+// p1 in extended coordinates with a secret scalar in big.Int.
+//
+// It uses fixed 4-bit windows over the fr.Bytes-long big-endian encoding of the
+// scalar and scans the whole table of multiples at each step, so that neither
+// the sequence of field operations nor the memory access pattern depend on the
+// scalar. The addition formulas are complete on this curve, no special case
+// is needed for the neutral element. Note that the additions and subtractions
+// of the fr package end with a conditional reduction.
+//
+// A negative scalar, or one that does not fit in fr.Bytes, is first reduced
+// modulo the subgroup order; callers holding secrets should pass a scalar in
+// range.
+func (p *PointExtended) ScalarMultiplicationConstantTime(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	const windowSize = 4
+	const tableSize = 1 << windowSize
+
+	var _scalar big.Int
+	_scalar.Set(scalar)
+	if _scalar.Sign() == -1 || _scalar.BitLen() > 8*fr.Bytes {
+		initOnce.Do(initCurveParams)
+		_scalar.Mod(&_scalar, &curveParams.Order)
+	}
+	var sBytes [fr.Bytes]byte
+	_scalar.FillBytes(sBytes[:])
+
+	// table[i] = [i]p1
+	var table [tableSize]PointExtended
+	table[0].setInfinity()
+	table[1].Set(p1)
+	for i := 2; i < tableSize; i++ {
+		table[i].Add(&table[i-1], p1)
+	}
+
+	var res, tmp PointExtended
+	res.setInfinity()
+	for i := 0; i < 2*fr.Bytes; i++ {
+		for j := 0; j < windowSize; j++ {
+			res.Double(&res)
+		}
+		w := int(sBytes[i/2]>>(windowSize*(1-uint(i%2)))) & (tableSize - 1)
+		tmp.setInfinity()
+		for k := 1; k < tableSize; k++ {
+			tmp.cmov(subtle.ConstantTimeEq(int32(k), int32(w)), &table[k])
+		}
+		res.Add(&res, &tmp)
+	}
+
+	p.Set(&res)
+	return p
+}
+
+// cmov sets p to p1 if c == 1 and leaves p unchanged if c == 0.
+func (p *PointExtended) cmov(c int, p1 *PointExtended) *PointExtended {
+	p.X.Select(c, &p.X, &p1.X)
+	p.Y.Select(c, &p.Y, &p1.Y)
+	p.Z.Select(c, &p.Z, &p1.Z)
+	p.T.Select(c, &p.T, &p1.T)
+	return p
+}
+
+// MultiExp sets p to ∑ scalars[i]*points[i], computed with the bucket method,
+// and returns it. The scalars must be non-negative.
+//
+// The windows of c bits of the scalars are processed independently, split
+// across the CPUs, and combined at the end.
+func (p *PointExtended) MultiExp(points []PointAffine, scalars []big.Int) (*PointExtended, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("len(points) != len(scalars)")
+	}
+
+	// c ≈ log₂(n) - 2 balances the bucket accumulation and reduction costs
+	c := bits.Len(uint(len(points))) - 2
+	if c < 2 {
+		c = 2
+	} else if c > 16 {
+		c = 16
+	}
+	maxBits := 0
+	for i := range scalars {
+		if l := scalars[i].BitLen(); l > maxBits {
+			maxBits = l
+		}
+	}
+	nbWindows := (maxBits + c - 1) / c
+
+	// windows[w] = ∑ digitᵢ(w)*points[i]
+	windows := make([]PointExtended, nbWindows)
+	parallel.Execute(nbWindows, func(start, end int) {
+		var sum PointExtended
+		buckets := make([]PointExtended, (1<<c)-1)
+		for w := start; w < end; w++ {
+			for k := range buckets {
+				buckets[k].setInfinity()
+			}
+			for i := range points {
+				digit := 0
+				for b := 0; b < c; b++ {
+					digit |= int(scalars[i].Bit(w*c+b)) << b
+				}
+				if digit != 0 {
+					buckets[digit-1].MixedAdd(&buckets[digit-1], &points[i])
+				}
+			}
+
+			// ∑ (k+1)*buckets[k]
+			sum.setInfinity()
+			windows[w].setInfinity()
+			for k := len(buckets) - 1; k >= 0; k-- {
+				sum.Add(&sum, &buckets[k])
+				windows[w].Add(&windows[w], &sum)
+			}
+		}
+	})
+
+	var res PointExtended
+	res.setInfinity()
+	for w := nbWindows - 1; w >= 0; w-- {
+		for j := 0; j < c; j++ {
+			res.Double(&res)
+		}
+		res.Add(&res, &windows[w])
+	}
+	p.Set(&res)
+	return p, nil
+}
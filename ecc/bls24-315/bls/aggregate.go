@@ -0,0 +1,160 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+)
+
+var (
+	errNoPublicKey          = errors.New("no public key to aggregate")
+	errNoSignature          = errors.New("no signature to aggregate")
+	errDuplicateMessages    = errors.New("the basic scheme requires distinct messages")
+	errNotProofOfPossession = errors.New("fast aggregate verification requires the proof of possession scheme")
+)
+
+// AggregateSignatures aggregates signatures (of the same or different messages)
+// into a single signature of the same size, by adding the corresponding points.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-2.8
+func (cs Ciphersuite) AggregateSignatures(signatures ...[]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errNoSignature
+	}
+	if cs.Variant == MinSig {
+		var agg, p bls24315.G1Jac
+		var s bls24315.G1Affine
+		for i := range signatures {
+			if len(signatures[i]) != sizeG1 {
+				return nil, errInvalidSignature
+			}
+			if _, err := s.SetBytes(signatures[i]); err != nil {
+				return nil, err
+			}
+			p.FromAffine(&s)
+			agg.AddAssign(&p)
+		}
+		s.FromJacobian(&agg)
+		res := s.Bytes()
+		return res[:], nil
+	}
+	var agg, p bls24315.G2Jac
+	var s bls24315.G2Affine
+	for i := range signatures {
+		if len(signatures[i]) != sizeG2 {
+			return nil, errInvalidSignature
+		}
+		if _, err := s.SetBytes(signatures[i]); err != nil {
+			return nil, err
+		}
+		p.FromAffine(&s)
+		agg.AddAssign(&p)
+	}
+	s.FromJacobian(&agg)
+	res := s.Bytes()
+	return res[:], nil
+}
+
+// AggregatePublicKeys adds public keys sharing the same ciphersuite.
+//
+// The aggregated key is only meaningful with the proof of possession scheme,
+// where it verifies the aggregated signature of a single message
+// (see FastAggregateVerify).
+func AggregatePublicKeys(publicKeys []PublicKey) (*PublicKey, error) {
+	if len(publicKeys) == 0 {
+		return nil, errNoPublicKey
+	}
+	var res PublicKey
+	res.Ciphersuite = publicKeys[0].Ciphersuite
+	var agg1, p1 bls24315.G1Jac
+	var agg2, p2 bls24315.G2Jac
+	for i := range publicKeys {
+		if publicKeys[i].Ciphersuite != res.Ciphersuite || !publicKeys[i].isValid() {
+			return nil, errInvalidPublicKey
+		}
+		if res.Ciphersuite.Variant == MinSig {
+			p2.FromAffine(&publicKeys[i].A2)
+			agg2.AddAssign(&p2)
+		} else {
+			p1.FromAffine(&publicKeys[i].A1)
+			agg1.AddAssign(&p1)
+		}
+	}
+	res.A1.FromJacobian(&agg1)
+	res.A2.FromJacobian(&agg2)
+	return &res, nil
+}
+
+// AggregateVerify validates an aggregated signature of messages[i] by publicKeys[i].
+//
+// For the basic scheme, the messages must be distinct. If hFunc is not nil,
+// each message is first hashed with hFunc.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-3.1.1
+func AggregateVerify(publicKeys []PublicKey, messages [][]byte, sigBin []byte, hFunc hash.Hash) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, errNoPublicKey
+	}
+	msgs := make([][]byte, len(messages))
+	for i := range messages {
+		var err error
+		if msgs[i], err = prehash(messages[i], hFunc); err != nil {
+			return false, err
+		}
+	}
+	cs := publicKeys[0].Ciphersuite
+	switch cs.Scheme {
+	case Basic:
+		seen := make(map[string]struct{}, len(msgs))
+		for i := range msgs {
+			if _, ok := seen[string(msgs[i])]; ok {
+				return false, errDuplicateMessages
+			}
+			seen[string(msgs[i])] = struct{}{}
+		}
+	case MessageAugmentation:
+		for i := range msgs {
+			if i < len(publicKeys) {
+				msgs[i] = publicKeys[i].augment(msgs[i])
+			}
+		}
+	}
+	return coreAggregateVerify(publicKeys, msgs, sigBin, cs.ID())
+}
+
+// FastAggregateVerify validates an aggregated signature of the same message by
+// all publicKeys. It is only available with the proof of possession scheme, and
+// the caller is responsible for having verified the proof of possession of each
+// public key beforehand.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-3.3.4
+func FastAggregateVerify(publicKeys []PublicKey, message, sigBin []byte, hFunc hash.Hash) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, errNoPublicKey
+	}
+	if publicKeys[0].Ciphersuite.Scheme != ProofOfPossession {
+		return false, errNotProofOfPossession
+	}
+	aggPk, err := AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return false, err
+	}
+	return aggPk.Verify(sigBin, message, hFunc)
+}
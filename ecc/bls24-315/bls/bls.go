@@ -0,0 +1,373 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/signature"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	sizeFr     = fr.Bytes
+	sizeG1     = bls24315.SizeOfG1AffineCompressed
+	sizeG2     = bls24315.SizeOfG2AffineCompressed
+	keyGenSalt = "BLS-SIG-KEYGEN-SALT-"
+	minIKMSize = 32
+)
+
+var (
+	errInvalidPublicKey = errors.New("invalid public key")
+	errInvalidSignature = errors.New("invalid signature")
+	errShortIKM         = errors.New("IKM must be at least 32 bytes long")
+	errUnknownScheme    = errors.New("unknown ciphersuite")
+)
+
+// Variant selects the groups in which public keys and signatures live.
+type Variant uint8
+
+const (
+	// MinPk minimizes the size of public keys: public keys are in G1 and signatures in G2.
+	MinPk Variant = iota
+	// MinSig minimizes the size of signatures: public keys are in G2 and signatures in G1.
+	MinSig
+)
+
+// Scheme selects how the draft protects against rogue key attacks.
+type Scheme uint8
+
+const (
+	// ProofOfPossession requires public keys to come with a proof of possession
+	// of the secret key (see PrivateKey.ProvePossession), and enables FastAggregateVerify.
+	ProofOfPossession Scheme = iota
+	// Basic requires the messages in an aggregate signature to be distinct.
+	Basic
+	// MessageAugmentation prepends the public key to the message before signing it.
+	MessageAugmentation
+)
+
+// Ciphersuite describes a BLS signature ciphersuite.
+//
+// The zero value is the default ciphersuite, MinPk with ProofOfPossession.
+type Ciphersuite struct {
+	Variant Variant
+	Scheme  Scheme
+}
+
+// DefaultCiphersuite is the ciphersuite used by GenerateKey
+var DefaultCiphersuite = Ciphersuite{Variant: MinPk, Scheme: ProofOfPossession}
+
+// ID returns the ciphersuite ID, which is also the domain separation tag
+// used to hash messages to the curve.
+func (cs Ciphersuite) ID() []byte {
+	return []byte("BLS_SIG_" + cs.hashSuite() + "_" + cs.schemeTag() + "_")
+}
+
+// PopID returns the domain separation tag used to hash public keys to the
+// curve in the proof of possession.
+func (cs Ciphersuite) PopID() []byte {
+	return []byte("BLS_POP_" + cs.hashSuite() + "_POP_")
+}
+
+// hashSuite returns the hash to curve suite of the group in which signatures live
+func (cs Ciphersuite) hashSuite() string {
+	if cs.Variant == MinSig {
+		return "BLS24315G1_XMD:SHA-256_SSWU_RO"
+	}
+	return "BLS24315G2_XMD:SHA-256_SVDW_RO"
+}
+
+func (cs Ciphersuite) schemeTag() string {
+	switch cs.Scheme {
+	case Basic:
+		return "NUL"
+	case MessageAugmentation:
+		return "AUG"
+	default:
+		return "POP"
+	}
+}
+
+func (cs Ciphersuite) isValid() bool {
+	return cs.Variant <= MinSig && cs.Scheme <= MessageAugmentation
+}
+
+// PublicKey represents a BLS public key
+type PublicKey struct {
+	Ciphersuite Ciphersuite
+	A1          bls24315.G1Affine // public key, when Ciphersuite.Variant == MinPk
+	A2          bls24315.G2Affine // public key, when Ciphersuite.Variant == MinSig
+}
+
+// PrivateKey represents a BLS private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// GenerateKey generates a public and private key pair for the default
+// ciphersuite, using 32 bytes of randomness read from rand as input key material.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	return GenerateKeyWithCiphersuite(rand, DefaultCiphersuite)
+}
+
+// GenerateKeyWithCiphersuite generates a public and private key pair for the
+// given ciphersuite, using 32 bytes of randomness read from rand as input key material.
+func GenerateKeyWithCiphersuite(rand io.Reader, cs Ciphersuite) (*PrivateKey, error) {
+	ikm := make([]byte, minIKMSize)
+	if _, err := io.ReadFull(rand, ikm); err != nil {
+		return nil, err
+	}
+	return KeyGen(ikm, nil, cs)
+}
+
+// KeyGen deterministically derives a key pair from the input key material ikm
+// (at least 32 bytes) and the optional keyInfo.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-2.3
+func KeyGen(ikm, keyInfo []byte, cs Ciphersuite) (*PrivateKey, error) {
+	if len(ikm) < minIKMSize {
+		return nil, errShortIKM
+	}
+	if !cs.isValid() {
+		return nil, errUnknownScheme
+	}
+
+	// L = ceil((3 * ceil(log2(r))) / 16)
+	const L = (3*fr.Bits + 15) / 16
+
+	ikm = append(append([]byte{}, ikm...), 0)          // IKM || I2OSP(0, 1)
+	info := append(append([]byte{}, keyInfo...), 0, L) // key_info || I2OSP(L, 2)
+	salt := []byte(keyGenSalt)
+	okm := make([]byte, L)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, info), okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm).Mod(sk, fr.Modulus())
+	}
+
+	privateKey := new(PrivateKey)
+	sk.FillBytes(privateKey.scalar[:])
+	privateKey.PublicKey.Ciphersuite = cs
+	if cs.Variant == MinSig {
+		privateKey.PublicKey.A2.ScalarMultiplicationBaseConstantTime(sk)
+	} else {
+		privateKey.PublicKey.A1.ScalarMultiplicationBaseConstantTime(sk)
+	}
+	return privateKey, nil
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	if pub.Ciphersuite != xx.Ciphersuite {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.Ciphersuite = privKey.PublicKey.Ciphersuite
+	pub.A1.Set(&privKey.PublicKey.A1)
+	pub.A2.Set(&privKey.PublicKey.A2)
+	return &pub
+}
+
+// isValid implements KeyValidate: the public key must be a point of the
+// prime order subgroup, different from the identity.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-2.5
+func (pub *PublicKey) isValid() bool {
+	if !pub.Ciphersuite.isValid() {
+		return false
+	}
+	if pub.Ciphersuite.Variant == MinSig {
+		return !pub.A2.IsInfinity() && pub.A2.IsInSubGroup()
+	}
+	return !pub.A1.IsInfinity() && pub.A1.IsInSubGroup()
+}
+
+// prehash hashes the message with hFunc, or returns it as is if hFunc is nil.
+func prehash(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// augment returns the message to be hashed to the curve, that is pk || message
+// for the message augmentation scheme and message otherwise.
+func (pub *PublicKey) augment(message []byte) []byte {
+	if pub.Ciphersuite.Scheme != MessageAugmentation {
+		return message
+	}
+	return append(pub.Bytes(), message...)
+}
+
+// Sign performs the BLS signature
+//
+// Q = hash_to_point(m)
+// signature = sk ⋅ Q
+//
+// If hFunc is not nil, the message is first hashed with hFunc, then the result
+// is hashed to the curve.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-2.6
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	msg, err := prehash(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	return privKey.coreSign(privKey.PublicKey.augment(msg), privKey.PublicKey.Ciphersuite.ID())
+}
+
+// ProvePossession returns a proof of possession of the private key, that is
+// a signature of the public key under the PopID domain separation tag.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-3.3.2
+func (privKey *PrivateKey) ProvePossession() ([]byte, error) {
+	return privKey.coreSign(privKey.PublicKey.Bytes(), privKey.PublicKey.Ciphersuite.PopID())
+}
+
+func (privKey *PrivateKey) coreSign(msg, dst []byte) ([]byte, error) {
+	scalar := new(big.Int).SetBytes(privKey.scalar[:])
+	if privKey.PublicKey.Ciphersuite.Variant == MinSig {
+		Q, err := bls24315.HashToG1(msg, dst)
+		if err != nil {
+			return nil, err
+		}
+		Q.ScalarMultiplicationConstantTime(&Q, scalar)
+		sig := Q.Bytes()
+		return sig[:], nil
+	}
+	Q, err := bls24315.HashToG2(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	Q.ScalarMultiplicationConstantTime(&Q, scalar)
+	sig := Q.Bytes()
+	return sig[:], nil
+}
+
+// Verify validates the BLS signature
+//
+// e(g1, signature) ?= e(pk, hash_to_point(m))
+//
+// for MinPk, and symmetrically for MinSig.
+//
+// If hFunc is not nil, the message is first hashed with hFunc, then the result
+// is hashed to the curve.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-2.7
+func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	msg, err := prehash(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+	return coreAggregateVerify([]PublicKey{*publicKey}, [][]byte{publicKey.augment(msg)}, sigBin, publicKey.Ciphersuite.ID())
+}
+
+// VerifyPossession validates a proof of possession of the private key
+// associated to the public key.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-3.3.3
+func (publicKey *PublicKey) VerifyPossession(proof []byte) (bool, error) {
+	return coreAggregateVerify([]PublicKey{*publicKey}, [][]byte{publicKey.Bytes()}, proof, publicKey.Ciphersuite.PopID())
+}
+
+// coreAggregateVerify checks that
+//
+// e(g1, signature) ?= ∏ e(pkᵢ, hash_to_point(mᵢ))
+//
+// for MinPk, and symmetrically for MinSig. All public keys must share
+// the same ciphersuite.
+//
+// https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05#section-2.9
+func coreAggregateVerify(publicKeys []PublicKey, messages [][]byte, sigBin, dst []byte) (bool, error) {
+	if len(publicKeys) == 0 || len(publicKeys) != len(messages) {
+		return false, errors.New("the number of public keys and messages must match and be non zero")
+	}
+	cs := publicKeys[0].Ciphersuite
+	for i := range publicKeys {
+		if publicKeys[i].Ciphersuite != cs || !publicKeys[i].isValid() {
+			return false, errInvalidPublicKey
+		}
+	}
+
+	_, _, g1, g2 := bls24315.Generators()
+	P := make([]bls24315.G1Affine, len(publicKeys)+1)
+	Q := make([]bls24315.G2Affine, len(publicKeys)+1)
+
+	if cs.Variant == MinSig {
+		// signature is in G1, public keys in G2
+		if len(sigBin) != sizeG1 {
+			return false, errInvalidSignature
+		}
+		if _, err := P[0].SetBytes(sigBin); err != nil {
+			return false, err
+		}
+		Q[0].Neg(&g2)
+		for i := range publicKeys {
+			var err error
+			if P[i+1], err = bls24315.HashToG1(messages[i], dst); err != nil {
+				return false, err
+			}
+			Q[i+1].Set(&publicKeys[i].A2)
+		}
+	} else {
+		// signature is in G2, public keys in G1
+		if len(sigBin) != sizeG2 {
+			return false, errInvalidSignature
+		}
+		if _, err := Q[0].SetBytes(sigBin); err != nil {
+			return false, err
+		}
+		P[0].Neg(&g1)
+		for i := range publicKeys {
+			var err error
+			if Q[i+1], err = bls24315.HashToG2(messages[i], dst); err != nil {
+				return false, err
+			}
+			P[i+1].Set(&publicKeys[i].A1)
+		}
+	}
+
+	return bls24315.PairingCheck(P, Q)
+}
@@ -0,0 +1,286 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+var ciphersuites = []Ciphersuite{
+	{Variant: MinPk, Scheme: ProofOfPossession},
+	{Variant: MinPk, Scheme: Basic},
+	{Variant: MinPk, Scheme: MessageAugmentation},
+	{Variant: MinSig, Scheme: ProofOfPossession},
+	{Variant: MinSig, Scheme: Basic},
+	{Variant: MinSig, Scheme: MessageAugmentation},
+}
+
+func TestBLS(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = 2
+	} else {
+		parameters.MinSuccessfulTests = 10
+	}
+	properties := gopter.NewProperties(parameters)
+
+	for _, cs := range ciphersuites {
+		cs := cs
+		properties.Property("[BLS24-315] "+string(cs.ID())+" test the signing and verification", prop.ForAll(
+			func() bool {
+
+				privKey, _ := GenerateKeyWithCiphersuite(rand.Reader, cs)
+				publicKey := privKey.PublicKey
+
+				msg := []byte("testing BLS")
+				hFunc := sha256.New()
+				sig, _ := privKey.Sign(msg, hFunc)
+				flag, _ := publicKey.Verify(sig, msg, hFunc)
+				if !flag {
+					return false
+				}
+
+				// wrong message
+				flag, _ = publicKey.Verify(sig, []byte("wrong message"), hFunc)
+				return !flag
+			},
+		))
+
+		properties.Property("[BLS24-315] "+string(cs.ID())+" test the signing and verification (pre-hashed)", prop.ForAll(
+			func() bool {
+
+				privKey, _ := GenerateKeyWithCiphersuite(rand.Reader, cs)
+				publicKey := privKey.PublicKey
+
+				msg := []byte("testing BLS")
+				sig, _ := privKey.Sign(msg, nil)
+				flag, _ := publicKey.Verify(sig, msg, nil)
+
+				return flag
+			},
+		))
+
+		properties.Property("[BLS24-315] "+string(cs.ID())+" test aggregate verification", prop.ForAll(
+			func() bool {
+				const n = 3
+				publicKeys := make([]PublicKey, n)
+				messages := make([][]byte, n)
+				signatures := make([][]byte, n)
+				for i := 0; i < n; i++ {
+					privKey, _ := GenerateKeyWithCiphersuite(rand.Reader, cs)
+					publicKeys[i] = privKey.PublicKey
+					messages[i] = []byte{byte(i)}
+					signatures[i], _ = privKey.Sign(messages[i], nil)
+				}
+				sig, err := cs.AggregateSignatures(signatures...)
+				if err != nil {
+					return false
+				}
+				flag, _ := AggregateVerify(publicKeys, messages, sig, nil)
+				if !flag {
+					return false
+				}
+
+				// swap two messages
+				messages[0], messages[1] = messages[1], messages[0]
+				flag, _ = AggregateVerify(publicKeys, messages, sig, nil)
+				return !flag
+			},
+		))
+	}
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestProofOfPossession(t *testing.T) {
+	t.Parallel()
+
+	for _, variant := range []Variant{MinPk, MinSig} {
+		cs := Ciphersuite{Variant: variant, Scheme: ProofOfPossession}
+
+		const n = 4
+		publicKeys := make([]PublicKey, n)
+		signatures := make([][]byte, n)
+		msg := []byte("testing BLS fast aggregate verify")
+		for i := 0; i < n; i++ {
+			privKey, err := GenerateKeyWithCiphersuite(rand.Reader, cs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			publicKeys[i] = privKey.PublicKey
+
+			proof, err := privKey.ProvePossession()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := publicKeys[i].VerifyPossession(proof); !ok || err != nil {
+				t.Fatal("proof of possession should verify")
+			}
+			// a signature of the public key is not a proof of possession
+			sig, err := privKey.Sign(publicKeys[i].Bytes(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, _ := publicKeys[i].VerifyPossession(sig); ok {
+				t.Fatal("signature of the public key should not be a valid proof of possession")
+			}
+
+			signatures[i], err = privKey.Sign(msg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sig, err := cs.AggregateSignatures(signatures...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := FastAggregateVerify(publicKeys, msg, sig, nil); !ok || err != nil {
+			t.Fatal("fast aggregate verify should succeed")
+		}
+		if ok, _ := FastAggregateVerify(publicKeys[1:], msg, sig, nil); ok {
+			t.Fatal("fast aggregate verify should fail with a missing public key")
+		}
+		if ok, _ := FastAggregateVerify(publicKeys, []byte("wrong message"), sig, nil); ok {
+			t.Fatal("fast aggregate verify should fail with a wrong message")
+		}
+	}
+
+	// fast aggregate verify is not available without proofs of possession
+	privKey, _ := GenerateKeyWithCiphersuite(rand.Reader, Ciphersuite{Scheme: Basic})
+	sig, _ := privKey.Sign([]byte("msg"), nil)
+	if _, err := FastAggregateVerify([]PublicKey{privKey.PublicKey}, []byte("msg"), sig, nil); err != errNotProofOfPossession {
+		t.Fatal("expected error for fast aggregate verify with the basic scheme")
+	}
+}
+
+func TestBasicSchemeDistinctMessages(t *testing.T) {
+	t.Parallel()
+
+	cs := Ciphersuite{Variant: MinPk, Scheme: Basic}
+	publicKeys := make([]PublicKey, 2)
+	signatures := make([][]byte, 2)
+	msg := []byte("same message")
+	for i := range publicKeys {
+		privKey, _ := GenerateKeyWithCiphersuite(rand.Reader, cs)
+		publicKeys[i] = privKey.PublicKey
+		signatures[i], _ = privKey.Sign(msg, nil)
+	}
+	sig, err := cs.AggregateSignatures(signatures...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AggregateVerify(publicKeys, [][]byte{msg, msg}, sig, nil); err != errDuplicateMessages {
+		t.Fatal("expected error for duplicate messages with the basic scheme")
+	}
+}
+
+func TestInvalidPublicKey(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("msg")
+	sig, _ := privKey.Sign(msg, nil)
+
+	// the identity is not a valid public key
+	var pk PublicKey
+	if ok, err := pk.Verify(sig, msg, nil); ok || err != errInvalidPublicKey {
+		t.Fatal("expected error for identity public key")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	for _, cs := range ciphersuites {
+		privKey, err := GenerateKeyWithCiphersuite(rand.Reader, cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var pk PublicKey
+		pk.Ciphersuite = cs
+		if _, err := pk.SetBytes(privKey.PublicKey.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if !pk.Equal(&privKey.PublicKey) {
+			t.Fatal("public key marshal round trip failed")
+		}
+
+		var sk PrivateKey
+		sk.PublicKey.Ciphersuite = cs
+		if _, err := sk.SetBytes(privKey.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+		if !sk.PublicKey.Equal(&privKey.PublicKey) || sk.scalar != privKey.scalar {
+			t.Fatal("private key marshal round trip failed")
+		}
+	}
+}
+
+func TestKeyGen(t *testing.T) {
+	t.Parallel()
+
+	if _, err := KeyGen(make([]byte, 31), nil, DefaultCiphersuite); err != errShortIKM {
+		t.Fatal("expected error for short IKM")
+	}
+
+	// KeyGen is deterministic
+	ikm := make([]byte, 32)
+	sk1, err := KeyGen(ikm, []byte("info"), DefaultCiphersuite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk2, _ := KeyGen(ikm, []byte("info"), DefaultCiphersuite)
+	sk3, _ := KeyGen(ikm, nil, DefaultCiphersuite)
+	if sk1.scalar != sk2.scalar || sk1.scalar == sk3.scalar {
+		t.Fatal("KeyGen should be deterministic and depend on keyInfo")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkSignBLS(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+
+	msg := []byte("benchmarking BLS sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerifyBLS(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("benchmarking BLS sign()")
+	sig, _ := privKey.Sign(msg, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, msg, nil)
+	}
+}
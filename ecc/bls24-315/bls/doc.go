@@ -0,0 +1,31 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package bls provides BLS signature scheme on the bls24-315 curve.
+//
+// The implementation follows the IETF draft draft-irtf-cfrg-bls-signature-05. Both
+// variants are supported:
+//   - minimal-pubkey-size (MinPk): public keys in G1, signatures in G2,
+//   - minimal-signature-size (MinSig): public keys in G2, signatures in G1,
+//
+// together with the three schemes of the draft (basic, message augmentation and
+// proof of possession). The default ciphersuite is MinPk with proof of possession,
+// that is BLS_SIG_BLS24315G2_XMD:SHA-256_SVDW_RO_POP_.
+//
+// Documentation:
+// - IETF draft: https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05
+// - Hash to curve: https://datatracker.ietf.org/doc/html/rfc9380
+package bls
@@ -0,0 +1,97 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+)
+
+var errScalarBiggerThanRMod = errors.New("scalar >= r_mod")
+
+// size returns the size of the compressed public key
+func (pk *PublicKey) size() int {
+	if pk.Ciphersuite.Variant == MinSig {
+		return sizeG2
+	}
+	return sizeG1
+}
+
+// Bytes returns the binary representation of the public key, that is
+// the compressed point of G1 (MinPk) or G2 (MinSig).
+//
+// The ciphersuite is not part of the encoding.
+func (pk *PublicKey) Bytes() []byte {
+	if pk.Ciphersuite.Variant == MinSig {
+		res := pk.A2.Bytes()
+		return res[:]
+	}
+	res := pk.A1.Bytes()
+	return res[:]
+}
+
+// SetBytes sets pk from binary representation in buf, according to
+// pk.Ciphersuite (MinPk with proof of possession if not set).
+// buf represents a compressed point of G1 (MinPk) or G2 (MinSig); the
+// point is checked to be on the curve and in the prime order subgroup.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < pk.size() {
+		return 0, io.ErrShortBuffer
+	}
+	if pk.Ciphersuite.Variant == MinSig {
+		return pk.A2.SetBytes(buf[:sizeG2])
+	}
+	return pk.A1.SetBytes(buf[:sizeG1])
+}
+
+// Bytes returns the binary representation of pk,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	pkBin := privKey.PublicKey.Bytes()
+	res := make([]byte, len(pkBin)+sizeFr)
+	copy(res, pkBin)
+	subtle.ConstantTimeCopy(1, res[len(pkBin):], privKey.scalar[:])
+	return res
+}
+
+// SetBytes sets pk from buf, where buf is interpreted
+// as  publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// The public key is decoded according to privKey.PublicKey.Ciphersuite.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	n := privKey.PublicKey.size()
+	if len(buf) < n+sizeFr {
+		return 0, io.ErrShortBuffer
+	}
+	if _, err := privKey.PublicKey.SetBytes(buf[:n]); err != nil {
+		return 0, err
+	}
+	if new(big.Int).SetBytes(buf[n:n+sizeFr]).Cmp(fr.Modulus()) >= 0 {
+		return 0, errScalarBiggerThanRMod
+	}
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[n:n+sizeFr])
+	return n + sizeFr, nil
+}
@@ -89,7 +89,7 @@ func GenerateKey(rand io.Reader) (*PrivateKey, error) {
 
 	privateKey := new(PrivateKey)
 	k.FillBytes(privateKey.scalar[:sizeFr])
-	privateKey.PublicKey.A.ScalarMultiplication(&g, k)
+	privateKey.PublicKey.A.ScalarMultiplicationConstantTime(&g, k)
 	return privateKey, nil
 }
 
@@ -207,7 +207,7 @@ func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error)
 			}
 
 			var P bls24315.G1Affine
-			P.ScalarMultiplicationBase(k)
+			P.ScalarMultiplicationBaseConstantTime(k)
 			kInv.ModInverse(k, order)
 
 			P.X.BigInt(r)
@@ -469,6 +469,10 @@ func setup(c Circuit, assignment WireAssignment, transcriptSettings fiatshamir.S
 		option(&o)
 	}
 
+	if transcriptSettings.Transcript != nil && transcriptSettings.Duplex != nil {
+		return o, fiatshamir.ErrTranscriptAndDuplex
+	}
+
 	o.nbVars = assignment.NumVars()
 	nbInstances := assignment.NumInstances()
 	if 1<<o.nbVars != nbInstances {
@@ -490,7 +494,9 @@ func setup(c Circuit, assignment WireAssignment, transcriptSettings fiatshamir.S
 
 	if transcriptSettings.Transcript == nil {
 		challengeNames := ChallengeNames(o.sorted, o.nbVars, transcriptSettings.Prefix)
-		o.transcript = fiatshamir.NewTranscript(transcriptSettings.Hash, challengeNames...)
+		if o.transcript, err = transcriptSettings.NewTranscript(challengeNames...); err != nil {
+			return o, err
+		}
 		for i := range transcriptSettings.BaseChallenges {
 			if err = o.transcript.Bind(challengeNames[0], transcriptSettings.BaseChallenges[i]); err != nil {
 				return o, err
@@ -0,0 +1,31 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package secretsharing provides Shamir secret sharing over fr, Feldman and
+// Pedersen verifiable secret sharing with commitments in G1, and a Pedersen
+// distributed key generation.
+//
+// A secret is shared between n participants, identified by 1..n, so that any
+// threshold of them can reconstruct it and fewer learn nothing about it. With
+// verifiable secret sharing, the dealer also publishes a commitment to the
+// sharing polynomial, against which each participant checks its share. In the
+// distributed key generation, each participant deals a random secret, and the
+// group secret is the sum of the secrets of the qualified dealers: no party
+// learns it, but any threshold of participants can use it.
+//
+// See Gennaro, Jarecki, Krawczyk and Rabin, "Secure Distributed Key
+// Generation for Discrete-Log Based Cryptosystems" (J. Cryptology, 2007).
+package secretsharing
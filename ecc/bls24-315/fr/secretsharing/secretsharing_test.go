@@ -0,0 +1,173 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package secretsharing
+
+import (
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShamir(t *testing.T) {
+	const threshold, n = 3, 5
+	var secret fr.Element
+	secret.SetRandom()
+
+	shares, err := Split(&secret, threshold, n)
+	require.NoError(t, err)
+	require.Len(t, shares, n)
+
+	// any threshold of shares reconstruct the secret
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		s := make([]Share, len(subset))
+		for i, j := range subset {
+			s[i] = shares[j]
+		}
+		res, err := Reconstruct(s, threshold)
+		require.NoError(t, err)
+		assert.True(t, res.Equal(&secret), "subset %v", subset)
+	}
+
+	// fewer shares don't
+	_, err = Reconstruct(shares[:threshold-1], threshold)
+	assert.ErrorIs(t, err, ErrNotEnoughShares)
+	res, err := Reconstruct(shares[:threshold-1], threshold-1)
+	require.NoError(t, err)
+	assert.False(t, res.Equal(&secret))
+
+	_, err = Reconstruct([]Share{shares[0], shares[0], shares[1]}, threshold)
+	assert.ErrorIs(t, err, ErrDuplicateID)
+	_, err = Split(&secret, n+1, n)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+	_, err = Split(&secret, 0, n)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+
+	// threshold 1: every share is the secret
+	shares, err = Split(&secret, 1, n)
+	require.NoError(t, err)
+	for i := range shares {
+		assert.True(t, shares[i].Value.Equal(&secret))
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	var secret fr.Element
+	secret.SetRandom()
+	f, err := randomPolynomial(&secret, 4, 6)
+	require.NoError(t, err)
+	shares := evalShares(f, 6)
+
+	g := interpolate([]Share{shares[5], shares[1], shares[3], shares[2]})
+	assert.True(t, f.Equal(g))
+}
+
+func TestFeldman(t *testing.T) {
+	const threshold, n = 3, 5
+	var secret fr.Element
+	secret.SetRandom()
+
+	shares, commitment, err := SplitFeldman(&secret, threshold, n)
+	require.NoError(t, err)
+	require.Len(t, commitment, threshold)
+
+	var expected curve.G1Affine
+	var b big.Int
+	expected.ScalarMultiplicationBase(secret.BigInt(&b))
+	assert.True(t, expected.Equal(&commitment[0]))
+
+	for i := range shares {
+		assert.NoError(t, commitment.Verify(&shares[i]))
+		v := commitment.Eval(shares[i].ID)
+		expected.ScalarMultiplicationBase(shares[i].Value.BigInt(&b))
+		assert.True(t, expected.Equal(&v))
+	}
+
+	// invalid shares
+	bad := shares[1]
+	bad.Value.Add(&bad.Value, &secret)
+	assert.ErrorIs(t, commitment.Verify(&bad), ErrInvalidShare)
+	bad = shares[1]
+	bad.ID = 3
+	assert.ErrorIs(t, commitment.Verify(&bad), ErrInvalidShare)
+	bad.ID = 0
+	assert.ErrorIs(t, commitment.Verify(&bad), ErrInvalidID)
+	assert.ErrorIs(t, FeldmanCommitment(nil).Verify(&shares[0]), ErrInvalidCommitment)
+}
+
+func TestPedersen(t *testing.T) {
+	const threshold, n = 3, 5
+	var secret fr.Element
+	secret.SetRandom()
+
+	shares, commitment, err := SplitPedersen(&secret, threshold, n)
+	require.NoError(t, err)
+	require.Len(t, commitment, threshold)
+
+	for i := range shares {
+		assert.NoError(t, commitment.Verify(&shares[i]))
+	}
+	s := make([]Share, threshold)
+	for i := range s {
+		s[i] = shares[n-1-i].Share
+	}
+	res, err := Reconstruct(s, threshold)
+	require.NoError(t, err)
+	assert.True(t, res.Equal(&secret))
+
+	// the commitment hides the secret
+	var expected curve.G1Affine
+	var b big.Int
+	expected.ScalarMultiplicationBase(secret.BigInt(&b))
+	assert.False(t, expected.Equal(&commitment[0]))
+
+	// invalid shares
+	bad := shares[2]
+	bad.Blinding.Add(&bad.Blinding, &secret)
+	assert.ErrorIs(t, commitment.Verify(&bad), ErrInvalidShare)
+	bad = shares[2]
+	bad.Value.Add(&bad.Value, &secret)
+	assert.ErrorIs(t, commitment.Verify(&bad), ErrInvalidShare)
+
+	g, h := PedersenGenerators()
+	assert.False(t, g.Equal(&h))
+	assert.True(t, h.IsInSubGroup())
+}
+
+func BenchmarkSplitPedersen(b *testing.B) {
+	const threshold, n = 11, 32
+	var secret fr.Element
+	secret.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = SplitPedersen(&secret, threshold, n)
+	}
+}
+
+func BenchmarkReconstruct(b *testing.B) {
+	const threshold, n = 11, 32
+	var secret fr.Element
+	secret.SetRandom()
+	shares, _ := Split(&secret, threshold, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Reconstruct(shares, threshold)
+	}
+}
@@ -80,6 +80,134 @@ func TestVectorEmptyRoundTrip(t *testing.T) {
 	assert.True(reflect.DeepEqual(v3, v2))
 }
 
+func TestVectorOps(t *testing.T) {
+	assert := require.New(t)
+
+	// minParallelLen + 3 exercises the parallel split
+	for _, n := range []int{0, 1, 2, 7, 64, 257, minParallelLen + 3} {
+		a, b := make(Vector, n), make(Vector, n)
+		for i := 0; i < n; i++ {
+			a[i].SetRandom()
+			b[i].SetRandom()
+		}
+		// ensure the reduction paths are exercised
+		if n > 1 {
+			a[0].SetOne().Neg(&a[0])
+			b[0].SetOne()
+			a[1].SetZero()
+			b[1].SetOne()
+		}
+		var c Element
+		c.SetRandom()
+
+		add, sub, mul, scalarMul := make(Vector, n), make(Vector, n), make(Vector, n), make(Vector, n)
+		add.Add(a, b)
+		sub.Sub(a, b)
+		mul.Mul(a, b)
+		scalarMul.ScalarMul(a, &c)
+
+		var sum, innerProduct, tmp Element
+		for i := 0; i < n; i++ {
+			assert.True(tmp.Add(&a[i], &b[i]).Equal(&add[i]), "Add")
+			assert.True(tmp.Sub(&a[i], &b[i]).Equal(&sub[i]), "Sub")
+			assert.True(tmp.Mul(&a[i], &b[i]).Equal(&mul[i]), "Mul")
+			assert.True(tmp.Mul(&a[i], &c).Equal(&scalarMul[i]), "ScalarMul")
+			sum.Add(&sum, &a[i])
+			innerProduct.Add(&innerProduct, tmp.Mul(&a[i], &b[i]))
+		}
+		s := a.Sum()
+		assert.True(s.Equal(&sum), "Sum")
+		ip := a.InnerProduct(b)
+		assert.True(ip.Equal(&innerProduct), "InnerProduct")
+
+		// operations can be done in place
+		a.Add(a, b)
+		assert.True(reflect.DeepEqual(a, add), "Add in place")
+	}
+
+	assert.Panics(func() {
+		v := make(Vector, 2)
+		v.Add(v, make(Vector, 1))
+	}, "Add should panic on length mismatch")
+	assert.Panics(func() {
+		v := make(Vector, 2)
+		v.InnerProduct(make(Vector, 1))
+	}, "InnerProduct should panic on length mismatch")
+}
+
+func BenchmarkVectorOps(b *testing.B) {
+	const N = 1 << 20
+	a, c, res := make(Vector, N), make(Vector, N), make(Vector, N)
+	for i := 0; i < N; i++ {
+		a[i].SetRandom()
+		c[i].SetRandom()
+	}
+
+	b.Run("Add", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Add(a, c)
+		}
+	})
+	b.Run("Sub", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Sub(a, c)
+		}
+	})
+
+	// the sequential loops are the baseline of the parallel operations
+	b.Run("ScalarMul/sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < N; j++ {
+				res[j].Mul(&a[j], &c[0])
+			}
+		}
+	})
+	b.Run("ScalarMul", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.ScalarMul(a, &c[0])
+		}
+	})
+	b.Run("Mul/sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < N; j++ {
+				res[j].Mul(&a[j], &c[j])
+			}
+		}
+	})
+	b.Run("Mul", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.Mul(a, c)
+		}
+	})
+	b.Run("Sum/sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var sum Element
+			for j := 0; j < N; j++ {
+				sum.Add(&sum, &a[j])
+			}
+		}
+	})
+	b.Run("Sum", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = a.Sum()
+		}
+	})
+	b.Run("InnerProduct/sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var sum, tmp Element
+			for j := 0; j < N; j++ {
+				tmp.Mul(&a[j], &c[j])
+				sum.Add(&sum, &tmp)
+			}
+		}
+	})
+	b.Run("InnerProduct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = a.InnerProduct(c)
+		}
+	})
+}
+
 func (vector *Vector) unmarshalBinaryAsync(data []byte) error {
 	r := bytes.NewReader(data)
 	_, err, chErr := vector.AsyncReadFrom(r)
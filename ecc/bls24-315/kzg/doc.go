@@ -14,5 +14,11 @@
 
 // Code generated by consensys/gnark-crypto DO NOT EDIT
 
-// Package kzg provides a KZG commitment scheme.
+// Package kzg provides a KZG commitment scheme, and the powers-of-tau
+// ceremony to generate its SRS.
+//
+// Multilinear polynomials, given by their evaluations on the hypercube, can be
+// committed with Commit and opened with the Zeromorph protocol, see
+// OpenMultilinear. The verifier needs a MultilinearVerifyingKey, which holds a
+// power of τ in G₂ depending on the size of the proving key.
 package kzg
@@ -27,6 +27,7 @@ import (
 	"github.com/consensys/gnark-crypto/ecc/bls24-315"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/fft"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
 
 	"github.com/consensys/gnark-crypto/utils"
 )
@@ -110,6 +111,53 @@ func TestCommitLagrange(t *testing.T) {
 
 	// compare the results
 	assert.True(digestCanonical.Equal(&digestLagrange), "error CommitLagrange")
+
+	// same with the Lagrange proving key
+	d.FFT(pol, fft.DIF)
+	fft.BitReverse(pol)
+	pkLagrange2, err := testSrs.Pk.Lagrange(d)
+	assert.NoError(err)
+	assert.Equal(lagrange, pkLagrange2.G1)
+	digestLagrange, err = CommitLagrange(pol, pkLagrange2)
+	assert.NoError(err)
+	assert.True(digestCanonical.Equal(&digestLagrange), "error CommitLagrange")
+
+	_, err = CommitLagrange(pol[:size-1], pkLagrange2)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = testSrs.Pk.Lagrange(fft.NewDomain(uint64(2 * len(testSrs.Pk.G1))))
+	assert.ErrorIs(err, ErrInvalidDomainSize)
+}
+
+func TestLagrangeDomain(t *testing.T) {
+	assert := require.New(t)
+
+	// a domain whose generator is not the default one of its cardinality
+	const size = 16
+	var w, tau, x, l fr.Element
+	d := fft.NewDomain(size, fft.WithoutPrecompute())
+	w.Exp(d.Generator, big.NewInt(3))
+	d.Generator = w
+	d.GeneratorInv.Inverse(&w)
+
+	pk, err := testSrs.Pk.Lagrange(d)
+	assert.NoError(err)
+
+	// [Lᵢ(α)]G₁ with Lᵢ(α) = ωⁱ(αⁿ-1)/(n(α-ωⁱ))
+	tau.SetBigInt(bAlpha)
+	var one, n, num fr.Element
+	one.SetOne()
+	n.SetUint64(size)
+	num.Exp(tau, big.NewInt(size)).Sub(&num, &one)
+	_, _, g1Gen, _ := bls24315.Generators()
+	x.SetOne()
+	var expected bls24315.G1Affine
+	var b big.Int
+	for i := 0; i < size; i++ {
+		l.Sub(&tau, &x).Mul(&l, &n).Inverse(&l).Mul(&l, &num).Mul(&l, &x)
+		expected.ScalarMultiplication(&g1Gen, l.BigInt(&b))
+		assert.True(expected.Equal(&pk.G1[i]), "error lagrange conversion")
+		x.Mul(&x, &w)
+	}
 }
 
 func TestDividePolyByXminusA(t *testing.T) {
@@ -159,6 +207,10 @@ func TestSerializationSRS(t *testing.T) {
 	assert.NoError(t, err)
 	t.Run("proving key round-trip", utils.SerializationRoundTrip(&srs.Pk))
 	t.Run("proving key raw round-trip", utils.SerializationRoundTripRaw(&srs.Pk))
+	pkLagrange, err := srs.Pk.Lagrange(fft.NewDomain(32))
+	assert.NoError(t, err)
+	t.Run("lagrange proving key round-trip", utils.SerializationRoundTrip(&pkLagrange))
+	t.Run("lagrange proving key raw round-trip", utils.SerializationRoundTripRaw(&pkLagrange))
 	t.Run("verifying key round-trip", utils.SerializationRoundTrip(&srs.Vk))
 	t.Run("whole SRS round-trip", utils.SerializationRoundTrip(srs))
 }
@@ -196,6 +248,29 @@ func TestCommit(t *testing.T) {
 
 }
 
+func TestCommitPrecomputed(t *testing.T) {
+	assert := require.New(t)
+
+	pk := ProvingKey{G1: testSrs.Pk.G1}
+	pb := bls24315.NewPrecomputedBases(pk.G1[:100], 100*8*bls24315.SizeOfG1AffineUncompressed)
+	assert.NoError(pk.UsePrecomputedBases(pb))
+
+	// polynomials smaller and larger than the precomputed tables
+	for _, size := range []int{60, 100, 150} {
+		f := randomPolynomial(size)
+		expected, err := Commit(f, testSrs.Pk)
+		assert.NoError(err)
+		digest, err := Commit(f, pk)
+		assert.NoError(err)
+		assert.True(digest.Equal(&expected), "commitment with precomputed bases differs")
+	}
+
+	// the tables must be computed from the points of the proving key
+	other := make([]bls24315.G1Affine, 10)
+	copy(other, pk.G1[1:])
+	assert.ErrorIs(pk.UsePrecomputedBases(bls24315.NewPrecomputedBases(other, 0)), ErrInvalidPrecomputedBases)
+}
+
 func TestVerifySinglePoint(t *testing.T) {
 
 	// create a polynomial
@@ -363,6 +438,38 @@ func TestBatchVerifySinglePoint(t *testing.T) {
 	}
 }
 
+func TestBatchVerifySinglePointDuplex(t *testing.T) {
+	assert := require.New(t)
+
+	f := make([][]fr.Element, 4)
+	digests := make([]Digest, len(f))
+	for i := range f {
+		f[i] = randomPolynomial(20)
+		digests[i], _ = Commit(f[i], testSrs.Pk)
+	}
+	var point fr.Element
+	point.SetString("4321")
+
+	newDuplex := func(absorbed string) *fiatshamir.Duplex {
+		d := fiatshamir.NewDuplex(sha256.New(), []byte("kzg"))
+		d.Absorb([]byte(absorbed))
+		return d
+	}
+
+	proof, err := BatchOpenSinglePointWithSettings(f, digests, point, testSrs.Pk, fiatshamir.WithDuplex(newDuplex("a")))
+	assert.NoError(err)
+	assert.NoError(BatchVerifySinglePointWithSettings(digests, &proof, point, testSrs.Vk, fiatshamir.WithDuplex(newDuplex("a"))))
+
+	// the folding challenge is bound to the state of the duplex
+	assert.Error(BatchVerifySinglePointWithSettings(digests, &proof, point, testSrs.Vk, fiatshamir.WithDuplex(newDuplex("b"))))
+
+	// a transcript and a duplex can't be used together
+	settings := fiatshamir.WithDuplex(newDuplex("a"))
+	settings.Transcript = fiatshamir.NewTranscript(sha256.New(), "gamma")
+	_, err = BatchOpenSinglePointWithSettings(f, digests, point, testSrs.Pk, settings)
+	assert.ErrorIs(err, fiatshamir.ErrTranscriptAndDuplex)
+}
+
 func TestBatchVerifyMultiPoints(t *testing.T) {
 
 	// create polynomials
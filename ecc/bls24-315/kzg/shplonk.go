@@ -0,0 +1,465 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+var (
+	ErrInvalidNbPoints  = errors.New("number of sets of points is not the same as the number of polynomials")
+	ErrZeroNbPoints     = errors.New("empty set of points")
+	ErrDuplicatedPoints = errors.New("the points at which a polynomial is opened must be distinct")
+)
+
+// MultiPointOpeningProof SHPLONK proof for opening several polynomials, each
+// at its own set of points.
+//
+// Besides the claimed values, the proof consists of two points of G₁,
+// regardless of the number of polynomials and points.
+//
+// implements io.ReaderFrom and io.WriterTo
+type MultiPointOpeningProof struct {
+	// W commitment to ∑ᵢγⁱZ_{T∖Sᵢ}(fᵢ-rᵢ)/Z_T
+	W bls24315.G1Affine
+
+	// WPrime commitment to L/(X-z) where L = ∑ᵢγⁱZ_{T∖Sᵢ}(z)(fᵢ-rᵢ(z)) - Z_T(z)W
+	WPrime bls24315.G1Affine
+
+	// ClaimedValues purported values, ClaimedValues[i][j] = fᵢ(points[i][j])
+	ClaimedValues [][]fr.Element
+}
+
+// OpenMultiPoints creates a SHPLONK opening proof of polynomials[i] at each of
+// the points in points[i] (BDFG20, https://eprint.iacr.org/2020/081.pdf, section 4).
+// It's an interactive protocol, made non-interactive using Fiat Shamir.
+//
+// * polynomials is the list of polynomials to open
+// * digests is the list of committed polynomials to open, need to derive the challenges using Fiat Shamir
+// * points[i] is the set of (distinct) points at which polynomials[i] is opened
+// * dataTranscript extra data that might be needed to derive the challenges
+func OpenMultiPoints(polynomials [][]fr.Element, digests []Digest, points [][]fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (MultiPointOpeningProof, error) {
+	return OpenMultiPointsWithSettings(polynomials, digests, points, pk, fiatshamir.WithHash(hf, dataTranscript...))
+}
+
+// OpenMultiPointsWithSettings is OpenMultiPoints, deriving the challenges as
+// described by transcriptSettings. The challenges are named
+// transcriptSettings.Prefix+"gamma" and transcriptSettings.Prefix+"z", the base
+// challenges are bound to the first one after the digests, the points and the
+// claimed values.
+func OpenMultiPointsWithSettings(polynomials [][]fr.Element, digests []Digest, points [][]fr.Element, pk ProvingKey, transcriptSettings fiatshamir.Settings) (MultiPointOpeningProof, error) {
+
+	if err := checkMultiPointsSizes(len(polynomials), digests, points); err != nil {
+		return MultiPointOpeningProof{}, err
+	}
+	maxSize := 0
+	for _, p := range polynomials {
+		if len(p) == 0 || len(p) > len(pk.G1) {
+			return MultiPointOpeningProof{}, ErrInvalidPolynomialSize
+		}
+		if len(p) > maxSize {
+			maxSize = len(p)
+		}
+	}
+
+	var res MultiPointOpeningProof
+
+	// compute the purported values
+	res.ClaimedValues = make([][]fr.Element, len(polynomials))
+	for i := range polynomials {
+		res.ClaimedValues[i] = make([]fr.Element, len(points[i]))
+		for j := range points[i] {
+			res.ClaimedValues[i][j] = eval(polynomials[i], points[i][j])
+		}
+	}
+
+	// derive the challenge γ, binded to the points, the commitments and the claimed values
+	fs, err := newMultiPointsTranscript(transcriptSettings)
+	if err != nil {
+		return MultiPointOpeningProof{}, err
+	}
+	gamma, err := deriveChallengeMultiPoints(fs, transcriptSettings, digests, points, res.ClaimedValues)
+	if err != nil {
+		return MultiPointOpeningProof{}, err
+	}
+
+	// rᵢ interpolates fᵢ on Sᵢ, Z_{T∖Sᵢ} vanishes on the points of the other sets
+	r := make([][]fr.Element, len(polynomials))
+	zTMinusS := make([][]fr.Element, len(polynomials))
+	sizeF := 0
+	for i := range polynomials {
+		if r[i], err = interpolate(points[i], res.ClaimedValues[i]); err != nil {
+			return MultiPointOpeningProof{}, err
+		}
+		zTMinusS[i] = vanishingPolynomial(pointsExcept(points, i))
+		size := len(polynomials[i])
+		if len(r[i]) > size {
+			size = len(r[i])
+		}
+		if size+len(zTMinusS[i])-1 > sizeF {
+			sizeF = size + len(zTMinusS[i]) - 1
+		}
+	}
+
+	// f = ∑ᵢγⁱZ_{T∖Sᵢ}(fᵢ-rᵢ)
+	f := make([]fr.Element, sizeF)
+	var gammaI fr.Element
+	gammaI.SetOne()
+	for i := range polynomials {
+		fMinusR := make([]fr.Element, len(polynomials[i]))
+		copy(fMinusR, polynomials[i])
+		fMinusR = subPolynomial(fMinusR, r[i])
+		term := mulPolynomials(zTMinusS[i], fMinusR)
+		var t fr.Element
+		for j := range term {
+			t.Mul(&term[j], &gammaI)
+			f[j].Add(&f[j], &t)
+		}
+		gammaI.Mul(&gammaI, &gamma)
+	}
+
+	// h = f/Z_T, the division is exact since Z_Sᵢ divides fᵢ-rᵢ
+	h := f
+	var zero fr.Element
+	for i := range points {
+		for j := range points[i] {
+			h = dividePolyByXminusA(h, zero, points[i][j])
+		}
+	}
+	if len(h) == 0 {
+		h = make([]fr.Element, 1)
+	}
+	if res.W, err = Commit(h, pk); err != nil {
+		return MultiPointOpeningProof{}, err
+	}
+
+	// derive the challenge z, binded to W
+	z, err := deriveChallengeZ(fs, transcriptSettings.Prefix, &res.W)
+	if err != nil {
+		return MultiPointOpeningProof{}, err
+	}
+
+	// L = ∑ᵢγⁱZ_{T∖Sᵢ}(z)(fᵢ-rᵢ(z)) - Z_T(z)h
+	sizeL := maxSize
+	if len(h) > sizeL {
+		sizeL = len(h)
+	}
+	l := make([]fr.Element, sizeL)
+	var c, t fr.Element
+	gammaI.SetOne()
+	for i := range polynomials {
+		c = eval(zTMinusS[i], z)
+		c.Mul(&c, &gammaI)
+		ri := eval(r[i], z)
+		for j := range polynomials[i] {
+			t.Mul(&polynomials[i][j], &c)
+			l[j].Add(&l[j], &t)
+		}
+		t.Mul(&ri, &c)
+		l[0].Sub(&l[0], &t)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	zT := evalVanishingPolynomial(points, z)
+	for j := range h {
+		t.Mul(&h[j], &zT)
+		l[j].Sub(&l[j], &t)
+	}
+
+	// W' = [L/(X-z)], L(z) = 0 by construction
+	q := dividePolyByXminusA(l, zero, z)
+	if len(q) == 0 {
+		q = make([]fr.Element, 1)
+	}
+	if res.WPrime, err = Commit(q, pk); err != nil {
+		return MultiPointOpeningProof{}, err
+	}
+
+	return res, nil
+}
+
+// VerifyMultiPoints verifies a SHPLONK opening proof of a list of polynomials,
+// each at its own set of points.
+//
+// * digests is the list of committed polynomials
+// * proof is the opening proof returned by OpenMultiPoints
+// * points[i] is the set of points at which digests[i] is opened
+// * dataTranscript extra data that might be needed to derive the challenges
+func VerifyMultiPoints(digests []Digest, proof *MultiPointOpeningProof, points [][]fr.Element, hf hash.Hash, vk VerifyingKey, dataTranscript ...[]byte) error {
+	return VerifyMultiPointsWithSettings(digests, proof, points, vk, fiatshamir.WithHash(hf, dataTranscript...))
+}
+
+// VerifyMultiPointsWithSettings is VerifyMultiPoints, deriving the challenges
+// as described by transcriptSettings, see OpenMultiPointsWithSettings.
+func VerifyMultiPointsWithSettings(digests []Digest, proof *MultiPointOpeningProof, points [][]fr.Element, vk VerifyingKey, transcriptSettings fiatshamir.Settings) error {
+
+	if err := checkMultiPointsSizes(len(proof.ClaimedValues), digests, points); err != nil {
+		return err
+	}
+	for i := range points {
+		if len(proof.ClaimedValues[i]) != len(points[i]) {
+			return ErrInvalidNbPoints
+		}
+	}
+
+	// derive the challenges γ and z
+	fs, err := newMultiPointsTranscript(transcriptSettings)
+	if err != nil {
+		return err
+	}
+	gamma, err := deriveChallengeMultiPoints(fs, transcriptSettings, digests, points, proof.ClaimedValues)
+	if err != nil {
+		return err
+	}
+	z, err := deriveChallengeZ(fs, transcriptSettings.Prefix, &proof.W)
+	if err != nil {
+		return err
+	}
+
+	// F = ∑ᵢγⁱZ_{T∖Sᵢ}(z)[fᵢ(α)]G₁ - [∑ᵢγⁱZ_{T∖Sᵢ}(z)rᵢ(z)]G₁ - Z_T(z)W
+	// and the pairing check is e(F + zW', G₂).e(-W', [α]G₂) == 1
+	nbDigests := len(digests)
+	bases := make([]bls24315.G1Affine, nbDigests+3)
+	scalars := make([]fr.Element, nbDigests+3)
+	copy(bases, digests)
+
+	var gammaI, foldedEvals, ri, t fr.Element
+	gammaI.SetOne()
+	for i := range digests {
+		scalars[i] = evalVanishingPolynomial(points[:i], z)
+		t = evalVanishingPolynomial(points[i+1:], z)
+		scalars[i].Mul(&scalars[i], &t).Mul(&scalars[i], &gammaI)
+
+		coeffs, err := interpolate(points[i], proof.ClaimedValues[i])
+		if err != nil {
+			return err
+		}
+		ri = eval(coeffs, z)
+		t.Mul(&ri, &scalars[i])
+		foldedEvals.Add(&foldedEvals, &t)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+
+	bases[nbDigests] = proof.W
+	scalars[nbDigests] = evalVanishingPolynomial(points, z)
+	scalars[nbDigests].Neg(&scalars[nbDigests])
+	bases[nbDigests+1] = vk.G1
+	scalars[nbDigests+1].Neg(&foldedEvals)
+	bases[nbDigests+2] = proof.WPrime
+	scalars[nbDigests+2] = z
+
+	var lhs bls24315.G1Affine
+	if _, err := lhs.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	var negWPrime bls24315.G1Affine
+	negWPrime.Neg(&proof.WPrime)
+
+	check, err := bls24315.PairingCheckFixedQ(
+		[]bls24315.G1Affine{lhs, negWPrime},
+		vk.Lines[:],
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// checkMultiPointsSizes checks that there is one digest and one non empty set
+// of points per polynomial.
+func checkMultiPointsSizes(nbPolynomials int, digests []Digest, points [][]fr.Element) error {
+	if nbPolynomials != len(digests) {
+		return ErrInvalidNbDigests
+	}
+	if nbPolynomials == 0 {
+		return ErrZeroNbDigests
+	}
+	if nbPolynomials != len(points) {
+		return ErrInvalidNbPoints
+	}
+	for i := range points {
+		if len(points[i]) == 0 {
+			return ErrZeroNbPoints
+		}
+	}
+	return nil
+}
+
+// newMultiPointsTranscript returns the transcript of transcriptSettings if
+// set, and a new one for the challenges γ and z otherwise.
+func newMultiPointsTranscript(transcriptSettings fiatshamir.Settings) (*fiatshamir.Transcript, error) {
+	if transcriptSettings.Transcript != nil && transcriptSettings.Duplex != nil {
+		return nil, fiatshamir.ErrTranscriptAndDuplex
+	}
+	if transcriptSettings.Transcript != nil {
+		return transcriptSettings.Transcript, nil
+	}
+	return transcriptSettings.NewTranscript(transcriptSettings.Prefix+"gamma", transcriptSettings.Prefix+"z")
+}
+
+// deriveChallengeMultiPoints derives the challenge γ used to fold the
+// polynomials, binded to the digests, the points, the claimed values and the
+// base challenges of transcriptSettings.
+func deriveChallengeMultiPoints(fs *fiatshamir.Transcript, transcriptSettings fiatshamir.Settings, digests []Digest, points, claimedValues [][]fr.Element) (fr.Element, error) {
+	challengeName := transcriptSettings.Prefix + "gamma"
+	for i := range digests {
+		if err := fs.Bind(challengeName, digests[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range points {
+		for j := range points[i] {
+			if err := fs.Bind(challengeName, points[i][j].Marshal()); err != nil {
+				return fr.Element{}, err
+			}
+		}
+	}
+	for i := range claimedValues {
+		for j := range claimedValues[i] {
+			if err := fs.Bind(challengeName, claimedValues[i][j].Marshal()); err != nil {
+				return fr.Element{}, err
+			}
+		}
+	}
+	for i := 0; i < len(transcriptSettings.BaseChallenges); i++ {
+		if err := fs.Bind(challengeName, transcriptSettings.BaseChallenges[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	gammaByte, err := fs.ComputeChallenge(challengeName)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
+
+// deriveChallengeZ derives the evaluation challenge z, binded to W.
+func deriveChallengeZ(fs *fiatshamir.Transcript, prefix string, w *bls24315.G1Affine) (fr.Element, error) {
+	challengeName := prefix + "z"
+	if err := fs.Bind(challengeName, w.Marshal()); err != nil {
+		return fr.Element{}, err
+	}
+	zByte, err := fs.ComputeChallenge(challengeName)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var z fr.Element
+	z.SetBytes(zByte)
+
+	return z, nil
+}
+
+// pointsExcept returns the concatenation of all the sets of points but the i-th.
+func pointsExcept(points [][]fr.Element, i int) []fr.Element {
+	var res []fr.Element
+	for j := range points {
+		if j != i {
+			res = append(res, points[j]...)
+		}
+	}
+	return res
+}
+
+// vanishingPolynomial returns ∏ᵢ(X-xᵢ), in canonical basis
+func vanishingPolynomial(x []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(x)+1)
+	res[0].SetOne()
+	var t fr.Element
+	for i := range x {
+		// multiply res, of degree i, by (X-xᵢ)
+		for j := i + 1; j > 0; j-- {
+			t.Mul(&res[j], &x[i])
+			res[j].Sub(&res[j-1], &t)
+		}
+		res[0].Mul(&res[0], &x[i]).Neg(&res[0])
+	}
+	return res
+}
+
+// evalVanishingPolynomial returns ∏ᵢⱼ(z-points[i][j])
+func evalVanishingPolynomial(points [][]fr.Element, z fr.Element) fr.Element {
+	var res, t fr.Element
+	res.SetOne()
+	for i := range points {
+		for j := range points[i] {
+			t.Sub(&z, &points[i][j])
+			res.Mul(&res, &t)
+		}
+	}
+	return res
+}
+
+// interpolate returns the polynomial of degree < len(x) such that p(xᵢ) = yᵢ, in canonical basis.
+// The points x must be distinct.
+func interpolate(x, y []fr.Element) ([]fr.Element, error) {
+	z := vanishingPolynomial(x)
+	res := make([]fr.Element, len(x))
+	li := make([]fr.Element, len(z))
+	var d, t fr.Element
+	var zero fr.Element
+	for i := range x {
+		// lᵢ = Z/(X-xᵢ), and the i-th Lagrange polynomial is lᵢ/lᵢ(xᵢ)
+		copy(li, z)
+		l := dividePolyByXminusA(li, zero, x[i])
+		d = eval(l, x[i])
+		if d.IsZero() {
+			return nil, ErrDuplicatedPoints
+		}
+		d.Inverse(&d).Mul(&d, &y[i])
+		for j := range l {
+			t.Mul(&l[j], &d)
+			res[j].Add(&res[j], &t)
+		}
+	}
+	return res, nil
+}
+
+// subPolynomial sets a to a-b and returns it, a is resized if needed
+func subPolynomial(a, b []fr.Element) []fr.Element {
+	if len(b) > len(a) {
+		a = append(a, make([]fr.Element, len(b)-len(a))...)
+	}
+	for i := range b {
+		a[i].Sub(&a[i], &b[i])
+	}
+	return a
+}
+
+// mulPolynomials returns a*b, in canonical basis
+func mulPolynomials(a, b []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(a)+len(b)-1)
+	var t fr.Element
+	for i := range a {
+		for j := range b {
+			t.Mul(&a[i], &b[j])
+			res[i+j].Add(&res[i+j], &t)
+		}
+	}
+	return res
+}
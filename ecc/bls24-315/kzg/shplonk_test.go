@@ -0,0 +1,213 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenMultiPoints(t *testing.T) {
+	assert := require.New(t)
+
+	// polynomials of different sizes, opened at sets of points of different
+	// sizes, some of them shared between polynomials
+	sizes := []int{40, 12, 1, 25}
+	nbPoints := []int{3, 1, 2, 5}
+	var shared fr.Element
+	shared.SetRandom()
+
+	f := make([][]fr.Element, len(sizes))
+	digests := make([]Digest, len(sizes))
+	points := make([][]fr.Element, len(sizes))
+	for i := range sizes {
+		f[i] = randomPolynomial(sizes[i])
+		var err error
+		digests[i], err = Commit(f[i], testSrs.Pk)
+		assert.NoError(err)
+		points[i] = make([]fr.Element, nbPoints[i])
+		points[i][0] = shared
+		for j := 1; j < nbPoints[i]; j++ {
+			points[i][j].SetRandom()
+		}
+	}
+
+	hf := sha256.New()
+	proof, err := OpenMultiPoints(f, digests, points, hf, testSrs.Pk, []byte("data"))
+	assert.NoError(err)
+
+	// check the claimed values
+	for i := range f {
+		for j := range points[i] {
+			v := eval(f[i], points[i][j])
+			assert.True(v.Equal(&proof.ClaimedValues[i][j]), "wrong claimed value")
+		}
+	}
+
+	err = VerifyMultiPoints(digests, &proof, points, hf, testSrs.Vk, []byte("data"))
+	assert.NoError(err)
+
+	t.Run("serialization round-trip", utils.SerializationRoundTrip(&proof))
+
+	// wrong transcript
+	err = VerifyMultiPoints(digests, &proof, points, hf, testSrs.Vk, []byte("wrong data"))
+	assert.Error(err)
+
+	// wrong claimed value
+	proof.ClaimedValues[1][0].Double(&proof.ClaimedValues[1][0])
+	err = VerifyMultiPoints(digests, &proof, points, hf, testSrs.Vk, []byte("data"))
+	assert.Error(err)
+	proof.ClaimedValues[1][0].Halve()
+
+	// wrong digest
+	digests[0], digests[3] = digests[3], digests[0]
+	err = VerifyMultiPoints(digests, &proof, points, hf, testSrs.Vk, []byte("data"))
+	assert.Error(err)
+	digests[0], digests[3] = digests[3], digests[0]
+
+	// quotients set to infinity
+	proof.W.X.SetZero()
+	proof.W.Y.SetZero()
+	proof.WPrime.X.SetZero()
+	proof.WPrime.Y.SetZero()
+	err = VerifyMultiPoints(digests, &proof, points, hf, testSrs.Vk, []byte("data"))
+	assert.Error(err)
+}
+
+func TestOpenMultiPointsWithSettings(t *testing.T) {
+	assert := require.New(t)
+
+	f := [][]fr.Element{randomPolynomial(10), randomPolynomial(20)}
+	digests := make([]Digest, len(f))
+	points := make([][]fr.Element, len(f))
+	for i := range f {
+		digests[i], _ = Commit(f[i], testSrs.Pk)
+		points[i] = make([]fr.Element, i+1)
+		for j := range points[i] {
+			points[i][j].SetRandom()
+		}
+	}
+
+	// the challenges are derived from a transcript shared with the caller
+	newTranscript := func() *fiatshamir.Transcript {
+		fs := fiatshamir.NewTranscript(sha256.New(), "alpha", "shplonk.gamma", "shplonk.z")
+		if _, err := fs.ComputeChallenge("alpha"); err != nil {
+			t.Fatal(err)
+		}
+		return fs
+	}
+	proof, err := OpenMultiPointsWithSettings(f, digests, points, testSrs.Pk, fiatshamir.WithTranscript(newTranscript(), "shplonk."))
+	assert.NoError(err)
+	assert.NoError(VerifyMultiPointsWithSettings(digests, &proof, points, testSrs.Vk, fiatshamir.WithTranscript(newTranscript(), "shplonk.")))
+	assert.Error(VerifyMultiPointsWithSettings(digests, &proof, points, testSrs.Vk, fiatshamir.WithTranscript(newTranscript(), "shplonk.", []byte("data"))))
+
+	// the challenges are bound to the state of the duplex
+	newDuplex := func(absorbed string) *fiatshamir.Duplex {
+		d := fiatshamir.NewDuplex(sha256.New(), []byte("shplonk"))
+		d.Absorb([]byte(absorbed))
+		return d
+	}
+	proof, err = OpenMultiPointsWithSettings(f, digests, points, testSrs.Pk, fiatshamir.WithDuplex(newDuplex("a")))
+	assert.NoError(err)
+	assert.NoError(VerifyMultiPointsWithSettings(digests, &proof, points, testSrs.Vk, fiatshamir.WithDuplex(newDuplex("a"))))
+	assert.Error(VerifyMultiPointsWithSettings(digests, &proof, points, testSrs.Vk, fiatshamir.WithDuplex(newDuplex("b"))))
+
+	// a transcript and a duplex can't be used together
+	settings := fiatshamir.WithDuplex(newDuplex("a"))
+	settings.Transcript = newTranscript()
+	_, err = OpenMultiPointsWithSettings(f, digests, points, testSrs.Pk, settings)
+	assert.ErrorIs(err, fiatshamir.ErrTranscriptAndDuplex)
+}
+
+func TestOpenMultiPointsErrors(t *testing.T) {
+	assert := require.New(t)
+
+	f := [][]fr.Element{randomPolynomial(10), randomPolynomial(10)}
+	digests := make([]Digest, len(f))
+	for i := range f {
+		digests[i], _ = Commit(f[i], testSrs.Pk)
+	}
+	points := make([][]fr.Element, len(f))
+	for i := range points {
+		points[i] = make([]fr.Element, 2)
+		points[i][0].SetRandom()
+		points[i][1].SetRandom()
+	}
+	hf := sha256.New()
+
+	_, err := OpenMultiPoints(f, digests[:1], points, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbDigests)
+
+	_, err = OpenMultiPoints(f, digests, points[:1], hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbPoints)
+
+	_, err = OpenMultiPoints(f, digests, [][]fr.Element{points[0], {}}, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrZeroNbPoints)
+
+	points[1][1] = points[1][0]
+	_, err = OpenMultiPoints(f, digests, points, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrDuplicatedPoints)
+}
+
+func TestInterpolate(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 7
+	x := make([]fr.Element, size)
+	y := make([]fr.Element, size)
+	for i := range x {
+		x[i].SetRandom()
+		y[i].SetRandom()
+	}
+	p, err := interpolate(x, y)
+	assert.NoError(err)
+	assert.Equal(size, len(p))
+	for i := range x {
+		v := eval(p, x[i])
+		assert.True(v.Equal(&y[i]), "interpolation failed")
+	}
+}
+
+func BenchmarkOpenMultiPoints(b *testing.B) {
+	srs, err := NewSRS(ecc.NextPowerOfTwo(benchSize), new(big.Int).SetInt64(42))
+	require.NoError(b, err)
+
+	const nbPolynomials = 10
+	f := make([][]fr.Element, nbPolynomials)
+	digests := make([]Digest, nbPolynomials)
+	points := make([][]fr.Element, nbPolynomials)
+	for i := range f {
+		f[i] = randomPolynomial(benchSize / 2)
+		points[i] = make([]fr.Element, 2)
+		points[i][0].SetRandom()
+		points[i][1].SetRandom()
+		digests[i], _ = Commit(f[i], srs.Pk)
+	}
+	hf := sha256.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		OpenMultiPoints(f, digests, points, hf, srs.Pk)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24315
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+)
+
+func TestMultiExpPrecomputed(t *testing.T) {
+	t.Parallel()
+
+	const nbBases = 300
+	scalars := make([]fr.Element, nbBases)
+	fillBenchScalars(scalars)
+	bases := BatchScalarMultiplicationG1(&g1GenAff, scalars)
+	fillBenchScalars(scalars)
+	// check that the infinity point is handled
+	bases[5].X.SetZero()
+	bases[5].Y.SetZero()
+	// and the extreme scalars
+	scalars[0].SetZero()
+	scalars[1].SetOne().Neg(&scalars[1])
+
+	nbChunks := int(computeNbChunks(4))
+	for _, maxMemory := range []int{0, 3 * nbBases * SizeOfG1AffineUncompressed, nbChunks * nbBases * SizeOfG1AffineUncompressed} {
+		pb := NewPrecomputedBases(bases, maxMemory)
+		if pb.NbBases() != nbBases {
+			t.Fatal("wrong number of bases")
+		}
+		if maxMemory > 0 && len(pb.table)*SizeOfG1AffineUncompressed > maxMemory {
+			t.Fatal("memory budget exceeded")
+		}
+		for i := range bases {
+			if !pb.Base(i).Equal(&bases[i]) {
+				t.Fatal("wrong base")
+			}
+		}
+
+		for _, n := range []int{1, 17, nbBases} {
+			for _, nbTasks := range []int{1, 5, 0} {
+				config := ecc.MultiExpConfig{NbTasks: nbTasks}
+				var expected, res G1Affine
+				if _, err := expected.MultiExp(bases[:n], scalars[:n], config); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := res.MultiExpPrecomputed(pb, scalars[:n], config); err != nil {
+					t.Fatal(err)
+				}
+				if !res.Equal(&expected) {
+					t.Fatalf("precomputed MSM differs from MSM (memory: %d, points: %d, tasks: %d)", maxMemory, n, nbTasks)
+				}
+			}
+		}
+	}
+
+	// more scalars than bases
+	pb := NewPrecomputedBases(bases[:10], 0)
+	var res G1Jac
+	if _, err := res.MultiExpPrecomputed(pb, scalars[:11], ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("expected an error with more scalars than bases")
+	}
+}
+
+func TestPrecomputedBasesSerialization(t *testing.T) {
+	t.Parallel()
+
+	const nbBases = 20
+	scalars := make([]fr.Element, nbBases)
+	fillBenchScalars(scalars)
+	bases := BatchScalarMultiplicationG1(&g1GenAff, scalars)
+	pb := NewPrecomputedBases(bases, 4*nbBases*SizeOfG1AffineUncompressed)
+
+	for _, raw := range []bool{false, true} {
+		var buf bytes.Buffer
+		var enc *Encoder
+		if raw {
+			enc = NewEncoder(&buf, RawEncoding())
+		} else {
+			enc = NewEncoder(&buf)
+		}
+		if err := enc.Encode(pb); err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded PrecomputedBases
+		if err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode(&decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.c != pb.c || decoded.k != pb.k || decoded.q != pb.q || len(decoded.table) != len(pb.table) {
+			t.Fatal("serialization round trip failed")
+		}
+		for i := range pb.table {
+			if !decoded.table[i].Equal(&pb.table[i]) {
+				t.Fatal("serialization round trip failed")
+			}
+		}
+	}
+
+	// window sizes which are not implemented, and numbers of rounds or
+	// multiples which are not those of NewPrecomputedBases, are rejected
+	nbChunks := computeNbChunks(pb.c)
+	invalidParams := [][3]uint64{
+		{pb.c, 1 << 40, 1},      // huge number of rounds
+		{pb.c, 1 << 63, 2},      // k·q overflows
+		{pb.c, pb.k + 1, pb.q},  // too many rounds
+		{pb.c, 1, nbChunks + 1}, // too many multiples
+		{pb.c, pb.k, 0},         // no multiples
+	}
+	for _, c := range []uint64{0, 3, 6, 12, 17, 64} {
+		if !isPrecomputedC(c) {
+			invalidParams = append(invalidParams, [3]uint64{c, computeNbChunks(4), 1})
+		}
+	}
+	for _, params := range invalidParams {
+		invalid := *pb
+		invalid.c, invalid.k, invalid.q = params[0], params[1], params[2]
+		var buf bytes.Buffer
+		if _, err := invalid.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		var decoded PrecomputedBases
+		if _, err := decoded.ReadFrom(&buf); err == nil {
+			t.Fatalf("expected an error for parameters %v", params)
+		}
+	}
+}
+
+func BenchmarkMultiExpPrecomputed(b *testing.B) {
+	const nbBases = 1 << 16
+	scalars := make([]fr.Element, nbBases)
+	fillBenchScalars(scalars)
+	bases := BatchScalarMultiplicationG1(&g1GenAff, scalars)
+	fillBenchScalars(scalars)
+
+	var res G1Affine
+	b.Run("MultiExp", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			res.MultiExp(bases, scalars, ecc.MultiExpConfig{})
+		}
+	})
+
+	for _, factor := range []int{1, 4, 32} {
+		pb := NewPrecomputedBases(bases, factor*nbBases*SizeOfG1AffineUncompressed)
+		b.Run(fmt.Sprintf("precomputed/memory=%dx", factor), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				res.MultiExpPrecomputed(pb, scalars, ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
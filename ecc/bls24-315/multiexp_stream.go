@@ -0,0 +1,192 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24315
+
+import (
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+)
+
+// MultiExpChunkReader provides the points and scalars of a streaming
+// multi-exponentiation, chunk by chunk.
+//
+// ReadChunk fills points and scalars (of same length) with the next pairs and
+// returns the number of pairs read. Like io.Reader, it may return n > 0 along
+// with an error; it returns io.EOF once all the pairs have been read.
+// Implementations should not return 0 pairs with a nil error: after
+// maxConsecutiveEmptyReads such calls, MultiExpStream fails with
+// io.ErrNoProgress.
+type MultiExpChunkReader interface {
+	ReadChunk(points []G1Affine, scalars []fr.Element) (n int, err error)
+}
+
+// NewMultiExpReader returns a MultiExpChunkReader decoding points from points
+// and scalars from scalars.
+//
+// points is a sequence of G1 points, compressed or not, without length prefix,
+// as written by the Encoder; the decoding options (e.g. NoSubgroupChecks) are
+// those of NewDecoder. scalars is a sequence of big endian, fr.Bytes long,
+// canonical field elements.
+func NewMultiExpReader(points, scalars io.Reader, options ...func(*Decoder)) MultiExpChunkReader {
+	return &multiExpReader{
+		points:  NewDecoder(points, options...),
+		scalars: scalars,
+	}
+}
+
+// maxConsecutiveEmptyReads is the number of consecutive ReadChunk calls
+// returning no pairs and no error after which MultiExpStream gives up, as in
+// bufio.
+const maxConsecutiveEmptyReads = 100
+
+type multiExpReader struct {
+	points  *Decoder
+	scalars io.Reader
+	buf     []byte
+}
+
+func (r *multiExpReader) ReadChunk(points []G1Affine, scalars []fr.Element) (int, error) {
+	if len(points) != len(scalars) {
+		return 0, errors.New("len(points) != len(scalars)")
+	}
+	if cap(r.buf) < len(scalars)*fr.Bytes {
+		r.buf = make([]byte, len(scalars)*fr.Bytes)
+	}
+	buf := r.buf[:len(scalars)*fr.Bytes]
+
+	// read the scalars first, they determine the number of points to read
+	m, err := io.ReadFull(r.scalars, buf)
+	eof := false
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		if m%fr.Bytes != 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		eof = true
+	} else if err != nil {
+		return 0, err
+	}
+	n := m / fr.Bytes
+
+	for i := 0; i < n; i++ {
+		if err := scalars[i].SetBytesCanonical(buf[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			return 0, err
+		}
+		if err := r.points.Decode(&points[i]); err != nil {
+			if err == io.EOF {
+				return 0, errors.New("fewer points than scalars")
+			}
+			return 0, err
+		}
+	}
+
+	if eof {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// MultiExpStream computes the multi-exponentiation of the points and scalars
+// provided by r, reading at most chunkSize pairs at a time.
+//
+// Each chunk is processed with MultiExp and config, while the next chunk is
+// read; the memory usage is that of two chunks. The result is the same as a
+// MultiExp over all the points and scalars.
+func (p *G1Affine) MultiExpStream(r MultiExpChunkReader, chunkSize int, config ecc.MultiExpConfig) (*G1Affine, error) {
+	var _p G1Jac
+	if _, err := _p.MultiExpStream(r, chunkSize, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpStream computes the multi-exponentiation of the points and scalars
+// provided by r, reading at most chunkSize pairs at a time.
+//
+// Each chunk is processed with MultiExp and config, while the next chunk is
+// read; the memory usage is that of two chunks. The result is the same as a
+// MultiExp over all the points and scalars.
+func (p *G1Jac) MultiExpStream(r MultiExpChunkReader, chunkSize int, config ecc.MultiExpConfig) (*G1Jac, error) {
+	if chunkSize <= 0 {
+		return nil, errors.New("invalid chunk size")
+	}
+
+	// double buffering: we read a chunk while processing the previous one
+	type chunk struct {
+		points  []G1Affine
+		scalars []fr.Element
+	}
+	type readResult struct {
+		n   int
+		err error
+	}
+	var chunks [2]chunk
+	for i := range chunks {
+		chunks[i].points = make([]G1Affine, chunkSize)
+		chunks[i].scalars = make([]fr.Element, chunkSize)
+	}
+	read := func(c *chunk) <-chan readResult {
+		chRes := make(chan readResult, 1)
+		go func() {
+			n, err := r.ReadChunk(c.points, c.scalars)
+			chRes <- readResult{n, err}
+		}()
+		return chRes
+	}
+
+	var res, tmp G1Jac
+	res.Set(&g1Infinity)
+
+	current := 0
+	emptyReads := 0
+	chRead := read(&chunks[current])
+	for {
+		rr := <-chRead
+		if rr.err != nil && rr.err != io.EOF {
+			return nil, rr.err
+		}
+		if rr.n == 0 && rr.err == nil {
+			emptyReads++
+			if emptyReads >= maxConsecutiveEmptyReads {
+				return nil, io.ErrNoProgress
+			}
+		} else {
+			emptyReads = 0
+		}
+		if rr.err == nil {
+			chRead = read(&chunks[1-current])
+		}
+
+		if rr.n > 0 {
+			if _, err := tmp.MultiExp(chunks[current].points[:rr.n], chunks[current].scalars[:rr.n], config); err != nil {
+				return nil, err
+			}
+			res.AddAssign(&tmp)
+		}
+
+		if rr.err == io.EOF {
+			break
+		}
+		current = 1 - current
+	}
+
+	p.Set(&res)
+	return p, nil
+}
@@ -16,6 +16,11 @@
 
 // Package eddsa provides EdDSA signature scheme on bls24-315's twisted edwards curve.
 //
+// It also provides FROST threshold signing: t-of-n signers produce a
+// signature which verifies as a regular EdDSA signature for the group key.
+//
+// The keys can also be used for ECDH key agreement and ECIES encryption.
+//
 // # See also
 //
 // https://en.wikipedia.org/wiki/EdDSA
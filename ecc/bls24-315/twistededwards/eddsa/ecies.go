@@ -0,0 +1,169 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	errInvalidPublicKey = errors.New("invalid public key")
+	errInvalidMessage   = errors.New("invalid ciphertext")
+)
+
+const (
+	// eciesKeyLen is the size of the AES-128 key, and of the secret from which
+	// the HMAC-SHA256 key is derived.
+	eciesKeyLen   = 16
+	eciesOverhead = sizePublicKey + aes.BlockSize + sha256.Size
+)
+
+// ECDH returns the Diffie-Hellman shared secret of privKey and pub, that is
+// the compressed point scalar⋅pub.
+//
+// The secret scalar is a multiple of the cofactor (see GenerateKey), so the
+// small order component of pub, if any, is cleared. The secret is not
+// uniformly distributed and should not be used as a key directly (see
+// SharedKey).
+func (privKey *PrivateKey) ECDH(pub *PublicKey) ([]byte, error) {
+	if !pub.A.IsOnCurve() {
+		return nil, errInvalidPublicKey
+	}
+	var S twistededwards.PointAffine
+	S.ScalarMultiplicationConstantTime(&pub.A, new(big.Int).SetBytes(privKey.scalar[:]))
+	if S.IsZero() {
+		return nil, errInvalidPublicKey
+	}
+	s := S.Bytes()
+	return s[:], nil
+}
+
+// SharedKey returns a key of size bytes derived from the ECDH shared secret of
+// privKey and pub with HKDF-SHA256 (RFC 5869), with the optional salt and
+// context information info.
+func (privKey *PrivateKey) SharedKey(pub *PublicKey, salt, info []byte, size int) ([]byte, error) {
+	secret, err := privKey.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, size)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Encrypt encrypts message for pub with ECIES, and returns R||iv||c||d where
+//
+// R is a compressed ephemeral public key
+// ke||km' = ConcatKDF-SHA256(ECDH(r, pub), s1) and km = SHA256(km')
+// c = AES-128-CTR(ke, iv, message)
+// d = HMAC-SHA256(km, iv||c||s2)
+//
+// s1 and s2 are optional shared information, authenticated but not
+// encrypted. Apart from the encoding of R, this is the ECIES scheme of the
+// ecdsa packages.
+//
+// SEC 1, Version 2.0, Section 5.1
+func Encrypt(rand io.Reader, pub *PublicKey, message, s1, s2 []byte) ([]byte, error) {
+	ephemeral, err := GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	z, err := ephemeral.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	ke, km := eciesKeys(z, s1)
+
+	res := make([]byte, sizePublicKey+aes.BlockSize+len(message), eciesOverhead+len(message))
+	copy(res, ephemeral.PublicKey.Bytes())
+
+	iv := res[sizePublicKey : sizePublicKey+aes.BlockSize]
+	if _, err := io.ReadFull(rand, iv); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(ke)
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCTR(block, iv).XORKeyStream(res[sizePublicKey+aes.BlockSize:], message)
+
+	return append(res, eciesTag(km, res[sizePublicKey:], s2)...), nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt for the public key of
+// privKey, with the same shared information s1 and s2.
+func (privKey *PrivateKey) Decrypt(ciphertext, s1, s2 []byte) ([]byte, error) {
+	if len(ciphertext) < eciesOverhead {
+		return nil, errInvalidMessage
+	}
+	var R PublicKey
+	if _, err := R.SetBytes(ciphertext[:sizePublicKey]); err != nil {
+		return nil, errInvalidMessage
+	}
+	z, err := privKey.ECDH(&R)
+	if err != nil {
+		return nil, err
+	}
+	ke, km := eciesKeys(z, s1)
+
+	em := ciphertext[sizePublicKey : len(ciphertext)-sha256.Size]
+	if !hmac.Equal(ciphertext[len(ciphertext)-sha256.Size:], eciesTag(km, em, s2)) {
+		return nil, errInvalidMessage
+	}
+	block, err := aes.NewCipher(ke)
+	if err != nil {
+		return nil, err
+	}
+	message := make([]byte, len(em)-aes.BlockSize)
+	cipher.NewCTR(block, em[:aes.BlockSize]).XORKeyStream(message, em[aes.BlockSize:])
+	return message, nil
+}
+
+// eciesKeys derives the encryption and MAC keys from the shared secret z, with
+// the concatenation KDF of NIST SP 800-56A (one SHA-256 block).
+func eciesKeys(z, s1 []byte) (ke, km []byte) {
+	var counter [4]byte
+	binary.BigEndian.PutUint32(counter[:], 1)
+	h := sha256.New()
+	h.Write(counter[:])
+	h.Write(z)
+	h.Write(s1)
+	k := h.Sum(nil)
+
+	mk := sha256.Sum256(k[eciesKeyLen : 2*eciesKeyLen])
+	return k[:eciesKeyLen], mk[:]
+}
+
+// eciesTag returns HMAC-SHA256(km, em||s2).
+func eciesTag(km, em, s2 []byte) []byte {
+	mac := hmac.New(sha256.New, km)
+	mac.Write(em)
+	mac.Write(s2)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,31 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package ipa provides an inner product argument (IPA) polynomial
+// commitment scheme on bls24-315's twisted edwards curve, in the style of
+// Bulletproofs (BCCGP16, BBBPWM18) as used in Halo.
+//
+// The polynomials have their coefficients in the scalar field of the curve
+// (integers modulo the order of its prime subgroup), represented as big.Int.
+//
+// The setup is transparent: the bases of the Pedersen vector commitment are
+// hashed to the curve from a public seed. An opening proof is made of
+// 2log₂(n) points and two scalars, and is checked with a single multi-scalar
+// multiplication of size O(n). Several proofs can be checked at once with
+// BatchVerify.
+//
+// The commitments are not hiding.
+package ipa
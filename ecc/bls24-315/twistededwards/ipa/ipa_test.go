@@ -0,0 +1,275 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+)
+
+const srsSize = 16
+
+var testSeed = []byte("gnark-crypto ipa test seed")
+
+func randomScalar() big.Int {
+	curve := twistededwards.GetEdwardsCurve()
+	r, _ := rand.Int(rand.Reader, &curve.Order)
+	return *r
+}
+
+func randomPolynomial(size int) []big.Int {
+	p := make([]big.Int, size)
+	for i := range p {
+		p[i] = randomScalar()
+	}
+	return p
+}
+
+func eval(p []big.Int, point *big.Int) big.Int {
+	curve := twistededwards.GetEdwardsCurve()
+	var res big.Int
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, point).Add(&res, &p[i]).Mod(&res, &curve.Order)
+	}
+	return res
+}
+
+func TestNewSRS(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+	assert.Equal(srsSize, len(srs.G))
+
+	// the bases are distinct points of the prime order subgroup
+	curve := twistededwards.GetEdwardsCurve()
+	for i := range srs.G {
+		assert.True(srs.G[i].IsOnCurve())
+		var p twistededwards.PointAffine
+		p.ScalarMultiplication(&srs.G[i], &curve.Order)
+		assert.True(p.IsZero())
+		assert.False(srs.G[i].Equal(&srs.Q))
+		for j := i + 1; j < len(srs.G); j++ {
+			assert.False(srs.G[i].Equal(&srs.G[j]))
+		}
+	}
+
+	// the setup is deterministic
+	_srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+	assert.Equal(srs.G, _srs.G)
+	assert.True(srs.Q.Equal(&_srs.Q))
+
+	_, err = NewSRS(srsSize+1, testSeed)
+	assert.ErrorIs(err, ErrInvalidSRSSize)
+	_, err = NewSRS(0, testSeed)
+	assert.ErrorIs(err, ErrInvalidSRSSize)
+}
+
+func TestOpen(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+
+	hf := sha256.New()
+	for _, size := range []int{1, 5, srsSize} {
+		p := randomPolynomial(size)
+		digest, err := Commit(p, srs)
+		assert.NoError(err)
+
+		point := randomScalar()
+		proof, err := Open(p, digest, &point, hf, srs, []byte("data"))
+		assert.NoError(err)
+		expected := eval(p, &point)
+		assert.Equal(0, expected.Cmp(&proof.ClaimedValue))
+		assert.Equal(4, len(proof.L))
+
+		assert.NoError(Verify(&digest, &proof, &point, hf, srs, []byte("data")))
+
+		// wrong claimed value
+		{
+			_proof := proof
+			_proof.ClaimedValue = randomScalar()
+			assert.ErrorIs(Verify(&digest, &_proof, &point, hf, srs, []byte("data")), ErrVerifyOpeningProof)
+		}
+
+		// wrong folded polynomial
+		{
+			_proof := proof
+			_proof.A = randomScalar()
+			assert.ErrorIs(Verify(&digest, &_proof, &point, hf, srs, []byte("data")), ErrVerifyOpeningProof)
+		}
+
+		// wrong point
+		{
+			_point := randomScalar()
+			assert.Error(Verify(&digest, &proof, &_point, hf, srs, []byte("data")))
+		}
+
+		// wrong transcript
+		assert.Error(Verify(&digest, &proof, &point, hf, srs, []byte("other data")))
+
+		// wrong cross term
+		{
+			_proof := proof
+			_proof.L = make([]twistededwards.PointAffine, len(proof.L))
+			copy(_proof.L, proof.L)
+			_proof.L[2] = proof.R[2]
+			assert.Error(Verify(&digest, &_proof, &point, hf, srs, []byte("data")))
+		}
+
+		// cross term with a component of order 2
+		{
+			var t2 twistededwards.PointAffine
+			t2.Y.SetOne().Neg(&t2.Y)
+			_proof := proof
+			_proof.L = make([]twistededwards.PointAffine, len(proof.L))
+			copy(_proof.L, proof.L)
+			_proof.L[0].Add(&_proof.L[0], &t2)
+			assert.ErrorIs(Verify(&digest, &_proof, &point, hf, srs, []byte("data")), ErrVerifyOpeningProof)
+
+			_digest := digest
+			_digest.Add(&_digest, &t2)
+			assert.ErrorIs(Verify(&_digest, &proof, &point, hf, srs, []byte("data")), ErrVerifyOpeningProof)
+		}
+
+		// wrong number of rounds
+		{
+			_proof := proof
+			_proof.L = proof.L[1:]
+			assert.ErrorIs(Verify(&digest, &_proof, &point, hf, srs, []byte("data")), ErrInvalidNbRounds)
+		}
+	}
+
+	_, err = Commit(randomPolynomial(srsSize+1), srs)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestBatchVerify(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+
+	hf := sha256.New()
+	const nbProofs = 4
+	digests := make([]Digest, nbProofs)
+	proofs := make([]OpeningProof, nbProofs)
+	points := make([]big.Int, nbProofs)
+	for i := 0; i < nbProofs; i++ {
+		p := randomPolynomial(srsSize - i)
+		digests[i], err = Commit(p, srs)
+		assert.NoError(err)
+		points[i] = randomScalar()
+		proofs[i], err = Open(p, digests[i], &points[i], hf, srs)
+		assert.NoError(err)
+	}
+
+	assert.NoError(BatchVerify(digests, proofs, points, hf, srs))
+
+	// a single wrong proof makes the batch fail
+	proofs[2].ClaimedValue = randomScalar()
+	assert.ErrorIs(BatchVerify(digests, proofs, points, hf, srs), ErrVerifyOpeningProof)
+
+	assert.ErrorIs(BatchVerify(digests[1:], proofs, points, hf, srs), ErrInvalidNbProofs)
+}
+
+func TestSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(srsSize, testSeed)
+	assert.NoError(err)
+
+	p := randomPolynomial(srsSize)
+	digest, err := Commit(p, srs)
+	assert.NoError(err)
+	point := randomScalar()
+	proof, err := Open(p, digest, &point, sha256.New(), srs)
+	assert.NoError(err)
+
+	var _proof OpeningProof
+	buf := proof.Bytes()
+	n, err := _proof.SetBytes(buf)
+	assert.NoError(err)
+	assert.Equal(len(buf), n)
+	assert.Equal(proof.L, _proof.L)
+	assert.Equal(proof.R, _proof.R)
+	assert.Equal(0, proof.A.Cmp(&_proof.A))
+	assert.Equal(0, proof.ClaimedValue.Cmp(&_proof.ClaimedValue))
+	assert.NoError(Verify(&digest, &_proof, &point, sha256.New(), srs))
+
+	_, err = _proof.SetBytes(buf[1:])
+	assert.Error(err)
+
+	// a point with a component of order 2
+	var t2 twistededwards.PointAffine
+	t2.Y.SetOne().Neg(&t2.Y)
+	proof.R[1].Add(&proof.R[1], &t2)
+	_, err = _proof.SetBytes(proof.Bytes())
+	assert.ErrorIs(err, errNotInSubGroup)
+}
+
+func BenchmarkOpen(b *testing.B) {
+	const size = 1 << 8
+	srs, err := NewSRS(size, testSeed)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(size)
+	digest, err := Commit(p, srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	point := randomScalar()
+	hf := sha256.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Open(p, digest, &point, hf, srs)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	const size = 1 << 8
+	srs, err := NewSRS(size, testSeed)
+	if err != nil {
+		b.Fatal(err)
+	}
+	p := randomPolynomial(size)
+	digest, err := Commit(p, srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+	point := randomScalar()
+	hf := sha256.New()
+	proof, err := Open(p, digest, &point, hf, srs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(&digest, &proof, &point, hf, srs)
+	}
+}
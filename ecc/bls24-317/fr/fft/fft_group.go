@@ -0,0 +1,316 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fft
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
+)
+
+// groupElement is the set of operations on the points of an elliptic curve
+// group, in Jacobian coordinates, needed by the FFT over the group.
+type groupElement[T any] interface {
+	*T
+	Set(*T) *T
+	AddAssign(*T) *T
+	SubAssign(*T) *T
+	ScalarMultiplication(*T, *big.Int) *T
+}
+
+// FFTG1 computes the discrete Fourier transform of the G1 points a and stores
+// the result in a, that is aᵢ = ∑ⱼ ωⁱʲaⱼ (or ∑ⱼ (gωⁱ)ʲaⱼ on the coset g⋅<ω>).
+// The decimation and the options have the same meaning as in FFT.
+//
+// Each butterfly costs a scalar multiplication: the FFT costs about
+// (n/2)⋅log(n) scalar multiplications.
+func (domain *Domain) FFTG1(a []curve.G1Jac, decimation Decimation, opts ...Option) {
+	fftGroup(domain, a, decimation, false, opts...)
+}
+
+// FFTInverseG1 computes the inverse discrete Fourier transform of the G1
+// points a and stores the result in a.
+// The decimation and the options have the same meaning as in FFTInverse.
+func (domain *Domain) FFTInverseG1(a []curve.G1Jac, decimation Decimation, opts ...Option) {
+	fftGroup(domain, a, decimation, true, opts...)
+}
+
+// FFTG2 computes the discrete Fourier transform of the G2 points a and stores
+// the result in a, see FFTG1.
+func (domain *Domain) FFTG2(a []curve.G2Jac, decimation Decimation, opts ...Option) {
+	fftGroup(domain, a, decimation, false, opts...)
+}
+
+// FFTInverseG2 computes the inverse discrete Fourier transform of the G2
+// points a and stores the result in a, see FFTInverseG1.
+func (domain *Domain) FFTInverseG2(a []curve.G2Jac, decimation Decimation, opts ...Option) {
+	fftGroup(domain, a, decimation, true, opts...)
+}
+
+// FFTG1Affine is FFTG1 on affine points.
+func (domain *Domain) FFTG1Affine(a []curve.G1Affine, decimation Decimation, opts ...Option) {
+	aJac := g1ToJacobian(a)
+	domain.FFTG1(aJac, decimation, opts...)
+	copy(a, curve.BatchJacobianToAffineG1(aJac))
+}
+
+// FFTInverseG1Affine is FFTInverseG1 on affine points.
+func (domain *Domain) FFTInverseG1Affine(a []curve.G1Affine, decimation Decimation, opts ...Option) {
+	aJac := g1ToJacobian(a)
+	domain.FFTInverseG1(aJac, decimation, opts...)
+	copy(a, curve.BatchJacobianToAffineG1(aJac))
+}
+
+// FFTG2Affine is FFTG2 on affine points.
+func (domain *Domain) FFTG2Affine(a []curve.G2Affine, decimation Decimation, opts ...Option) {
+	aJac := g2ToJacobian(a)
+	domain.FFTG2(aJac, decimation, opts...)
+	g2ToAffine(a, aJac)
+}
+
+// FFTInverseG2Affine is FFTInverseG2 on affine points.
+func (domain *Domain) FFTInverseG2Affine(a []curve.G2Affine, decimation Decimation, opts ...Option) {
+	aJac := g2ToJacobian(a)
+	domain.FFTInverseG2(aJac, decimation, opts...)
+	g2ToAffine(a, aJac)
+}
+
+func fftGroup[T any, PT groupElement[T]](domain *Domain, a []T, decimation Decimation, inverse bool, opts ...Option) {
+	opt := fftOptions(opts...)
+
+	// find the stage where we should stop spawning go routines in our recursive calls
+	// (ie when we have as many go routines running as we have available CPUs)
+	maxSplits := bits.TrailingZeros64(ecc.NextPowerOfTwo(uint64(opt.nbTasks)))
+	if opt.nbTasks == 1 {
+		maxSplits = -1
+	}
+
+	// the coset shift is applied to the input of the FFT, and to the output of
+	// the inverse FFT, together with the division by the cardinality
+	var shift []fr.Element
+	if opt.coset {
+		shift = make([]fr.Element, len(a))
+		if inverse {
+			BuildExpTable(domain.FrMultiplicativeGenInv, shift)
+		} else {
+			BuildExpTable(domain.FrMultiplicativeGen, shift)
+		}
+	}
+	var cardinalityInv *fr.Element
+	if inverse {
+		cardinalityInv = &domain.CardinalityInv
+	}
+
+	// the input of DIT and the output of DIF are in bit-reversed order
+	if !inverse && shift != nil {
+		scaleGroup[T, PT](a, shift, nil, decimation == DIT, opt.nbTasks)
+	}
+
+	twiddles := domain.groupTwiddles(inverse)
+	switch decimation {
+	case DIF:
+		difFFTGroup[T, PT](a, twiddles, 0, maxSplits, nil, opt.nbTasks)
+	case DIT:
+		ditFFTGroup[T, PT](a, twiddles, 0, maxSplits, nil, opt.nbTasks)
+	default:
+		panic("not implemented")
+	}
+
+	if inverse {
+		scaleGroup[T, PT](a, shift, cardinalityInv, decimation == DIF, opt.nbTasks)
+	}
+}
+
+// groupTwiddles returns the twiddles of the domain (of the inverse FFT if
+// inverse is set), computing them if the domain doesn't store them.
+func (domain *Domain) groupTwiddles(inverse bool) [][]fr.Element {
+	if domain.withPrecompute {
+		if inverse {
+			return domain.twiddlesInv
+		}
+		return domain.twiddles
+	}
+	nbStages := uint64(bits.TrailingZeros64(domain.Cardinality))
+	twiddles := make([][]fr.Element, nbStages)
+	if inverse {
+		buildTwiddles(twiddles, domain.GeneratorInv, nbStages)
+	} else {
+		buildTwiddles(twiddles, domain.Generator, nbStages)
+	}
+	return twiddles
+}
+
+// scaleGroup sets aᵢ = [sᵢ⋅c]aᵢ, where s or c may be nil (equal to 1), and i
+// is bit-reversed if bitReversed is set.
+func scaleGroup[T any, PT groupElement[T]](a []T, s []fr.Element, c *fr.Element, bitReversed bool, nbTasks int) {
+	if s == nil && c == nil {
+		return
+	}
+	parallel.Execute(len(a), func(start, end int) {
+		n := uint64(len(a))
+		nn := uint64(64 - bits.TrailingZeros64(n))
+		var e fr.Element
+		var b big.Int
+		for i := start; i < end; i++ {
+			e.SetOne()
+			if s != nil {
+				j := i
+				if bitReversed {
+					j = int(bits.Reverse64(uint64(i)) >> nn)
+				}
+				e.Set(&s[j])
+			}
+			if c != nil {
+				e.Mul(&e, c)
+			}
+			PT(&a[i]).ScalarMultiplication(&a[i], e.BigInt(&b))
+		}
+	}, nbTasks)
+}
+
+func difFFTGroup[T any, PT groupElement[T]](a []T, twiddles [][]fr.Element, stage, maxSplits int, chDone chan struct{}, nbTasks int) {
+	if chDone != nil {
+		defer close(chDone)
+	}
+
+	n := len(a)
+	if n == 1 {
+		return
+	}
+	m := n >> 1
+
+	if stage < maxSplits {
+		parallel.Execute(m, func(start, end int) {
+			innerDIFGroup[T, PT](a, twiddles[stage], start, end, m)
+		}, nbTasks/(1<<(stage))) // 1 << stage == estimated used CPUs
+	} else {
+		innerDIFGroup[T, PT](a, twiddles[stage], 0, m, m)
+	}
+
+	if m == 1 {
+		return
+	}
+
+	nextStage := stage + 1
+	if stage < maxSplits {
+		chDone := make(chan struct{}, 1)
+		go difFFTGroup[T, PT](a[m:n], twiddles, nextStage, maxSplits, chDone, nbTasks)
+		difFFTGroup[T, PT](a[0:m], twiddles, nextStage, maxSplits, nil, nbTasks)
+		<-chDone
+	} else {
+		difFFTGroup[T, PT](a[0:m], twiddles, nextStage, maxSplits, nil, nbTasks)
+		difFFTGroup[T, PT](a[m:n], twiddles, nextStage, maxSplits, nil, nbTasks)
+	}
+}
+
+func ditFFTGroup[T any, PT groupElement[T]](a []T, twiddles [][]fr.Element, stage, maxSplits int, chDone chan struct{}, nbTasks int) {
+	if chDone != nil {
+		defer close(chDone)
+	}
+
+	n := len(a)
+	if n == 1 {
+		return
+	}
+	m := n >> 1
+
+	nextStage := stage + 1
+	if stage < maxSplits {
+		chDone := make(chan struct{}, 1)
+		go ditFFTGroup[T, PT](a[m:], twiddles, nextStage, maxSplits, chDone, nbTasks)
+		ditFFTGroup[T, PT](a[0:m], twiddles, nextStage, maxSplits, nil, nbTasks)
+		<-chDone
+	} else {
+		ditFFTGroup[T, PT](a[0:m], twiddles, nextStage, maxSplits, nil, nbTasks)
+		ditFFTGroup[T, PT](a[m:n], twiddles, nextStage, maxSplits, nil, nbTasks)
+	}
+
+	if stage < maxSplits {
+		parallel.Execute(m, func(start, end int) {
+			innerDITGroup[T, PT](a, twiddles[stage], start, end, m)
+		}, nbTasks/(1<<(stage)))
+	} else {
+		innerDITGroup[T, PT](a, twiddles[stage], 0, m, m)
+	}
+}
+
+func innerDIFGroup[T any, PT groupElement[T]](a []T, twiddles []fr.Element, start, end, m int) {
+	var b big.Int
+	if start == 0 {
+		butterflyGroup[T, PT](&a[0], &a[m])
+		start++
+	}
+	for i := start; i < end; i++ {
+		butterflyGroup[T, PT](&a[i], &a[i+m])
+		PT(&a[i+m]).ScalarMultiplication(&a[i+m], twiddles[i].BigInt(&b))
+	}
+}
+
+func innerDITGroup[T any, PT groupElement[T]](a []T, twiddles []fr.Element, start, end, m int) {
+	var b big.Int
+	if start == 0 {
+		butterflyGroup[T, PT](&a[0], &a[m])
+		start++
+	}
+	for i := start; i < end; i++ {
+		PT(&a[i+m]).ScalarMultiplication(&a[i+m], twiddles[i].BigInt(&b))
+		butterflyGroup[T, PT](&a[i], &a[i+m])
+	}
+}
+
+// butterflyGroup computes (a, b) = (a + b, a - b).
+func butterflyGroup[T any, PT groupElement[T]](a, b *T) {
+	var t T
+	PT(&t).Set(a)
+	PT(a).AddAssign(b)
+	PT(&t).SubAssign(b)
+	PT(b).Set(&t)
+}
+
+func g1ToJacobian(a []curve.G1Affine) []curve.G1Jac {
+	res := make([]curve.G1Jac, len(a))
+	parallel.Execute(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].FromAffine(&a[i])
+		}
+	})
+	return res
+}
+
+func g2ToJacobian(a []curve.G2Affine) []curve.G2Jac {
+	res := make([]curve.G2Jac, len(a))
+	parallel.Execute(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].FromAffine(&a[i])
+		}
+	})
+	return res
+}
+
+func g2ToAffine(res []curve.G2Affine, a []curve.G2Jac) {
+	parallel.Execute(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].FromJacobian(&a[i])
+		}
+	})
+}
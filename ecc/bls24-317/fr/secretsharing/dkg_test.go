@@ -0,0 +1,231 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package secretsharing
+
+import (
+	"math/big"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dkgAdversary tampers with the messages of the participants.
+type dkgAdversary struct {
+	shares     func(from uint64, shares []DealShare) []DealShare
+	responses  func(from uint64, responses []DealShare) []DealShare
+	extraction func(from uint64, e Extraction) Extraction
+}
+
+// runDKG runs the distributed key generation between n participants, routing
+// the messages as an authenticated broadcast channel and private channels.
+func runDKG(t testing.TB, threshold, n int, adv dkgAdversary) []KeyShare {
+	participants := make([]*DKG, n)
+	for i := range participants {
+		var err error
+		participants[i], err = NewDKG(uint64(i+1), threshold, n)
+		require.NoError(t, err)
+	}
+
+	// round 1
+	var deals []Deal
+	private := make(map[uint64][]DealShare)
+	for _, p := range participants {
+		deal, shares, err := p.Deal()
+		require.NoError(t, err)
+		if adv.shares != nil {
+			shares = adv.shares(p.id, shares)
+		}
+		deals = append(deals, deal)
+		for _, s := range shares {
+			private[s.To] = append(private[s.To], s)
+		}
+	}
+
+	// round 2
+	var complaints []Complaint
+	for _, p := range participants {
+		c, err := p.ProcessDeals(deals, private[p.id])
+		require.NoError(t, err)
+		complaints = append(complaints, c...)
+	}
+
+	// round 3
+	var responses []DealShare
+	for _, p := range participants {
+		r, err := p.Respond(complaints)
+		require.NoError(t, err)
+		if adv.responses != nil {
+			r = adv.responses(p.id, r)
+		}
+		responses = append(responses, r...)
+	}
+
+	// round 4
+	var extractions []Extraction
+	for _, p := range participants {
+		e, err := p.ProcessResponses(complaints, responses)
+		require.NoError(t, err)
+		if adv.extraction != nil {
+			e = adv.extraction(p.id, e)
+		}
+		if e.From != 0 {
+			extractions = append(extractions, e)
+		}
+	}
+
+	// round 5
+	var extractionComplaints []ExtractionComplaint
+	for _, p := range participants {
+		c, err := p.ProcessExtractions(extractions)
+		require.NoError(t, err)
+		extractionComplaints = append(extractionComplaints, c...)
+	}
+
+	// round 6
+	var reconstruction []DealShare
+	for _, p := range participants {
+		r, err := p.Reconstruct(extractionComplaints)
+		require.NoError(t, err)
+		reconstruction = append(reconstruction, r...)
+	}
+
+	// round 7
+	keyShares := make([]KeyShare, n)
+	for i, p := range participants {
+		var err error
+		keyShares[i], err = p.Finalize(reconstruction)
+		require.NoError(t, err)
+	}
+	return keyShares
+}
+
+// checkKeyShares checks that the key shares are consistent, and that they
+// share the secret key of the group key.
+func checkKeyShares(t *testing.T, threshold int, keyShares []KeyShare) {
+	for i := range keyShares {
+		assert.True(t, keyShares[i].GroupKey.Equal(&keyShares[0].GroupKey))
+		assert.Equal(t, keyShares[0].Qualified, keyShares[i].Qualified)
+		require.Len(t, keyShares[i].Commitment, threshold)
+		for k := range keyShares[i].Commitment {
+			assert.True(t, keyShares[i].Commitment[k].Equal(&keyShares[0].Commitment[k]))
+		}
+		assert.NoError(t, keyShares[0].Commitment.Verify(&keyShares[i].Share))
+	}
+
+	shares := make([]Share, len(keyShares))
+	for i := range keyShares {
+		shares[len(shares)-1-i] = keyShares[i].Share
+	}
+	secret, err := Reconstruct(shares, threshold)
+	require.NoError(t, err)
+	var groupKey curve.G1Affine
+	var b big.Int
+	groupKey.ScalarMultiplicationBase(secret.BigInt(&b))
+	assert.True(t, groupKey.Equal(&keyShares[0].GroupKey))
+}
+
+func TestDKG(t *testing.T) {
+	const threshold, n = 3, 5
+
+	t.Run("honest", func(t *testing.T) {
+		keyShares := runDKG(t, threshold, n, dkgAdversary{})
+		checkKeyShares(t, threshold, keyShares)
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5}, keyShares[0].Qualified)
+	})
+
+	t.Run("invalid share answered", func(t *testing.T) {
+		// participant 2 sends a wrong share to participant 4, and reveals the
+		// right one when 4 complains
+		keyShares := runDKG(t, threshold, n, dkgAdversary{
+			shares: func(from uint64, shares []DealShare) []DealShare {
+				if from == 2 {
+					shares[2].Share.Value.SetOne()
+				}
+				return shares
+			},
+		})
+		checkKeyShares(t, threshold, keyShares)
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5}, keyShares[0].Qualified)
+	})
+
+	t.Run("complaint not answered", func(t *testing.T) {
+		// participant 3 doesn't send its share to 1, and doesn't answer the
+		// complaint: it is disqualified
+		keyShares := runDKG(t, threshold, n, dkgAdversary{
+			shares: func(from uint64, shares []DealShare) []DealShare {
+				if from == 3 {
+					return shares[1:]
+				}
+				return shares
+			},
+			responses: func(from uint64, responses []DealShare) []DealShare {
+				if from == 3 {
+					return nil
+				}
+				return responses
+			},
+		})
+		checkKeyShares(t, threshold, keyShares)
+		assert.Equal(t, []uint64{1, 2, 4, 5}, keyShares[0].Qualified)
+	})
+
+	t.Run("invalid extraction", func(t *testing.T) {
+		// participant 5 broadcasts a wrong Feldman commitment: its public
+		// polynomial is reconstructed from the shares
+		keyShares := runDKG(t, threshold, n, dkgAdversary{
+			extraction: func(from uint64, e Extraction) Extraction {
+				if from == 5 {
+					bad := make(FeldmanCommitment, len(e.Commitment))
+					copy(bad, e.Commitment)
+					bad[0].Double(&bad[0])
+					e.Commitment = bad
+				}
+				return e
+			},
+		})
+		checkKeyShares(t, threshold, keyShares)
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5}, keyShares[0].Qualified)
+	})
+
+	t.Run("rounds out of order", func(t *testing.T) {
+		p, err := NewDKG(1, threshold, n)
+		require.NoError(t, err)
+		_, err = p.ProcessDeals(nil, nil)
+		assert.ErrorIs(t, err, ErrInvalidRound)
+		_, _, err = p.Deal()
+		require.NoError(t, err)
+		_, _, err = p.Deal()
+		assert.ErrorIs(t, err, ErrInvalidRound)
+		_, err = p.Finalize(nil)
+		assert.ErrorIs(t, err, ErrInvalidRound)
+
+		_, err = NewDKG(0, threshold, n)
+		assert.ErrorIs(t, err, ErrInvalidID)
+		_, err = NewDKG(1, n+1, n)
+		assert.ErrorIs(t, err, ErrInvalidThreshold)
+	})
+}
+
+func BenchmarkDKG(b *testing.B) {
+	const threshold, n = 3, 5
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		runDKG(b, threshold, n, dkgAdversary{})
+	}
+}
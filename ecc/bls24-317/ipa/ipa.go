@@ -0,0 +1,458 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls24317 "github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidSRSSize        = errors.New("the size of the SRS must be a power of two")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (larger than SRS or == 0)")
+	ErrInvalidNbRounds       = errors.New("the number of rounds of the proof does not match the size of the SRS")
+	ErrInvalidNbProofs       = errors.New("the numbers of digests, proofs and points differ")
+	ErrZeroChallenge         = errors.New("a challenge of the transcript is zero")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+)
+
+// domain separation tags of the hash to curve of the bases
+const (
+	dstG = "IPA-BLS24-317-G"
+	dstQ = "IPA-BLS24-317-Q"
+)
+
+// Digest commitment of a polynomial.
+type Digest = bls24317.G1Affine
+
+// SRS public parameters of the scheme. They are derived from a public seed, no
+// trusted setup is needed.
+//
+// implements io.ReaderFrom and io.WriterTo
+type SRS struct {
+	// G bases of the Pedersen vector commitment
+	G []bls24317.G1Affine
+
+	// Q base to which the inner product is bound
+	Q bls24317.G1Affine
+}
+
+// OpeningProof IPA proof of the evaluation of a polynomial at a point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type OpeningProof struct {
+	// L, R cross terms of the successive rounds
+	L, R []bls24317.G1Affine
+
+	// A the polynomial, folded to a single coefficient
+	A fr.Element
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+}
+
+// NewSRS returns public parameters to commit to polynomials of degree < size.
+// size must be a power of two. The bases are the hashes to G₁ of seed||i.
+func NewSRS(size uint64, seed []byte) (*SRS, error) {
+	if size == 0 || bits.OnesCount64(size) != 1 {
+		return nil, ErrInvalidSRSSize
+	}
+
+	var srs SRS
+	var err error
+	if srs.Q, err = bls24317.HashToG1(seed, []byte(dstQ)); err != nil {
+		return nil, err
+	}
+
+	srs.G = make([]bls24317.G1Affine, size)
+	var lock sync.Mutex
+	parallel.Execute(int(size), func(start, end int) {
+		msg := make([]byte, len(seed)+8)
+		copy(msg, seed)
+		for i := start; i < end; i++ {
+			binary.BigEndian.PutUint64(msg[len(seed):], uint64(i))
+			g, errG := bls24317.HashToG1(msg, []byte(dstG))
+			if errG != nil {
+				lock.Lock()
+				err = errG
+				lock.Unlock()
+				return
+			}
+			srs.G[i] = g
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &srs, nil
+}
+
+// Commit commits to a polynomial p, given by its coefficients, as ∑ᵢpᵢGᵢ.
+func Commit(p []fr.Element, srs *SRS, nbTasks ...int) (Digest, error) {
+	if len(p) == 0 || len(p) > len(srs.G) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+
+	var res Digest
+	if _, err := res.MultiExp(srs.G[:len(p)], p, config); err != nil {
+		return Digest{}, err
+	}
+	return res, nil
+}
+
+// Open computes an opening proof of the polynomial p at a given point.
+// It's an interactive protocol, made non-interactive using Fiat Shamir.
+//
+// * digest is the commitment to p, needed to derive the challenges
+// * dataTranscript extra data that might be needed to derive the challenges
+//
+// With a = p, b = (1, point, point², …) and G = srs.G, each round halves the
+// vectors: a ← a_L + xa_R, b ← b_L + x⁻¹b_R and G ← G_L + x⁻¹G_R, where x is
+// derived from the cross terms L = ⟨a_R, G_L⟩ + ⟨a_R, b_L⟩Q' and
+// R = ⟨a_L, G_R⟩ + ⟨a_L, b_R⟩Q', with Q' = wQ.
+func Open(p []fr.Element, digest Digest, point fr.Element, hf hash.Hash, srs *SRS, dataTranscript ...[]byte) (OpeningProof, error) {
+
+	var res OpeningProof
+	n := len(srs.G)
+	if len(p) == 0 || len(p) > n {
+		return res, ErrInvalidPolynomialSize
+	}
+	nbRounds := bits.TrailingZeros(uint(n))
+
+	a := make([]fr.Element, n)
+	copy(a, p)
+	b := make([]fr.Element, n)
+	b[0].SetOne()
+	for i := 1; i < n; i++ {
+		b[i].Mul(&b[i-1], &point)
+	}
+	res.ClaimedValue = innerProduct(a, b)
+
+	// Q' = wQ
+	fs := fiatshamir.NewTranscript(hf, challengeNames(nbRounds)...)
+	w, err := deriveChallengeW(fs, &digest, point, res.ClaimedValue, dataTranscript...)
+	if err != nil {
+		return res, err
+	}
+	var q bls24317.G1Affine
+	var bw big.Int
+	q.ScalarMultiplication(&srs.Q, w.BigInt(&bw))
+
+	g := make([]bls24317.G1Affine, n)
+	copy(g, srs.G)
+	res.L = make([]bls24317.G1Affine, nbRounds)
+	res.R = make([]bls24317.G1Affine, nbRounds)
+	var x, xInv, t fr.Element
+	for round := 0; round < nbRounds; round++ {
+		m := len(a) / 2
+		aL, aR := a[:m], a[m:]
+		bL, bR := b[:m], b[m:]
+		gL, gR := g[:m], g[m:]
+
+		if res.L[round], err = crossTerm(gL, aR, bL, &q); err != nil {
+			return res, err
+		}
+		if res.R[round], err = crossTerm(gR, aL, bR, &q); err != nil {
+			return res, err
+		}
+		if x, err = deriveChallengeX(fs, round, &res.L[round], &res.R[round]); err != nil {
+			return res, err
+		}
+		xInv.Inverse(&x)
+
+		for i := 0; i < m; i++ {
+			t.Mul(&aR[i], &x)
+			aL[i].Add(&aL[i], &t)
+			t.Mul(&bR[i], &xInv)
+			bL[i].Add(&bL[i], &t)
+		}
+		g = foldBases(gL, gR, &xInv)
+		a, b = aL, bL
+	}
+	res.A = a[0]
+
+	return res, nil
+}
+
+// Verify verifies an opening proof of the polynomial committed in digest at
+// point.
+//
+// * dataTranscript extra data that might be needed to derive the challenges
+func Verify(digest *Digest, proof *OpeningProof, point fr.Element, hf hash.Hash, srs *SRS, dataTranscript ...[]byte) error {
+	var one fr.Element
+	one.SetOne()
+	v := newVerifier(srs, 1)
+	if err := v.addProof(digest, proof, point, one, hf, dataTranscript...); err != nil {
+		return err
+	}
+	return v.check()
+}
+
+// BatchVerify verifies the opening proofs proofs[i] of the polynomials
+// committed in digests[i] at points[i], with a single multi-scalar
+// multiplication, by checking a random linear combination of the verification
+// equations.
+//
+// * dataTranscript extra data that might be needed to derive the challenges, shared by all the proofs
+func BatchVerify(digests []Digest, proofs []OpeningProof, points []fr.Element, hf hash.Hash, srs *SRS, dataTranscript ...[]byte) error {
+	if len(digests) != len(proofs) || len(digests) != len(points) {
+		return ErrInvalidNbProofs
+	}
+	if len(digests) == 0 {
+		return nil
+	}
+
+	v := newVerifier(srs, len(proofs))
+	var r fr.Element
+	r.SetOne()
+	for i := range proofs {
+		if err := v.addProof(&digests[i], &proofs[i], points[i], r, hf, dataTranscript...); err != nil {
+			return err
+		}
+		if _, err := r.SetRandom(); err != nil {
+			return err
+		}
+	}
+	return v.check()
+}
+
+// verifier accumulates the verification equations
+//
+//	r(C + vQ' + ∑ᵢ(xᵢLᵢ + xᵢ⁻¹Rᵢ) - a⟨s, G⟩ - a⟨s, b⟩Q') = 0
+//
+// where sⱼ = ∏ᵢxᵢ^{-bitᵢ(j)}, so that they are checked with a single
+// multi-scalar multiplication.
+type verifier struct {
+	srs *SRS
+
+	// scalars by which srs.G and srs.Q are multiplied
+	gScalars []fr.Element
+	qScalar  fr.Element
+
+	// the other terms
+	bases   []bls24317.G1Affine
+	scalars []fr.Element
+}
+
+func newVerifier(srs *SRS, nbProofs int) *verifier {
+	nbRounds := bits.TrailingZeros(uint(len(srs.G)))
+	return &verifier{
+		srs:      srs,
+		gScalars: make([]fr.Element, len(srs.G)),
+		bases:    make([]bls24317.G1Affine, 0, nbProofs*(2*nbRounds+1)),
+		scalars:  make([]fr.Element, 0, nbProofs*(2*nbRounds+1)),
+	}
+}
+
+// addProof adds the verification equation of proof, multiplied by r.
+func (v *verifier) addProof(digest *Digest, proof *OpeningProof, point, r fr.Element, hf hash.Hash, dataTranscript ...[]byte) error {
+	n := len(v.srs.G)
+	nbRounds := bits.TrailingZeros(uint(n))
+	if len(proof.L) != nbRounds || len(proof.R) != nbRounds {
+		return ErrInvalidNbRounds
+	}
+
+	// derive the challenges
+	fs := fiatshamir.NewTranscript(hf, challengeNames(nbRounds)...)
+	w, err := deriveChallengeW(fs, digest, point, proof.ClaimedValue, dataTranscript...)
+	if err != nil {
+		return err
+	}
+	x := make([]fr.Element, nbRounds)
+	for i := range x {
+		if x[i], err = deriveChallengeX(fs, i, &proof.L[i], &proof.R[i]); err != nil {
+			return err
+		}
+	}
+	xInv := fr.BatchInvert(x)
+
+	// r(C + ∑ᵢ(xᵢLᵢ + xᵢ⁻¹Rᵢ))
+	var t fr.Element
+	v.bases = append(v.bases, *digest)
+	v.scalars = append(v.scalars, r)
+	for i := range x {
+		v.bases = append(v.bases, proof.L[i], proof.R[i])
+		v.scalars = append(v.scalars, *t.Mul(&r, &x[i]), *new(fr.Element).Mul(&r, &xInv[i]))
+	}
+
+	// s, in the order of the indices of G: the i-th round splits the indices
+	// according to their (nbRounds-1-i)-th bit
+	s := make([]fr.Element, n)
+	s[0].SetOne()
+	for i := range xInv {
+		for j := (1 << i) - 1; j >= 0; j-- {
+			s[2*j+1].Mul(&s[j], &xInv[i])
+			s[2*j] = s[j]
+		}
+	}
+
+	// ⟨s, b⟩ = ∏ᵢ(1 + xᵢ⁻¹point^{2^{nbRounds-1-i}})
+	var b, one fr.Element
+	one.SetOne()
+	b.SetOne()
+	pointPow := point
+	for i := nbRounds - 1; i >= 0; i-- {
+		t.Mul(&xInv[i], &pointPow).Add(&t, &one)
+		b.Mul(&b, &t)
+		pointPow.Square(&pointPow)
+	}
+
+	// -ra s
+	var ra fr.Element
+	ra.Mul(&r, &proof.A)
+	for j := range s {
+		t.Mul(&s[j], &ra)
+		v.gScalars[j].Sub(&v.gScalars[j], &t)
+	}
+
+	// rw(v - ab)
+	t.Mul(&proof.A, &b)
+	t.Sub(&proof.ClaimedValue, &t).Mul(&t, &w).Mul(&t, &r)
+	v.qScalar.Add(&v.qScalar, &t)
+
+	return nil
+}
+
+// check checks that the sum of the verification equations is zero.
+func (v *verifier) check() error {
+	bases := make([]bls24317.G1Affine, 0, len(v.srs.G)+1+len(v.bases))
+	bases = append(bases, v.srs.G...)
+	bases = append(bases, v.srs.Q)
+	bases = append(bases, v.bases...)
+	scalars := make([]fr.Element, 0, len(bases))
+	scalars = append(scalars, v.gScalars...)
+	scalars = append(scalars, v.qScalar)
+	scalars = append(scalars, v.scalars...)
+
+	var res bls24317.G1Jac
+	if _, err := res.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if !res.Z.IsZero() {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// crossTerm returns ⟨a, g⟩ + ⟨a, b⟩q
+func crossTerm(g []bls24317.G1Affine, a, b []fr.Element, q *bls24317.G1Affine) (bls24317.G1Affine, error) {
+	bases := make([]bls24317.G1Affine, len(g)+1)
+	copy(bases, g)
+	bases[len(g)] = *q
+	scalars := make([]fr.Element, len(a)+1)
+	copy(scalars, a)
+	scalars[len(a)] = innerProduct(a, b)
+
+	var res bls24317.G1Affine
+	if _, err := res.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// foldBases returns gL + x⁻¹gR
+func foldBases(gL, gR []bls24317.G1Affine, xInv *fr.Element) []bls24317.G1Affine {
+	var bxInv big.Int
+	xInv.BigInt(&bxInv)
+	res := make([]bls24317.G1Jac, len(gL))
+	parallel.Execute(len(gL), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].FromAffine(&gR[i])
+			res[i].ScalarMultiplication(&res[i], &bxInv).
+				AddMixed(&gL[i])
+		}
+	})
+	return bls24317.BatchJacobianToAffineG1(res)
+}
+
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, t fr.Element
+	for i := range a {
+		t.Mul(&a[i], &b[i])
+		res.Add(&res, &t)
+	}
+	return res
+}
+
+// challengeNames returns the names of the challenges of the transcript, w
+// and the challenges xᵢ of the rounds.
+func challengeNames(nbRounds int) []string {
+	res := make([]string, nbRounds+1)
+	res[0] = "w"
+	for i := 0; i < nbRounds; i++ {
+		res[i+1] = "x" + strconv.Itoa(i)
+	}
+	return res
+}
+
+// deriveChallengeW derives the challenge w, binded to the digest, the point,
+// the claimed value and dataTranscript.
+func deriveChallengeW(fs *fiatshamir.Transcript, digest *Digest, point, claimedValue fr.Element, dataTranscript ...[]byte) (fr.Element, error) {
+	bDigest := digest.RawBytes()
+	toBind := [][]byte{bDigest[:], point.Marshal(), claimedValue.Marshal()}
+	toBind = append(toBind, dataTranscript...)
+	for i := range toBind {
+		if err := fs.Bind("w", toBind[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	return computeChallenge(fs, "w")
+}
+
+// deriveChallengeX derives the challenge of the given round, binded to the
+// cross terms.
+func deriveChallengeX(fs *fiatshamir.Transcript, round int, l, r *bls24317.G1Affine) (fr.Element, error) {
+	name := "x" + strconv.Itoa(round)
+	bl, br := l.RawBytes(), r.RawBytes()
+	if err := fs.Bind(name, bl[:]); err != nil {
+		return fr.Element{}, err
+	}
+	if err := fs.Bind(name, br[:]); err != nil {
+		return fr.Element{}, err
+	}
+	return computeChallenge(fs, name)
+}
+
+func computeChallenge(fs *fiatshamir.Transcript, name string) (fr.Element, error) {
+	b, err := fs.ComputeChallenge(name)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	if res.IsZero() {
+		return res, ErrZeroChallenge
+	}
+	return res, nil
+}
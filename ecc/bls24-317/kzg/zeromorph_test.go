@@ -0,0 +1,270 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr/polynomial"
+	"github.com/consensys/gnark-crypto/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPoint(nbVariables int) []fr.Element {
+	point := make([]fr.Element, nbVariables)
+	for i := range point {
+		point[i].SetRandom()
+	}
+	return point
+}
+
+var (
+	testG2Powers     []bls24317.G2Affine
+	testG2PowersOnce sync.Once
+)
+
+// testMultilinearVk returns the verifying key of testSrs for multilinear
+// polynomials in nbVariables variables.
+func testMultilinearVk(nbVariables int) (MultilinearVerifyingKey, error) {
+	testG2PowersOnce.Do(func() {
+		// [αⁱ]G₂ for i ≤ len(testSrs.Pk.G1)
+		testG2Powers = make([]bls24317.G2Affine, len(testSrs.Pk.G1)+1)
+		var alpha, alphaI fr.Element
+		alpha.SetBigInt(bAlpha)
+		alphaI.SetOne()
+		var b big.Int
+		for i := range testG2Powers {
+			testG2Powers[i].ScalarMultiplicationBase(alphaI.BigInt(&b))
+			alphaI.Mul(&alphaI, &alpha)
+		}
+	})
+	return NewMultilinearVerifyingKey(testSrs.Vk, testG2Powers, len(testSrs.Pk.G1), nbVariables)
+}
+
+func TestMultilinearVerifyingKey(t *testing.T) {
+	assert := require.New(t)
+
+	vk, err := testMultilinearVk(5)
+	assert.NoError(err)
+	t.Run("serialization round-trip", utils.SerializationRoundTrip(&vk))
+
+	_, err = NewMultilinearVerifyingKey(testSrs.Vk, testG2Powers[:len(testSrs.Pk.G1)-32], len(testSrs.Pk.G1), 5)
+	assert.ErrorIs(err, ErrInvalidG2Powers)
+	_, err = NewMultilinearVerifyingKey(testSrs.Vk, testG2Powers[1:], len(testSrs.Pk.G1), 5)
+	assert.ErrorIs(err, ErrInvalidG2Powers)
+	_, err = NewMultilinearVerifyingKey(testSrs.Vk, testG2Powers, 16, 5)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestOpenMultilinear(t *testing.T) {
+	assert := require.New(t)
+
+	const nbVariables = 5
+	f := polynomial.MultiLin(randomPolynomial(1 << nbVariables))
+	digest, err := Commit(f, testSrs.Pk)
+	assert.NoError(err)
+	point := randomPoint(nbVariables)
+	vk, err := testMultilinearVk(nbVariables)
+	assert.NoError(err)
+
+	hf := sha256.New()
+	proof, err := OpenMultilinear(f, digest, point, hf, testSrs.Pk, []byte("data"))
+	assert.NoError(err)
+
+	// check the claimed value
+	expected := f.Evaluate(point, nil)
+	assert.True(expected.Equal(&proof.ClaimedValue), "wrong claimed value")
+
+	err = VerifyMultilinear(&digest, &proof, point, hf, vk, []byte("data"))
+	assert.NoError(err)
+
+	t.Run("serialization round-trip", utils.SerializationRoundTrip(&proof))
+
+	// wrong transcript
+	err = VerifyMultilinear(&digest, &proof, point, hf, vk, []byte("wrong data"))
+	assert.Error(err)
+
+	// wrong point
+	wrongPoint := make([]fr.Element, nbVariables)
+	copy(wrongPoint, point)
+	wrongPoint[2].SetRandom()
+	err = VerifyMultilinear(&digest, &proof, wrongPoint, hf, vk, []byte("data"))
+	assert.Error(err)
+	err = VerifyMultilinear(&digest, &proof, point[1:], hf, vk, []byte("data"))
+	assert.ErrorIs(err, ErrInvalidNbVariables)
+
+	// wrong claimed value
+	proof.ClaimedValue.Double(&proof.ClaimedValue)
+	err = VerifyMultilinear(&digest, &proof, point, hf, vk, []byte("data"))
+	assert.Error(err)
+	proof.ClaimedValue = expected
+
+	// wrong quotient
+	proof.Quotients[1], proof.Quotients[3] = proof.Quotients[3], proof.Quotients[1]
+	err = VerifyMultilinear(&digest, &proof, point, hf, vk, []byte("data"))
+	assert.Error(err)
+	proof.Quotients[1], proof.Quotients[3] = proof.Quotients[3], proof.Quotients[1]
+
+	// the proof is bound to the size of the proving key
+	_proof, err := OpenMultilinear(f, digest, point, hf, ProvingKey{G1: testSrs.Pk.G1[:128]}, []byte("data"))
+	assert.NoError(err)
+	err = VerifyMultilinear(&digest, &_proof, point, hf, vk, []byte("data"))
+	assert.ErrorIs(err, ErrVerifyOpeningProof)
+
+	// wrong number of variables of the verifying key
+	_vk, err := testMultilinearVk(nbVariables + 1)
+	assert.NoError(err)
+	err = VerifyMultilinear(&digest, &proof, point, hf, _vk, []byte("data"))
+	assert.ErrorIs(err, ErrInvalidNbVariables)
+
+	// invalid sizes
+	_, err = OpenMultilinear(f[:24], digest, point, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = OpenMultilinear(f, digest, point[1:], hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbVariables)
+}
+
+func TestOpenMultilinearHypercube(t *testing.T) {
+	assert := require.New(t)
+
+	// on the hypercube, the evaluation is an entry of f, the first
+	// coordinate being the most significant bit of the index
+	const nbVariables = 4
+	f := randomPolynomial(1 << nbVariables)
+	digest, err := Commit(f, testSrs.Pk)
+	assert.NoError(err)
+	vk, err := testMultilinearVk(nbVariables)
+	assert.NoError(err)
+
+	hf := sha256.New()
+	for _, index := range []int{0, 1, 6, 15} {
+		point := make([]fr.Element, nbVariables)
+		for i := range point {
+			point[i].SetUint64(uint64(index>>(nbVariables-1-i)) & 1)
+		}
+		proof, err := OpenMultilinear(f, digest, point, hf, testSrs.Pk)
+		assert.NoError(err)
+		assert.True(f[index].Equal(&proof.ClaimedValue), "wrong claimed value")
+		assert.NoError(VerifyMultilinear(&digest, &proof, point, hf, vk))
+	}
+
+	// constant polynomial, no variable
+	digest, err = Commit(f[:1], testSrs.Pk)
+	assert.NoError(err)
+	proof, err := OpenMultilinear(f[:1], digest, nil, hf, testSrs.Pk)
+	assert.NoError(err)
+	assert.True(f[0].Equal(&proof.ClaimedValue), "wrong claimed value")
+	vk, err = testMultilinearVk(0)
+	assert.NoError(err)
+	assert.NoError(VerifyMultilinear(&digest, &proof, nil, hf, vk))
+}
+
+func TestBatchOpenMultilinear(t *testing.T) {
+	assert := require.New(t)
+
+	const nbVariables = 6
+	const nbPolynomials = 4
+	f := make([][]fr.Element, nbPolynomials)
+	digests := make([]Digest, nbPolynomials)
+	for i := range f {
+		f[i] = randomPolynomial(1 << nbVariables)
+		var err error
+		digests[i], err = Commit(f[i], testSrs.Pk)
+		assert.NoError(err)
+	}
+	point := randomPoint(nbVariables)
+	vk, err := testMultilinearVk(nbVariables)
+	assert.NoError(err)
+
+	hf := sha256.New()
+	proof, err := BatchOpenMultilinear(f, digests, point, hf, testSrs.Pk, []byte("data"))
+	assert.NoError(err)
+
+	// check the claimed values
+	for i := range f {
+		expected := polynomial.MultiLin(f[i]).Evaluate(point, nil)
+		assert.True(expected.Equal(&proof.ClaimedValues[i]), "wrong claimed value")
+	}
+
+	err = BatchVerifyMultilinear(digests, &proof, point, hf, vk, []byte("data"))
+	assert.NoError(err)
+
+	t.Run("serialization round-trip", utils.SerializationRoundTrip(&proof))
+
+	// wrong digests
+	digests[0], digests[1] = digests[1], digests[0]
+	err = BatchVerifyMultilinear(digests, &proof, point, hf, vk, []byte("data"))
+	assert.Error(err)
+	digests[0], digests[1] = digests[1], digests[0]
+
+	// wrong claimed value
+	proof.ClaimedValues[2].Double(&proof.ClaimedValues[2])
+	err = BatchVerifyMultilinear(digests, &proof, point, hf, vk, []byte("data"))
+	assert.Error(err)
+
+	// inconsistent sizes
+	f[1] = f[1][:32]
+	_, err = BatchOpenMultilinear(f, digests, point, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = BatchOpenMultilinear(f, digests[1:], point, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbDigests)
+}
+
+func BenchmarkOpenMultilinear(b *testing.B) {
+	const nbVariables = 7
+	f := randomPolynomial(1 << nbVariables)
+	digest, err := Commit(f, testSrs.Pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+	point := randomPoint(nbVariables)
+	hf := sha256.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = OpenMultilinear(f, digest, point, hf, testSrs.Pk)
+	}
+}
+
+func BenchmarkVerifyMultilinear(b *testing.B) {
+	const nbVariables = 7
+	f := randomPolynomial(1 << nbVariables)
+	digest, err := Commit(f, testSrs.Pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+	point := randomPoint(nbVariables)
+	hf := sha256.New()
+	proof, err := OpenMultilinear(f, digest, point, hf, testSrs.Pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+	vk, err := testMultilinearVk(nbVariables)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = VerifyMultilinear(&digest, &proof, point, hf, vk)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24317
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+)
+
+func TestMultiExpStream(t *testing.T) {
+	t.Parallel()
+
+	const nbPoints = 200
+	scalars := make([]fr.Element, nbPoints)
+	fillBenchScalars(scalars)
+	points := BatchScalarMultiplicationG1(&g1GenAff, scalars)
+	fillBenchScalars(scalars)
+	// check that the infinity point is handled
+	points[3].X.SetZero()
+	points[3].Y.SetZero()
+
+	var expected G1Affine
+	if _, err := expected.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var bScalars bytes.Buffer
+	for i := range scalars {
+		b := scalars[i].Bytes()
+		bScalars.Write(b[:])
+	}
+
+	for _, raw := range []bool{false, true} {
+		var bPoints bytes.Buffer
+		var enc *Encoder
+		if raw {
+			enc = NewEncoder(&bPoints, RawEncoding())
+		} else {
+			enc = NewEncoder(&bPoints)
+		}
+		for i := range points {
+			if err := enc.Encode(&points[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, chunkSize := range []int{1, 7, 64, nbPoints, 2 * nbPoints} {
+			r := NewMultiExpReader(bytes.NewReader(bPoints.Bytes()), bytes.NewReader(bScalars.Bytes()))
+			var res G1Affine
+			if _, err := res.MultiExpStream(r, chunkSize, ecc.MultiExpConfig{}); err != nil {
+				t.Fatal(err)
+			}
+			if !res.Equal(&expected) {
+				t.Fatalf("streaming MSM (raw: %v, chunk size: %d) differs from the in-memory MSM", raw, chunkSize)
+			}
+		}
+	}
+
+	// fewer points than scalars
+	var bPoints bytes.Buffer
+	enc := NewEncoder(&bPoints)
+	for i := 0; i < nbPoints-1; i++ {
+		if err := enc.Encode(&points[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewMultiExpReader(&bPoints, bytes.NewReader(bScalars.Bytes()))
+	var res G1Affine
+	if _, err := res.MultiExpStream(r, 64, ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("expected an error when points are missing")
+	}
+
+	// truncated scalar
+	r = NewMultiExpReader(bytes.NewReader(bPoints.Bytes()), bytes.NewReader(bScalars.Bytes()[:fr.Bytes+1]))
+	if _, err := res.MultiExpStream(r, 64, ecc.MultiExpConfig{}); err != io.ErrUnexpectedEOF {
+		t.Fatal("expected io.ErrUnexpectedEOF on a truncated scalar")
+	}
+
+	// empty stream
+	r = NewMultiExpReader(bytes.NewReader(nil), bytes.NewReader(nil))
+	if _, err := res.MultiExpStream(r, 64, ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsInfinity() {
+		t.Fatal("MSM of an empty stream should be the infinity point")
+	}
+
+	// a reader which never makes progress
+	if _, err := res.MultiExpStream(noProgressReader{}, 64, ecc.MultiExpConfig{}); err != io.ErrNoProgress {
+		t.Fatal("expected io.ErrNoProgress when the reader returns no pairs and no error")
+	}
+}
+
+// noProgressReader always returns 0 pairs and no error.
+type noProgressReader struct{}
+
+func (noProgressReader) ReadChunk([]G1Affine, []fr.Element) (int, error) {
+	return 0, nil
+}
+
+func BenchmarkMultiExpStream(b *testing.B) {
+	const nbPoints = 1 << 16
+	scalars := make([]fr.Element, nbPoints)
+	fillBenchScalars(scalars)
+	points := BatchScalarMultiplicationG1(&g1GenAff, scalars)
+	fillBenchScalars(scalars)
+
+	var bPoints, bScalars bytes.Buffer
+	enc := NewEncoder(&bPoints, RawEncoding())
+	for i := range points {
+		if err := enc.Encode(&points[i]); err != nil {
+			b.Fatal(err)
+		}
+		s := scalars[i].Bytes()
+		bScalars.Write(s[:])
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := NewMultiExpReader(bytes.NewReader(bPoints.Bytes()), bytes.NewReader(bScalars.Bytes()), NoSubgroupChecks())
+		var res G1Affine
+		res.MultiExpStream(r, nbPoints/4, ecc.MultiExpConfig{})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestSignDeterministic(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := privKey.PublicKey
+	msg := []byte("testing RFC 6979")
+	hFunc := sha256.New()
+
+	for _, h := range []func() ([]byte, []byte){
+		// hashed by the signer
+		func() ([]byte, []byte) {
+			s1, _ := privKey.SignDeterministic(msg, hFunc)
+			s2, _ := privKey.SignDeterministic(msg, hFunc)
+			return s1, s2
+		},
+		// pre-hashed
+		func() ([]byte, []byte) {
+			digest := sha256.Sum256(msg)
+			s1, _ := privKey.SignDeterministic(digest[:], nil)
+			s2, _ := privKey.SignDeterministic(digest[:], nil)
+			return s1, s2
+		},
+	} {
+		sig1, sig2 := h()
+		if !bytes.Equal(sig1, sig2) {
+			t.Fatal("deterministic signatures differ")
+		}
+		var sig Signature
+		if _, err := sig.SetBytes(sig1); err != nil {
+			t.Fatal(err)
+		}
+		if !sig.IsLowS() {
+			t.Fatal("deterministic signature should be low-s")
+		}
+	}
+
+	sig, err := privKey.SignDeterministic(msg, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := publicKey.Verify(sig, msg, hFunc); !ok {
+		t.Fatal("deterministic signature should verify")
+	}
+	if ok, _ := publicKey.Verify(sig, []byte("wrong message"), hFunc); ok {
+		t.Fatal("deterministic signature should not verify for a wrong message")
+	}
+	digest := sha256.Sum256(msg)
+	sig, err = privKey.SignDeterministic(digest[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := publicKey.Verify(sig, digest[:], nil); !ok {
+		t.Fatal("deterministic signature of a pre-hashed message should verify")
+	}
+}
+
+func TestSignRecoverable(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 10; i++ {
+		privKey, err := GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := []byte("testing recovery")
+		hFunc := sha256.New()
+		sig, err := privKey.SignRecoverable(msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sig) != 2*sizeFr+1 {
+			t.Fatal("wrong recoverable signature size")
+		}
+
+		var recovered PublicKey
+		if err := recovered.RecoverFromSignature(msg, sig, hFunc); err != nil {
+			t.Fatal(err)
+		}
+		if !recovered.Equal(&privKey.PublicKey) {
+			t.Fatal("recovered public key differs")
+		}
+
+		// r||s is a regular signature
+		if ok, _ := privKey.PublicKey.Verify(sig[:sizeSignature], msg, hFunc); !ok {
+			t.Fatal("signature should verify")
+		}
+	}
+}
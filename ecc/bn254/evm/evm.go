@@ -0,0 +1,210 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package evm implements the encodings of the BN254 points used by the
+// Ethereum precompiled contracts, and the precompiles themselves:
+//
+//   - ECAdd (0x06) and ECMul (0x07), see EIP-196
+//   - ECPairing (0x08), see EIP-197
+//
+// Field elements are 32-byte big-endian integers, smaller than the modulus.
+// A G1 point is encoded as x || y, a G2 point as x.A1 || x.A0 || y.A1 || y.A0
+// (the imaginary part first). The point at infinity is encoded with zeroes.
+//
+// The gas costs are not computed by this package.
+package evm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+const (
+	// SizeOfFp is the size of an encoded field element.
+	SizeOfFp = fp.Bytes
+
+	// SizeOfG1 is the size of an encoded G1 point.
+	SizeOfG1 = 2 * SizeOfFp
+
+	// SizeOfG2 is the size of an encoded G2 point.
+	SizeOfG2 = 4 * SizeOfFp
+
+	// SizeOfScalar is the size of an encoded scalar.
+	SizeOfScalar = 32
+)
+
+const (
+	ecAddInputSize       = 2 * SizeOfG1
+	ecMulInputSize       = SizeOfG1 + SizeOfScalar
+	ecPairingElementSize = SizeOfG1 + SizeOfG2
+)
+
+var (
+	ErrInvalidInputLength  = errors.New("invalid input length")
+	ErrInvalidFieldElement = errors.New("invalid field element: not smaller than the modulus")
+	ErrPointNotOnCurve     = errors.New("point is not on the curve")
+	ErrPointNotInSubgroup  = errors.New("point is not in the prime order subgroup")
+)
+
+// EncodeG1 returns the encoding of p.
+func EncodeG1(p *bn254.G1Affine) [SizeOfG1]byte {
+	var res [SizeOfG1]byte
+	fp.BigEndian.PutElement((*[SizeOfFp]byte)(res[:SizeOfFp]), p.X)
+	fp.BigEndian.PutElement((*[SizeOfFp]byte)(res[SizeOfFp:]), p.Y)
+	return res
+}
+
+// DecodeG1 decodes a G1 point, checked to be on the curve.
+func DecodeG1(b []byte) (bn254.G1Affine, error) {
+	var p bn254.G1Affine
+	if len(b) != SizeOfG1 {
+		return p, ErrInvalidInputLength
+	}
+	var err error
+	if p.X, err = decodeFp(b[:SizeOfFp]); err != nil {
+		return p, err
+	}
+	if p.Y, err = decodeFp(b[SizeOfFp:]); err != nil {
+		return p, err
+	}
+	if !p.IsOnCurve() {
+		return p, ErrPointNotOnCurve
+	}
+	return p, nil
+}
+
+// EncodeG2 returns the encoding of p.
+func EncodeG2(p *bn254.G2Affine) [SizeOfG2]byte {
+	var res [SizeOfG2]byte
+	for i, e := range []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0} {
+		fp.BigEndian.PutElement((*[SizeOfFp]byte)(res[i*SizeOfFp:(i+1)*SizeOfFp]), *e)
+	}
+	return res
+}
+
+// DecodeG2 decodes a G2 point, checked to be on the curve and in the prime
+// order subgroup.
+func DecodeG2(b []byte) (bn254.G2Affine, error) {
+	var p bn254.G2Affine
+	if len(b) != SizeOfG2 {
+		return p, ErrInvalidInputLength
+	}
+	for i, e := range []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0} {
+		var err error
+		if *e, err = decodeFp(b[i*SizeOfFp : (i+1)*SizeOfFp]); err != nil {
+			return p, err
+		}
+	}
+	if !p.IsOnCurve() {
+		return p, ErrPointNotOnCurve
+	}
+	if !p.IsInSubGroup() {
+		return p, ErrPointNotInSubgroup
+	}
+	return p, nil
+}
+
+// ECAdd implements the addition precompile: the input is x₁ || y₁ || x₂ || y₂,
+// right-padded with zeroes (or truncated) to 128 bytes, and the output is the
+// encoding of the sum of the points.
+func ECAdd(input []byte) ([]byte, error) {
+	input = rightPad(input, ecAddInputSize)
+	p, err := DecodeG1(input[:SizeOfG1])
+	if err != nil {
+		return nil, err
+	}
+	q, err := DecodeG1(input[SizeOfG1:])
+	if err != nil {
+		return nil, err
+	}
+	p.Add(&p, &q)
+	res := EncodeG1(&p)
+	return res[:], nil
+}
+
+// ECMul implements the scalar multiplication precompile: the input is
+// x || y || s, right-padded with zeroes (or truncated) to 96 bytes, and the
+// output is the encoding of [s](x, y). The scalar s is any 256-bit integer.
+func ECMul(input []byte) ([]byte, error) {
+	input = rightPad(input, ecMulInputSize)
+	p, err := DecodeG1(input[:SizeOfG1])
+	if err != nil {
+		return nil, err
+	}
+	// G1 has prime order r, the scalar is reduced modulo r
+	var s big.Int
+	s.SetBytes(input[SizeOfG1:]).Mod(&s, fr.Modulus())
+	p.ScalarMultiplication(&p, &s)
+	res := EncodeG1(&p)
+	return res[:], nil
+}
+
+// ECPairing implements the pairing check precompile: the input is a sequence
+// of G1 and G2 points (P₁, Q₁, …, Pₖ, Qₖ), and the output is the 32-byte
+// encoding of 1 if ∏ e(Pᵢ, Qᵢ) = 1 (or if k = 0), of 0 otherwise.
+func ECPairing(input []byte) ([]byte, error) {
+	if len(input)%ecPairingElementSize != 0 {
+		return nil, ErrInvalidInputLength
+	}
+	k := len(input) / ecPairingElementSize
+	res := make([]byte, 32)
+	if k == 0 {
+		res[31] = 1
+		return res, nil
+	}
+
+	P := make([]bn254.G1Affine, k)
+	Q := make([]bn254.G2Affine, k)
+	for i := 0; i < k; i++ {
+		b := input[i*ecPairingElementSize : (i+1)*ecPairingElementSize]
+		var err error
+		if P[i], err = DecodeG1(b[:SizeOfG1]); err != nil {
+			return nil, err
+		}
+		if Q[i], err = DecodeG2(b[SizeOfG1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	ok, err := bn254.PairingCheck(P, Q)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		res[31] = 1
+	}
+	return res, nil
+}
+
+func decodeFp(b []byte) (fp.Element, error) {
+	e, err := fp.BigEndian.Element((*[SizeOfFp]byte)(b))
+	if err != nil {
+		return e, ErrInvalidFieldElement
+	}
+	return e, nil
+}
+
+// rightPad returns b right-padded with zeroes, or truncated, to size bytes.
+func rightPad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b[:size]
+	}
+	res := make([]byte, size)
+	copy(res, b)
+	return res
+}
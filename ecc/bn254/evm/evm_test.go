@@ -0,0 +1,239 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evm
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomG1() (bn254.G1Affine, fr.Element) {
+	var s fr.Element
+	s.SetRandom()
+	var p bn254.G1Affine
+	var b big.Int
+	p.ScalarMultiplicationBase(s.BigInt(&b))
+	return p, s
+}
+
+func randomG2() (bn254.G2Affine, fr.Element) {
+	var s fr.Element
+	s.SetRandom()
+	var p bn254.G2Affine
+	var b big.Int
+	p.ScalarMultiplicationBase(s.BigInt(&b))
+	return p, s
+}
+
+func concat(b ...[]byte) []byte {
+	var res []byte
+	for i := range b {
+		res = append(res, b[i]...)
+	}
+	return res
+}
+
+func TestEncoding(t *testing.T) {
+	p, _ := randomG1()
+	b := EncodeG1(&p)
+	pp, err := DecodeG1(b[:])
+	require.NoError(t, err)
+	assert.True(t, pp.Equal(&p))
+
+	q, _ := randomG2()
+	c := EncodeG2(&q)
+	qq, err := DecodeG2(c[:])
+	require.NoError(t, err)
+	assert.True(t, qq.Equal(&q))
+
+	// the imaginary part comes first
+	var x [SizeOfFp]byte
+	fp.BigEndian.PutElement(&x, q.X.A1)
+	assert.Equal(t, x[:], c[:SizeOfFp])
+
+	// the point at infinity is encoded with zeroes
+	var inf1 bn254.G1Affine
+	var inf2 bn254.G2Affine
+	assert.Equal(t, [SizeOfG1]byte{}, EncodeG1(&inf1))
+	assert.Equal(t, [SizeOfG2]byte{}, EncodeG2(&inf2))
+	pp, err = DecodeG1(make([]byte, SizeOfG1))
+	require.NoError(t, err)
+	assert.True(t, pp.IsInfinity())
+	qq, err = DecodeG2(make([]byte, SizeOfG2))
+	require.NoError(t, err)
+	assert.True(t, qq.IsInfinity())
+
+	// invalid encodings
+	_, err = DecodeG1(b[1:])
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+	bad := b
+	copy(bad[:SizeOfFp], fp.Modulus().Bytes())
+	_, err = DecodeG1(bad[:])
+	assert.ErrorIs(t, err, ErrInvalidFieldElement)
+	bad = b
+	bad[SizeOfG1-1] ^= 1
+	_, err = DecodeG1(bad[:])
+	assert.ErrorIs(t, err, ErrPointNotOnCurve)
+
+	var u bn254.E2
+	u.SetRandom()
+	notInSubgroup := bn254.MapToCurve2(&u)
+	require.True(t, notInSubgroup.IsOnCurve())
+	require.False(t, notInSubgroup.IsInSubGroup())
+	c = EncodeG2(&notInSubgroup)
+	_, err = DecodeG2(c[:])
+	assert.ErrorIs(t, err, ErrPointNotInSubgroup)
+}
+
+func TestECAdd(t *testing.T) {
+	p, _ := randomG1()
+	q, _ := randomG1()
+	var expected bn254.G1Affine
+	expected.Add(&p, &q)
+
+	b1, b2 := EncodeG1(&p), EncodeG1(&q)
+	res, err := ECAdd(concat(b1[:], b2[:]))
+	require.NoError(t, err)
+	e := EncodeG1(&expected)
+	assert.Equal(t, e[:], res)
+
+	// doubling
+	expected.Double(&p)
+	res, err = ECAdd(concat(b1[:], b1[:]))
+	require.NoError(t, err)
+	e = EncodeG1(&expected)
+	assert.Equal(t, e[:], res)
+
+	// P + (-P)
+	q.Neg(&p)
+	b2 = EncodeG1(&q)
+	res, err = ECAdd(concat(b1[:], b2[:]))
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, SizeOfG1), res)
+
+	// short inputs are padded, long inputs truncated
+	res, err = ECAdd(b1[:])
+	require.NoError(t, err)
+	assert.Equal(t, b1[:], res)
+	res, err = ECAdd(concat(b1[:], make([]byte, SizeOfG1), []byte{1, 2, 3}))
+	require.NoError(t, err)
+	assert.Equal(t, b1[:], res)
+	res, err = ECAdd(nil)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, SizeOfG1), res)
+
+	// invalid point
+	b1[SizeOfG1-1] ^= 1
+	_, err = ECAdd(b1[:])
+	assert.ErrorIs(t, err, ErrPointNotOnCurve)
+
+	// reference vector
+	input, _ := hex.DecodeString("18b18acfb4c2c30276db5411368e7185b311dd124691610c5d3b74034e093dc9063c909c4720840cb5134cb9f59fa749755796819658d32efc0d288198f3726607c2b7f58a84bd6145f00c9c2bc0bb1a187f20ff2c92963a88019e7c6a014eed06614e20c147e940f2d70da3f74c9a17df361706a4485c742bd6788478fa17d7")
+	output, _ := hex.DecodeString("2243525c5efd4b9c3d3c45ac0ca3fe4dd85e830a4ce6b65fa1eeaee202839703301d1d33be6da8e509df21cc35964723180eed7532537db9ae5e7d48f195c915")
+	res, err = ECAdd(input)
+	require.NoError(t, err)
+	assert.Equal(t, output, res)
+}
+
+func TestECMul(t *testing.T) {
+	p, _ := randomG1()
+	var s fr.Element
+	s.SetRandom()
+	var expected bn254.G1Affine
+	var b big.Int
+	expected.ScalarMultiplication(&p, s.BigInt(&b))
+
+	bp := EncodeG1(&p)
+	bs := s.Bytes()
+	res, err := ECMul(concat(bp[:], bs[:]))
+	require.NoError(t, err)
+	e := EncodeG1(&expected)
+	assert.Equal(t, e[:], res)
+
+	// the scalar is not reduced
+	b.Add(&b, fr.Modulus())
+	res, err = ECMul(concat(bp[:], b.FillBytes(make([]byte, SizeOfScalar))))
+	require.NoError(t, err)
+	assert.Equal(t, e[:], res)
+
+	// [r]P = 0
+	res, err = ECMul(concat(bp[:], fr.Modulus().FillBytes(make([]byte, SizeOfScalar))))
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, SizeOfG1), res)
+
+	// short input
+	res, err = ECMul(bp[:])
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, SizeOfG1), res)
+
+	// reference vector
+	input, _ := hex.DecodeString("2bd3e6d0f3b142924f5ca7b49ce5b9d54c4703d7ae5648e61d02268b1a0a9fb721611ce0a6af85915e2f1d70300909ce2e49dfad4a4619c8390cae66cefdb20400000000000000000000000000000000000000000000000011138ce750fa15c2")
+	output, _ := hex.DecodeString("070a8d6a982153cae4be29d434e8faef8a47b274a053f5a4ee2a6c9c13c31e5c031b8ce914eba3a9ffb989f9cdd5b0f01943074bf4f0f315690ec3cec6981afc")
+	res, err = ECMul(input)
+	require.NoError(t, err)
+	assert.Equal(t, output, res)
+}
+
+func TestECPairing(t *testing.T) {
+	one := make([]byte, 32)
+	one[31] = 1
+	zero := make([]byte, 32)
+
+	// e(P, [s]Q)⋅e(-[s]P, Q) = 1
+	p, _ := randomG1()
+	q, _ := randomG2()
+	var s fr.Element
+	s.SetRandom()
+	var b big.Int
+	var sp bn254.G1Affine
+	var sq bn254.G2Affine
+	sp.ScalarMultiplication(&p, s.BigInt(&b)).Neg(&sp)
+	sq.ScalarMultiplication(&q, &b)
+
+	bp, bsp := EncodeG1(&p), EncodeG1(&sp)
+	bq, bsq := EncodeG2(&q), EncodeG2(&sq)
+	res, err := ECPairing(concat(bp[:], bsq[:], bsp[:], bq[:]))
+	require.NoError(t, err)
+	assert.Equal(t, one, res)
+
+	res, err = ECPairing(concat(bp[:], bsq[:], bp[:], bq[:]))
+	require.NoError(t, err)
+	assert.Equal(t, zero, res)
+
+	// empty input and points at infinity
+	res, err = ECPairing(nil)
+	require.NoError(t, err)
+	assert.Equal(t, one, res)
+	res, err = ECPairing(concat(bp[:], make([]byte, SizeOfG2)))
+	require.NoError(t, err)
+	assert.Equal(t, one, res)
+
+	// invalid inputs
+	_, err = ECPairing(concat(bp[:], bq[1:]))
+	assert.ErrorIs(t, err, ErrInvalidInputLength)
+	var u bn254.E2
+	u.SetRandom()
+	notInSubgroup := bn254.MapToCurve2(&u)
+	bn := EncodeG2(&notInSubgroup)
+	_, err = ECPairing(concat(bp[:], bn[:]))
+	assert.ErrorIs(t, err, ErrPointNotInSubgroup)
+}
@@ -40,6 +40,7 @@ var (
 	ErrSizeNotPowerOfTwo          = errors.New("the size of the polynomials must be a power of two")
 	ErrInconsistentSizeDomain     = errors.New("the size of the domain must be consistent with the size of the polynomials")
 	ErrIncorrectNumberOfVariables = errors.New("the number of variables is incorrect")
+	ErrZeroDenominator            = errors.New("a denominator of the ratios is zero")
 )
 
 // Build an 'accumulating ratio' polynomial.
@@ -256,6 +257,66 @@ func BuildRatioCopyConstraint(
 
 }
 
+// BuildRatioLogDerivative builds the accumulating sum of ratios of the
+// logarithmic derivative lookup argument (logUp), to prove that the entries of
+// f are entries of t, the j-th entry of t being used m(ωʲ) times.
+// * f, t, m polynomials of the same size, a power of 2
+// * beta variable at which the ratios are evaluated
+// * expectedForm expected form of the resulting polynomial
+// * Return: say beta=β, the function returns the polynomial Z whose evaluation
+// on the j-th root of unity is
+// Z(ωʲ) = Σ_{i<j} 1/(β-f(ωⁱ)) - m(ωⁱ)/(β-t(ωⁱ))
+// The sum on the full domain, that is Z(ωⁿ) = Z(1) = 0, vanishes if and only
+// if (with high probability on β) the lookup is correct.
+func BuildRatioLogDerivative(f, t, m *Polynomial, beta fr.Element, expectedForm Form, domain *fft.Domain) (*Polynomial, error) {
+
+	// check that the sizes are consistent
+	err := checkSize([]*Polynomial{f, t, m})
+	if err != nil {
+		return nil, err
+	}
+
+	// create the domain + some checks on the sizes of the polynomials
+	n := f.coefficients.Len()
+	domain, err = buildDomain(n, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	// put every polynomials in Lagrange form
+	f.ToLagrange(domain)
+	t.ToLagrange(domain)
+	m.ToLagrange(domain)
+
+	// the denominators β-f(ωⁱ) and β-t(ωⁱ)
+	den := make([]fr.Element, 2*n)
+	for i := 0; i < n; i++ {
+		a, b := f.GetCoeff(i), t.GetCoeff(i)
+		den[i].Sub(&beta, &a)
+		den[n+i].Sub(&beta, &b)
+		if den[i].IsZero() || den[n+i].IsZero() {
+			return nil, ErrZeroDenominator
+		}
+	}
+	den = fr.BatchInvert(den)
+
+	coeffs := make([]fr.Element, n)
+	var a fr.Element
+	for i := 0; i < n-1; i++ {
+		mi := m.GetCoeff(i)
+		a.Mul(&mi, &den[n+i])
+		coeffs[i+1].Add(&coeffs[i], &den[i]).
+			Sub(&coeffs[i+1], &a)
+	}
+
+	res := NewPolynomial(&coeffs, expectedForm)
+
+	// at this stage the result is in Lagrange form, Regular layout
+	putInExpectedFormFromLagrangeRegular(res, domain, expectedForm)
+
+	return res, nil
+}
+
 func putInExpectedFormFromLagrangeRegular(p *Polynomial, domain *fft.Domain, expectedForm Form) {
 	p.Basis = expectedForm.Basis
 	p.Layout = expectedForm.Layout
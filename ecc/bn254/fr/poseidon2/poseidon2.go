@@ -0,0 +1,319 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
+)
+
+const (
+	// DefaultWidth is the default size of the state
+	DefaultWidth = 3
+
+	// DefaultNbFullRounds is the default number of full rounds
+	DefaultNbFullRounds = 8
+
+	// DefaultNbPartialRounds is the default number of partial rounds
+	DefaultNbPartialRounds = 56
+
+	// SBoxDegree is the degree d of the s-box x -> xᵈ
+	SBoxDegree = 5
+)
+
+// Parameters describe the poseidon2 implementation.
+type Parameters struct {
+	// len(preimage)+len(digest)=len(preimage)+ceil(log(2*<security_level>/r))
+	Width int
+
+	// number of full rounds (even number)
+	NbFullRounds int
+
+	// number of partial rounds
+	NbPartialRounds int
+
+	// derived round keys from the parameter seed and curve ID
+	RoundKeys [][]fr.Element
+
+	// diagonal of the internal matrix, minus one. Only used when Width > 3.
+	diagInternalMatrix []fr.Element
+}
+
+// NewParameters returns a new set of parameters for the poseidon2 permutation.
+// The round keys are derived deterministically from a seed depending on the
+// width and the number of rounds.
+func NewParameters(width, nbFullRounds, nbPartialRounds int) *Parameters {
+	if width < 2 || (width > 3 && width%4 != 0) {
+		panic("poseidon2: width must be 2, 3 or a multiple of 4")
+	}
+	if nbFullRounds%2 != 0 {
+		panic("poseidon2: the number of full rounds must be even")
+	}
+	p := Parameters{Width: width, NbFullRounds: nbFullRounds, NbPartialRounds: nbPartialRounds}
+	seed := p.String()
+	p.initRC(seed)
+	if width > 3 {
+		p.initDiagInternalMatrix(seed)
+	}
+	return &p
+}
+
+// String returns a string representation of the parameters. It is also used
+// as seed to derive the round keys.
+func (p *Parameters) String() string {
+	return fmt.Sprintf("Poseidon2-BN254[t=%d,rF=%d,rP=%d,d=%d]", p.Width, p.NbFullRounds, p.NbPartialRounds, SBoxDegree)
+}
+
+// initRC initiate round keys. Only one entry is non zero for the internal
+// rounds, cf https://eprint.iacr.org/2023/323.pdf page 9
+func (p *Parameters) initRC(seed string) {
+
+	keys := make([][]fr.Element, p.NbFullRounds+p.NbPartialRounds)
+	alloc := make([]fr.Element, p.Width*p.NbFullRounds+p.NbPartialRounds)
+
+	rnd := newKeccakStream(seed)
+	rf := p.NbFullRounds / 2
+	for i := 0; i < len(keys); i++ {
+		n := p.Width
+		if i >= rf && i < rf+p.NbPartialRounds {
+			n = 1
+		}
+		keys[i], alloc = alloc[:n], alloc[n:]
+		for j := range keys[i] {
+			keys[i][j].SetBytes(rnd.next())
+		}
+	}
+	p.RoundKeys = keys
+}
+
+// initDiagInternalMatrix derives the diagonal D of the internal matrix
+// M = 𝟙 + diag(D), rejecting the (unlikely) choices for which M is singular.
+func (p *Parameters) initDiagInternalMatrix(seed string) {
+	rnd := newKeccakStream(seed + "-internal")
+	diag := make([]fr.Element, p.Width)
+	for {
+		for i := range diag {
+			for diag[i].IsZero() {
+				diag[i].SetBytes(rnd.next())
+			}
+		}
+		// det(𝟙 + diag(D)) = ∏dᵢ * (1 + ∑1/dᵢ)
+		inv := fr.BatchInvert(diag)
+		var s fr.Element
+		s.SetOne()
+		for i := range inv {
+			s.Add(&s, &inv[i])
+		}
+		if !s.IsZero() {
+			break
+		}
+		for i := range diag {
+			diag[i].SetZero()
+		}
+	}
+	p.diagInternalMatrix = diag
+}
+
+// keccakStream is a deterministic stream of pseudo random bytes derived from a
+// seed, obtained by iterating keccak256.
+type keccakStream struct {
+	rnd []byte
+}
+
+func newKeccakStream(seed string) *keccakStream {
+	hash := sha3.NewLegacyKeccak256()
+	_, _ = hash.Write([]byte(seed))
+	return &keccakStream{rnd: hash.Sum(nil)}
+}
+
+func (s *keccakStream) next() []byte {
+	hash := sha3.NewLegacyKeccak256()
+	_, _ = hash.Write(s.rnd)
+	s.rnd = hash.Sum(nil)
+	return s.rnd
+}
+
+// Permutation stores the buffer of the poseidon2 permutation and provides poseidon2 permutation
+// methods on the buffer
+type Permutation struct {
+	// parameters describing the instance
+	params *Parameters
+}
+
+// NewPermutation returns a new poseidon2 permutation instance.
+func NewPermutation(width, nbFullRounds, nbPartialRounds int) *Permutation {
+	return &Permutation{params: NewParameters(width, nbFullRounds, nbPartialRounds)}
+}
+
+// NewPermutationWithParameters returns a new poseidon2 permutation instance
+// using the given parameters.
+func NewPermutationWithParameters(p *Parameters) *Permutation {
+	return &Permutation{params: p}
+}
+
+// Params returns the parameters of the permutation.
+func (h *Permutation) Params() *Parameters {
+	return h.params
+}
+
+// sBox applies the sBox on buffer[index]
+func (h *Permutation) sBox(index int, input []fr.Element) {
+	var tmp fr.Element
+	tmp.Set(&input[index])
+	// sbox degree 5
+	input[index].Square(&input[index]).
+		Square(&input[index]).
+		Mul(&input[index], &tmp)
+}
+
+// matMulM4 computes
+// s <- M4*s
+// where M4=
+// (5 7 1 3)
+// (4 6 1 1)
+// (1 3 5 7)
+// (1 1 4 6)
+// on chunks of 4 elemts on each part of the buffer
+// see https://eprint.iacr.org/2023/323.pdf appendix B for the addition chain
+func (h *Permutation) matMulM4InPlace(s []fr.Element) {
+	c := len(s) / 4
+	for i := 0; i < c; i++ {
+		var t0, t1, t2, t3, t4, t5, t6, t7 fr.Element
+		t0.Add(&s[4*i], &s[4*i+1])               // s0+s1
+		t1.Add(&s[4*i+2], &s[4*i+3])             // s2+s3
+		t2.Double(&s[4*i+1]).Add(&t2, &t1)       // 2s1+t1
+		t3.Double(&s[4*i+3]).Add(&t3, &t0)       // 2s3+t0
+		t4.Double(&t1).Double(&t4).Add(&t4, &t3) // 4t1+t3
+		t5.Double(&t0).Double(&t5).Add(&t5, &t2) // 4t0+t2
+		t6.Add(&t3, &t5)                         // t3+t5
+		t7.Add(&t2, &t4)                         // t2+t4
+		s[4*i].Set(&t6)
+		s[4*i+1].Set(&t5)
+		s[4*i+2].Set(&t7)
+		s[4*i+3].Set(&t4)
+	}
+}
+
+// when Width = 0 mod 4, the buffer is multiplied by circ(2M4,M4,..,M4)
+// see https://eprint.iacr.org/2023/323.pdf
+//
+// when Width = 2,3 the buffer is multiplied by circ(2,1) and circ(2,1,1)
+// see https://eprint.iacr.org/2023/323.pdf page 15, case t=2,3
+func (h *Permutation) matMulExternalInPlace(input []fr.Element) {
+
+	if h.params.Width == 2 || h.params.Width == 3 {
+		var tmp fr.Element
+		for i := range input {
+			tmp.Add(&tmp, &input[i])
+		}
+		for i := range input {
+			input[i].Add(&input[i], &tmp)
+		}
+		return
+	}
+
+	// at this stage t is supposed to be a multiple of 4
+	// the MDS matrix is circ(2M4,M4,..,M4)
+	h.matMulM4InPlace(input)
+	tmp := make([]fr.Element, 4)
+	for i := 0; i < h.params.Width/4; i++ {
+		tmp[0].Add(&tmp[0], &input[4*i])
+		tmp[1].Add(&tmp[1], &input[4*i+1])
+		tmp[2].Add(&tmp[2], &input[4*i+2])
+		tmp[3].Add(&tmp[3], &input[4*i+3])
+	}
+	for i := 0; i < h.params.Width/4; i++ {
+		input[4*i].Add(&input[4*i], &tmp[0])
+		input[4*i+1].Add(&input[4*i+1], &tmp[1])
+		input[4*i+2].Add(&input[4*i+2], &tmp[2])
+		input[4*i+3].Add(&input[4*i+3], &tmp[3])
+	}
+}
+
+// when Width = 2,3 the matrix are respectively [[2,1][1,3]] and [[2,1,1][1,2,1][1,1,3]]
+// otherwise the matrix is 𝟙 + diag(D), where D is derived from the parameters seed.
+func (h *Permutation) matMulInternalInPlace(input []fr.Element) {
+	var sum fr.Element
+	for i := range input {
+		sum.Add(&sum, &input[i])
+	}
+	switch h.params.Width {
+	case 2, 3:
+		// the last diagonal coefficient is 2, the others 1
+		last := len(input) - 1
+		input[last].Double(&input[last])
+		for i := range input {
+			input[i].Add(&input[i], &sum)
+		}
+	default:
+		for i := range input {
+			input[i].Mul(&input[i], &h.params.diagInternalMatrix[i]).
+				Add(&input[i], &sum)
+		}
+	}
+}
+
+// addRoundKeyInPlace adds the round-th key to the buffer
+func (h *Permutation) addRoundKeyInPlace(round int, input []fr.Element) {
+	for i := 0; i < len(h.params.RoundKeys[round]); i++ {
+		input[i].Add(&input[i], &h.params.RoundKeys[round][i])
+	}
+}
+
+// Permutation applies the permutation on input, and stores the result in input.
+func (h *Permutation) Permutation(input []fr.Element) error {
+	if len(input) != h.params.Width {
+		return ErrInvalidSizebuffer
+	}
+
+	// external matrix multiplication, cf https://eprint.iacr.org/2023/323.pdf page 14 (part 6)
+	h.matMulExternalInPlace(input)
+
+	rf := h.params.NbFullRounds / 2
+	for i := 0; i < rf; i++ {
+		// one round = matMulExternal(sBox_Full(addRoundKey))
+		h.addRoundKeyInPlace(i, input)
+		for j := 0; j < h.params.Width; j++ {
+			h.sBox(j, input)
+		}
+		h.matMulExternalInPlace(input)
+	}
+
+	for i := rf; i < rf+h.params.NbPartialRounds; i++ {
+		// one round = matMulInternal(sBox_sparse(addRoundKey))
+		h.addRoundKeyInPlace(i, input)
+		h.sBox(0, input)
+		h.matMulInternalInPlace(input)
+	}
+	for i := rf + h.params.NbPartialRounds; i < h.params.NbFullRounds+h.params.NbPartialRounds; i++ {
+		// one round = matMulExternal(sBox_Full(addRoundKey))
+		h.addRoundKeyInPlace(i, input)
+		for j := 0; j < h.params.Width; j++ {
+			h.sBox(j, input)
+		}
+		h.matMulExternalInPlace(input)
+	}
+
+	return nil
+}
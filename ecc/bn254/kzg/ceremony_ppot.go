@@ -0,0 +1,320 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// This file implements the challenge format of the perpetual powers of tau
+// ceremony, see https://github.com/privacy-scaling-explorations/perpetualpowersoftau.
+// A challenge file of power n is the BLAKE2b hash of the previous response,
+// followed by the uncompressed points
+//
+//	[τⁱ]G₁ for i < 2ⁿ⁺¹-1, [τⁱ]G₂, [ατⁱ]G₁ and [βτⁱ]G₁ for i < 2ⁿ, and [β]G₂
+//
+// The coordinates are encoded in big-endian, the G2 ones as (c1, c0), and the
+// point at infinity has the second most significant bit of the first byte set.
+
+const (
+	ppotHashSize = 64
+	ppotG1Size   = 2 * fp.Bytes
+	ppotG2Size   = 4 * fp.Bytes
+)
+
+var ErrInvalidPerpetualPowersOfTau = errors.New("invalid perpetual powers of tau")
+
+// PerpetualPowersOfTau is a challenge of the perpetual powers of tau ceremony.
+// Use ReadPerpetualPowersOfTau to read only the first powers of a large
+// challenge.
+//
+// implements io.ReaderFrom and io.WriterTo
+type PerpetualPowersOfTau struct {
+	Hash       [ppotHashSize]byte
+	TauG1      []bn254.G1Affine
+	TauG2      []bn254.G2Affine
+	AlphaTauG1 []bn254.G1Affine
+	BetaTauG1  []bn254.G1Affine
+	BetaG2     bn254.G2Affine
+}
+
+// NewPerpetualPowersOfTau returns a challenge of power n, to read with ReadFrom.
+func NewPerpetualPowersOfTau(n int) *PerpetualPowersOfTau {
+	return &PerpetualPowersOfTau{
+		TauG1:      make([]bn254.G1Affine, (1<<(n+1))-1),
+		TauG2:      make([]bn254.G2Affine, 1<<n),
+		AlphaTauG1: make([]bn254.G1Affine, 1<<n),
+		BetaTauG1:  make([]bn254.G1Affine, 1<<n),
+	}
+}
+
+// PowersOfTau returns the first nG1 and nG2 powers of tau of the challenge.
+func (c *PerpetualPowersOfTau) PowersOfTau(nG1, nG2 int) (*PowersOfTau, error) {
+	if nG1 > len(c.TauG1) || nG2 > len(c.TauG2) {
+		return nil, ErrInvalidPerpetualPowersOfTau
+	}
+	if nG1 < 2 || nG2 < 2 {
+		return nil, ErrMinSRSSize
+	}
+	p := &PowersOfTau{
+		G1: make([]bn254.G1Affine, nG1),
+		G2: make([]bn254.G2Affine, nG2),
+	}
+	copy(p.G1, c.TauG1)
+	copy(p.G2, c.TauG2)
+	return p, nil
+}
+
+// WriteTo writes the challenge in the perpetual powers of tau format
+func (c *PerpetualPowersOfTau) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	n, err := bw.Write(c.Hash[:])
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+	writeG1 := func(points []bn254.G1Affine) error {
+		for i := range points {
+			n, err := bw.Write(ppotG1Bytes(&points[i]))
+			written += int64(n)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	writeG2 := func(points []bn254.G2Affine) error {
+		for i := range points {
+			n, err := bw.Write(ppotG2Bytes(&points[i]))
+			written += int64(n)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := writeG1(c.TauG1); err != nil {
+		return written, err
+	}
+	if err := writeG2(c.TauG2); err != nil {
+		return written, err
+	}
+	if err := writeG1(c.AlphaTauG1); err != nil {
+		return written, err
+	}
+	if err := writeG1(c.BetaTauG1); err != nil {
+		return written, err
+	}
+	if err := writeG2([]bn254.G2Affine{c.BetaG2}); err != nil {
+		return written, err
+	}
+	return written, bw.Flush()
+}
+
+// ReadFrom reads a challenge in the perpetual powers of tau format, of the
+// power given to NewPerpetualPowersOfTau. The points are checked to be in the
+// prime order subgroups.
+func (c *PerpetualPowersOfTau) ReadFrom(r io.Reader) (int64, error) {
+	br := bufio.NewReader(r)
+	var read int64
+	n, err := io.ReadFull(br, c.Hash[:])
+	read += int64(n)
+	if err != nil {
+		return read, err
+	}
+	n, err = readPPoTG1(br, c.TauG1)
+	read += int64(n)
+	if err != nil {
+		return read, err
+	}
+	n, err = readPPoTG2(br, c.TauG2)
+	read += int64(n)
+	if err != nil {
+		return read, err
+	}
+	n, err = readPPoTG1(br, c.AlphaTauG1)
+	read += int64(n)
+	if err != nil {
+		return read, err
+	}
+	n, err = readPPoTG1(br, c.BetaTauG1)
+	read += int64(n)
+	if err != nil {
+		return read, err
+	}
+	betaG2 := make([]bn254.G2Affine, 1)
+	n, err = readPPoTG2(br, betaG2)
+	read += int64(n)
+	c.BetaG2 = betaG2[0]
+	return read, err
+}
+
+// ReadPerpetualPowersOfTau reads the first nG1 and nG2 powers of tau of a
+// challenge of power n in the perpetual powers of tau format, skipping the
+// rest of the G1 powers. The points are checked to be in the prime order
+// subgroups, see (*PowersOfTau).Verify for the other checks.
+func ReadPerpetualPowersOfTau(r io.Reader, n, nG1, nG2 int) (*PowersOfTau, error) {
+	if nG1 > (1<<(n+1))-1 || nG2 > 1<<n {
+		return nil, ErrInvalidPerpetualPowersOfTau
+	}
+	if nG1 < 2 || nG2 < 2 {
+		return nil, ErrMinSRSSize
+	}
+	br := bufio.NewReader(r)
+	if _, err := br.Discard(ppotHashSize); err != nil {
+		return nil, err
+	}
+	p := &PowersOfTau{
+		G1: make([]bn254.G1Affine, nG1),
+		G2: make([]bn254.G2Affine, nG2),
+	}
+	if _, err := readPPoTG1(br, p.G1); err != nil {
+		return nil, err
+	}
+	if _, err := io.CopyN(io.Discard, br, int64((1<<(n+1))-1-nG1)*ppotG1Size); err != nil {
+		return nil, err
+	}
+	if _, err := readPPoTG2(br, p.G2); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func readPPoTG1(r io.Reader, points []bn254.G1Affine) (int, error) {
+	var buf [ppotG1Size]byte
+	read := 0
+	for i := range points {
+		n, err := io.ReadFull(r, buf[:])
+		read += n
+		if err != nil {
+			return read, err
+		}
+		if err = ppotSetG1Bytes(&points[i], buf[:]); err != nil {
+			return read, err
+		}
+	}
+	return read, nil
+}
+
+func readPPoTG2(r io.Reader, points []bn254.G2Affine) (int, error) {
+	var buf [ppotG2Size]byte
+	read := 0
+	for i := range points {
+		n, err := io.ReadFull(r, buf[:])
+		read += n
+		if err != nil {
+			return read, err
+		}
+		if err = ppotSetG2Bytes(&points[i], buf[:]); err != nil {
+			return read, err
+		}
+	}
+	return read, nil
+}
+
+func ppotG1Bytes(p *bn254.G1Affine) []byte {
+	res := make([]byte, ppotG1Size)
+	if p.IsInfinity() {
+		res[0] = ppotInfinity
+		return res
+	}
+	for i, e := range []*fp.Element{&p.X, &p.Y} {
+		b := e.Bytes()
+		copy(res[i*fp.Bytes:], b[:])
+	}
+	return res
+}
+
+func ppotG2Bytes(p *bn254.G2Affine) []byte {
+	res := make([]byte, ppotG2Size)
+	if p.IsInfinity() {
+		res[0] = ppotInfinity
+		return res
+	}
+	for i, e := range []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0} {
+		b := e.Bytes()
+		copy(res[i*fp.Bytes:], b[:])
+	}
+	return res
+}
+
+// ppotInfinity is the flag of the point at infinity; the most significant bit
+// (compressed point) must be unset.
+const (
+	ppotInfinity = 0b01 << 6
+	ppotFlags    = 0b11 << 6
+)
+
+func ppotSetG1Bytes(p *bn254.G1Affine, buf []byte) error {
+	if ok, err := ppotCheckInfinity(buf); ok || err != nil {
+		p.X.SetZero()
+		p.Y.SetZero()
+		return err
+	}
+	for i, e := range []*fp.Element{&p.X, &p.Y} {
+		if err := e.SetBytesCanonical(buf[i*fp.Bytes : (i+1)*fp.Bytes]); err != nil {
+			return err
+		}
+	}
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		return ErrInvalidPerpetualPowersOfTau
+	}
+	return nil
+}
+
+func ppotSetG2Bytes(p *bn254.G2Affine, buf []byte) error {
+	if ok, err := ppotCheckInfinity(buf); ok || err != nil {
+		p.X.SetZero()
+		p.Y.SetZero()
+		return err
+	}
+	for i, e := range []*fp.Element{&p.X.A1, &p.X.A0, &p.Y.A1, &p.Y.A0} {
+		if err := e.SetBytesCanonical(buf[i*fp.Bytes : (i+1)*fp.Bytes]); err != nil {
+			return err
+		}
+	}
+	if !p.IsOnCurve() || !p.IsInSubGroup() {
+		return ErrInvalidPerpetualPowersOfTau
+	}
+	return nil
+}
+
+// ppotCheckInfinity returns true if buf encodes the point at infinity, and an
+// error if the flags are invalid.
+func ppotCheckInfinity(buf []byte) (bool, error) {
+	switch buf[0] & ppotFlags {
+	case 0:
+		return false, nil
+	case ppotInfinity:
+		if buf[0] != ppotInfinity {
+			return false, ErrInvalidPerpetualPowersOfTau
+		}
+		for _, b := range buf[1:] {
+			if b != 0 {
+				return false, ErrInvalidPerpetualPowersOfTau
+			}
+		}
+		return true, nil
+	default:
+		return false, ErrInvalidPerpetualPowersOfTau
+	}
+}
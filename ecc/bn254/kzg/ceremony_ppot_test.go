@@ -0,0 +1,162 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestPerpetualPowersOfTau returns a challenge of power n with known τ, α
+// and β.
+func newTestPerpetualPowersOfTau(n int) *PerpetualPowersOfTau {
+	c := NewPerpetualPowersOfTau(n)
+	_, _, gen1Aff, gen2Aff := bn254.Generators()
+	tau, alpha, beta := big.NewInt(11), big.NewInt(13), big.NewInt(17)
+	var e big.Int
+	for i := range c.TauG1 {
+		e.Exp(tau, big.NewInt(int64(i)), nil)
+		c.TauG1[i].ScalarMultiplication(&gen1Aff, &e)
+	}
+	for i := range c.TauG2 {
+		e.Exp(tau, big.NewInt(int64(i)), nil)
+		c.TauG2[i].ScalarMultiplication(&gen2Aff, &e)
+		c.AlphaTauG1[i].ScalarMultiplication(&c.TauG1[i], alpha)
+		c.BetaTauG1[i].ScalarMultiplication(&c.TauG1[i], beta)
+	}
+	c.BetaG2.ScalarMultiplication(&gen2Aff, beta)
+	for i := range c.Hash {
+		c.Hash[i] = byte(i)
+	}
+	return c
+}
+
+func TestPerpetualPowersOfTau(t *testing.T) {
+	const n = 3
+	c := newTestPerpetualPowersOfTau(n)
+
+	var buf bytes.Buffer
+	written, err := c.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(ppotHashSize+((1<<(n+1))-1+2*(1<<n))*ppotG1Size+((1<<n)+1)*ppotG2Size), written)
+	data := buf.Bytes()
+
+	// the generator of G1 is (1, 2)
+	assert.Equal(t,
+		"0000000000000000000000000000000000000000000000000000000000000001"+
+			"0000000000000000000000000000000000000000000000000000000000000002",
+		hex.EncodeToString(data[ppotHashSize:ppotHashSize+ppotG1Size]))
+
+	d := NewPerpetualPowersOfTau(n)
+	read, err := d.ReadFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, written, read)
+	assert.Equal(t, c, d)
+
+	// the first powers
+	p, err := ReadPerpetualPowersOfTau(bytes.NewReader(data), n, 5, 3)
+	require.NoError(t, err)
+	require.NoError(t, p.Verify())
+	q, err := c.PowersOfTau(5, 3)
+	require.NoError(t, err)
+	assert.Equal(t, q, p)
+
+	// the powers can be extended by a new ceremony
+	_, err = p.Contribute()
+	require.NoError(t, err)
+	require.NoError(t, p.Verify())
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ReadPerpetualPowersOfTau(bytes.NewReader(data), n, 1<<(n+1), 3)
+		assert.ErrorIs(t, err, ErrInvalidPerpetualPowersOfTau)
+		_, err = ReadPerpetualPowersOfTau(bytes.NewReader(data[:ppotHashSize+10*ppotG1Size]), n, 5, 3)
+		assert.Error(t, err)
+
+		// point not on the curve
+		bad := append([]byte(nil), data...)
+		bad[ppotHashSize+ppotG1Size-1] ^= 1
+		_, err = ReadPerpetualPowersOfTau(bytes.NewReader(bad), n, 5, 3)
+		assert.ErrorIs(t, err, ErrInvalidPerpetualPowersOfTau)
+
+		// compressed flag
+		bad = append([]byte(nil), data...)
+		bad[ppotHashSize] |= 0x80
+		_, err = ReadPerpetualPowersOfTau(bytes.NewReader(bad), n, 5, 3)
+		assert.ErrorIs(t, err, ErrInvalidPerpetualPowersOfTau)
+	})
+
+	t.Run("infinity", func(t *testing.T) {
+		var inf bn254.G1Affine
+		b := ppotG1Bytes(&inf)
+		assert.Equal(t, byte(0x40), b[0])
+		var p bn254.G1Affine
+		require.NoError(t, ppotSetG1Bytes(&p, b))
+		assert.True(t, p.IsInfinity())
+		b[3] = 1
+		assert.ErrorIs(t, ppotSetG1Bytes(&p, b), ErrInvalidPerpetualPowersOfTau)
+	})
+}
+
+// TestPerpetualPowersOfTauInitialChallenge reads the challenge of power 2
+// written by the `new` command of the ceremony, before any contribution: the
+// hash is BLAKE2b of the empty string and all the powers are the generators.
+func TestPerpetualPowersOfTauInitialChallenge(t *testing.T) {
+	const n = 2
+	data, err := os.ReadFile("testdata/ppot_challenge_power_2")
+	require.NoError(t, err)
+
+	c := NewPerpetualPowersOfTau(n)
+	read, err := c.ReadFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), read)
+	assert.Equal(t,
+		"786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419"+
+			"d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce",
+		hex.EncodeToString(c.Hash[:]))
+
+	_, _, gen1Aff, gen2Aff := bn254.Generators()
+	for _, points := range [][]bn254.G1Affine{c.TauG1, c.AlphaTauG1, c.BetaTauG1} {
+		for i := range points {
+			assert.True(t, points[i].Equal(&gen1Aff))
+		}
+	}
+	for _, p := range append(c.TauG2, c.BetaG2) {
+		assert.True(t, p.Equal(&gen2Aff))
+	}
+
+	var buf bytes.Buffer
+	_, err = c.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, data, buf.Bytes())
+
+	// the powers are those of a new ceremony, which can be continued
+	p, err := ReadPerpetualPowersOfTau(bytes.NewReader(data), n, 7, 4)
+	require.NoError(t, err)
+	initial, err := NewPowersOfTau(7, 4)
+	require.NoError(t, err)
+	assert.Equal(t, initial, p)
+	contribution, err := p.Contribute()
+	require.NoError(t, err)
+	require.NoError(t, VerifyContributions(p, []Contribution{contribution}))
+}
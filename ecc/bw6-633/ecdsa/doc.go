@@ -21,6 +21,10 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 //
+// BatchVerify verifies a batch of signatures, split across the CPUs: a plain
+// ECDSA signature only holds the x-coordinate of R, so the verification
+// equations can't be combined.
+//
 // Documentation:
 // - Wikipedia: https://en.wikipedia.org/wiki/Elliptic_Curve_Digital_Signature_Algorithm
 // - FIPS 186-4: https://nvlpubs.nist.gov/nistpubs/FIPS/NIST.FIPS.186-4.pdf
@@ -17,7 +17,9 @@
 package ecdsa
 
 import (
+	"bytes"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/subtle"
 	"testing"
 
@@ -62,3 +64,71 @@ func TestSerialization(t *testing.T) {
 
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+
+func TestSignatureDER(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("testing DER")
+	hFunc := sha256.New()
+	for i := 0; i < 10; i++ {
+		sigBin, _ := privKey.Sign(msg, hFunc)
+		var sig, decoded Signature
+		if _, err := sig.SetBytes(sigBin); err != nil {
+			t.Fatal(err)
+		}
+		der := sig.BytesDER()
+		n, err := decoded.SetBytesDER(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != len(der) || decoded != sig {
+			t.Fatal("DER round trip failed")
+		}
+
+		// trailing data
+		if _, err := decoded.SetBytesDER(append(der, 0)); err != errInvalidDER {
+			t.Fatal("expected an error with trailing data")
+		}
+	}
+
+	// non minimal integer: r = 1 encoded on 2 bytes
+	nonMinimal := []byte{0x30, 0x07, 0x02, 0x02, 0x00, 0x01, 0x02, 0x01, 0x01}
+	var sig Signature
+	if _, err := sig.SetBytesDER(nonMinimal); err != errInvalidDER {
+		t.Fatal("expected an error for a non minimal integer")
+	}
+	// r = 0
+	zero := []byte{0x30, 0x06, 0x02, 0x01, 0x00, 0x02, 0x01, 0x01}
+	if _, err := sig.SetBytesDER(zero); err != errZero {
+		t.Fatal("expected an error for r = 0")
+	}
+}
+
+func TestSignatureLowS(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("testing low-s")
+	hFunc := sha256.New()
+	for i := 0; i < 20; i++ {
+		sigBin, _ := privKey.Sign(msg, hFunc)
+		var sig Signature
+		if _, err := sig.SetBytes(sigBin); err != nil {
+			t.Fatal(err)
+		}
+		wasLow := sig.IsLowS()
+		if sig.NormalizeS() == wasLow {
+			t.Fatal("NormalizeS should only change high-s signatures")
+		}
+		if !sig.IsLowS() {
+			t.Fatal("normalized signature should be low-s")
+		}
+		if !wasLow && bytes.Equal(sig.Bytes(), sigBin) {
+			t.Fatal("normalized signature should differ")
+		}
+		if ok, _ := privKey.PublicKey.Verify(sig.Bytes(), msg, hFunc); !ok {
+			t.Fatal("normalized signature should verify")
+		}
+	}
+}
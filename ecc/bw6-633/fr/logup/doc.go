@@ -0,0 +1,30 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package logup provides an API to build lookup proofs based on the
+// logarithmic derivative (logUp) argument.
+//
+// To prove that the entries of f are entries of t, the prover commits to the
+// multiplicities m, where m(ωʲ) is the number of times the j-th entry of t is
+// looked up, and shows that, for a random β,
+//
+//	Σᵢ 1/(β-f(ωⁱ)) = Σⱼ m(ωʲ)/(β-t(ωʲ))
+//
+// The identity is proven with an accumulating sum polynomial (see
+// iop.BuildRatioLogDerivative) and a quotient by Xⁿ-1, committed with KZG.
+// Contrary to plookup, no sorted vector is needed and an entry of t can be
+// looked up any number of times.
+package logup
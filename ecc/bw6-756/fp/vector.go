@@ -37,6 +37,10 @@ import (
 //   - encoding.BinaryMarshaler
 //   - encoding.BinaryUnmarshaler
 //   - sort.Interface
+//
+// The arithmetic methods are written in Go, except Add and Sub which use
+// assembly on amd64 when the modulus fits in 4 words. ScalarMul, Mul, Sum and
+// InnerProduct split long vectors across the CPUs instead.
 type Vector []Element
 
 // MarshalBinary implements encoding.BinaryMarshaler
@@ -207,6 +211,126 @@ func (vector Vector) Swap(i, j int) {
 	vector[i], vector[j] = vector[j], vector[i]
 }
 
+// Add adds two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Add(a, b Vector) {
+	addVecGeneric(*vector, a, b)
+}
+
+// Sub subtracts two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Sub(a, b Vector) {
+	subVecGeneric(*vector, a, b)
+}
+
+// ScalarMul multiplies a vector by a scalar element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) ScalarMul(a Vector, b *Element) {
+	scalarMulVecGeneric(*vector, a, b)
+}
+
+// Mul multiplies two vectors element-wise and stores the result in self.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Mul(a, b Vector) {
+	mulVecGeneric(*vector, a, b)
+}
+
+// Sum computes the sum of all elements in the vector.
+func (vector *Vector) Sum() (res Element) {
+	sumVecGeneric(&res, *vector)
+	return
+}
+
+// InnerProduct computes the inner product of two vectors.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) InnerProduct(other Vector) (res Element) {
+	innerProductVecGeneric(&res, *vector, other)
+	return
+}
+
+func addVecGeneric(res, a, b Vector) {
+	if len(a) != len(b) || len(a) != len(res) {
+		panic("vector.Add: vectors don't have the same length")
+	}
+	for i := 0; i < len(a); i++ {
+		res[i].Add(&a[i], &b[i])
+	}
+}
+
+func subVecGeneric(res, a, b Vector) {
+	if len(a) != len(b) || len(a) != len(res) {
+		panic("vector.Sub: vectors don't have the same length")
+	}
+	for i := 0; i < len(a); i++ {
+		res[i].Sub(&a[i], &b[i])
+	}
+}
+
+// minParallelLen is the length from which ScalarMul, Mul, Sum and InnerProduct
+// split the work across the CPUs; below it the goroutines cost more than they save.
+const minParallelLen = 1 << 12
+
+// executeVec runs work on [0, n), in parallel if n ≥ minParallelLen.
+func executeVec(n int, work func(int, int)) {
+	if n < minParallelLen {
+		work(0, n)
+		return
+	}
+	execute(n, work)
+}
+
+func scalarMulVecGeneric(res, a Vector, b *Element) {
+	if len(a) != len(res) {
+		panic("vector.ScalarMul: vectors don't have the same length")
+	}
+	executeVec(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].Mul(&a[i], b)
+		}
+	})
+}
+
+func mulVecGeneric(res, a, b Vector) {
+	if len(a) != len(b) || len(a) != len(res) {
+		panic("vector.Mul: vectors don't have the same length")
+	}
+	executeVec(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].Mul(&a[i], &b[i])
+		}
+	})
+}
+
+func sumVecGeneric(res *Element, a Vector) {
+	var lock sync.Mutex
+	executeVec(len(a), func(start, end int) {
+		var partial Element
+		for i := start; i < end; i++ {
+			partial.Add(&partial, &a[i])
+		}
+		lock.Lock()
+		res.Add(res, &partial)
+		lock.Unlock()
+	})
+}
+
+func innerProductVecGeneric(res *Element, a, b Vector) {
+	if len(a) != len(b) {
+		panic("vector.InnerProduct: vectors don't have the same length")
+	}
+	var lock sync.Mutex
+	executeVec(len(a), func(start, end int) {
+		var partial, tmp Element
+		for i := start; i < end; i++ {
+			tmp.Mul(&a[i], &b[i])
+			partial.Add(&partial, &tmp)
+		}
+		lock.Lock()
+		res.Add(res, &partial)
+		lock.Unlock()
+	})
+}
+
 // TODO @gbotrel make a public package out of that.
 // execute executes the work function in parallel.
 // this is copy paste from internal/parallel/parallel.go
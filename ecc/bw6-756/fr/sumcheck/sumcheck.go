@@ -65,9 +65,13 @@ func setupTranscript(claimsNum int, varsNum int, settings *fiatshamir.Settings)
 	for i := 0; i < varsNum; i++ {
 		challengeNames[i+numChallenges-varsNum] = prefix + strconv.Itoa(i)
 	}
+	if settings.Transcript != nil && settings.Duplex != nil {
+		return nil, fiatshamir.ErrTranscriptAndDuplex
+	}
 	if settings.Transcript == nil {
-		transcript := fiatshamir.NewTranscript(settings.Hash, challengeNames...)
-		settings.Transcript = transcript
+		if settings.Transcript, err = settings.NewTranscript(challengeNames...); err != nil {
+			return
+		}
 	}
 
 	for i := range settings.BaseChallenges {
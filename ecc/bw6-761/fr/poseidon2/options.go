@@ -0,0 +1,78 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+)
+
+// Option defines option for altering the behavior of the Poseidon2 hasher.
+// See the descriptions of functions returning instances of this type for
+// particular options.
+type Option func(*poseidon2Config)
+
+type poseidon2Config struct {
+	byteOrder       fr.ByteOrder
+	width           int
+	capacity        int
+	nbFullRounds    int
+	nbPartialRounds int
+}
+
+// default options
+func poseidon2Options(opts ...Option) poseidon2Config {
+	// apply options
+	opt := poseidon2Config{
+		byteOrder:       fr.BigEndian,
+		width:           DefaultWidth,
+		capacity:        DefaultCapacity,
+		nbFullRounds:    DefaultNbFullRounds,
+		nbPartialRounds: DefaultNbPartialRounds,
+	}
+	for _, option := range opts {
+		option(&opt)
+	}
+	return opt
+}
+
+// WithByteOrder sets the byte order used to decode the input
+// in the Write method. Default is BigEndian.
+func WithByteOrder(byteOrder fr.ByteOrder) Option {
+	return func(opt *poseidon2Config) {
+		opt.byteOrder = byteOrder
+	}
+}
+
+// WithPermutation sets the width and the number of rounds of the
+// underlying permutation. Default is DefaultWidth, DefaultNbFullRounds
+// and DefaultNbPartialRounds.
+func WithPermutation(width, nbFullRounds, nbPartialRounds int) Option {
+	return func(opt *poseidon2Config) {
+		opt.width = width
+		opt.nbFullRounds = nbFullRounds
+		opt.nbPartialRounds = nbPartialRounds
+	}
+}
+
+// WithCapacity sets the capacity of the sponge, in number of field elements.
+// The rate is then width-capacity, and the digest consists of capacity field
+// elements. Default is DefaultCapacity.
+func WithCapacity(capacity int) Option {
+	return func(opt *poseidon2Config) {
+		opt.capacity = capacity
+	}
+}
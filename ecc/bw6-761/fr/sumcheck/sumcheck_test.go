@@ -159,3 +159,25 @@ func TestSumcheckDeterministicHashSingleClaimMultilin(t *testing.T) {
 		}
 	}
 }
+
+func TestSumcheckDuplex(t *testing.T) {
+	poly := make(polynomial.MultiLin, 8)
+	for i := range poly {
+		poly[i].SetUint64(uint64(i + 1))
+	}
+	hashGen := test_vector_utils.NewMessageCounterGenerator(1, 1)
+
+	claim := singleMultilinClaim{g: poly.Clone()}
+	proof, err := Prove(&claim, fiatshamir.WithDuplex(fiatshamir.NewDuplex(hashGen(), []byte("sumcheck"))))
+	assert.NoError(t, err)
+
+	lazyClaim := singleMultilinLazyClaim{g: poly, claimedSum: poly.Sum()}
+	assert.NoError(t, Verify(lazyClaim, proof, fiatshamir.WithDuplex(fiatshamir.NewDuplex(hashGen(), []byte("sumcheck")))))
+
+	// a transcript and a duplex can't be used together
+	settings := fiatshamir.WithDuplex(fiatshamir.NewDuplex(hashGen(), []byte("sumcheck")))
+	settings.Transcript = fiatshamir.NewTranscript(hashGen(), "comb")
+	claim = singleMultilinClaim{g: poly.Clone()}
+	_, err = Prove(&claim, settings)
+	assert.ErrorIs(t, err, fiatshamir.ErrTranscriptAndDuplex)
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+	"hash"
+	"math/big"
+)
+
+var (
+	errBatchSize  = errors.New("the numbers of public keys, signatures and messages differ")
+	errNotOnCurve = errors.New("public key is not on the curve")
+)
+
+// sizeRandomizer is the size in bytes of the random coefficients of the
+// batch verification, so that an invalid batch passes with probability 2⁻¹²⁸.
+const sizeRandomizer = 16
+
+// BatchVerify verifies the signatures sigs[i] of messages msgs[i] for the
+// public keys pubs[i] at once. It returns true and -1 if all the signatures
+// are valid, and false and the index of the first invalid signature otherwise.
+//
+// sigs[i] is either a signature r||s (see Signature), or a recoverable
+// signature r||s||v (see RecoverableSignature). A recoverable signature is
+// valid if it is valid for Verify and v is its recovery information.
+//
+// A plain ECDSA signature only contains the x-coordinate r of the point R, so
+// its verification equation can't be combined with the others: such
+// signatures are verified one by one, split across the CPUs.
+// The recovery information v gives Rᵢ, and the equations of the recoverable
+// signatures sᵢRᵢ = mᵢ⋅Base + rᵢ⋅Pᵢ are checked with a random linear
+// combination
+//
+//	∑ zᵢsᵢRᵢ - (∑ zᵢmᵢ)⋅Base - ∑ zᵢrᵢPᵢ = 0
+//
+// computed with a single multi-scalar multiplication. If the check fails, the
+// recoverable signatures are verified one by one to find the invalid one. To
+// benefit from the batch verification, sign with SignForRecover.
+func BatchVerify(pubs []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) (bool, int, error) {
+	if len(pubs) != len(sigs) || len(pubs) != len(msgs) {
+		return false, -1, errBatchSize
+	}
+	n := len(pubs)
+	if n == 0 {
+		return true, -1, nil
+	}
+
+	// decode the signatures and hash the messages first: hFunc can't be
+	// shared between go routines.
+	decoded := make([]RecoverableSignature, n)
+	isPlain := make([]bool, n)
+	digests := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		if !pubs[i].A.IsInSubGroup() {
+			return false, i, errNotOnCurve
+		}
+		if len(sigs[i]) == sizeRecoverableSignature {
+			if _, err := decoded[i].SetBytes(sigs[i]); err != nil {
+				return false, i, err
+			}
+		} else {
+			if _, err := decoded[i].Signature.SetBytes(sigs[i]); err != nil {
+				return false, i, err
+			}
+			isPlain[i] = true
+		}
+		d, err := messageDigest(msgs[i], hFunc)
+		if err != nil {
+			return false, i, err
+		}
+		digests[i] = d
+	}
+
+	valid := make([]bool, n)
+	if batchVerifyRecoverable(pubs, decoded, isPlain, digests) {
+		for i := range valid {
+			valid[i] = !isPlain[i]
+		}
+	}
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			if valid[i] {
+				continue
+			}
+			if !isPlain[i] {
+				valid[i] = verifyRecoverable(&pubs[i], &decoded[i], digests[i])
+				continue
+			}
+			// the signature and the digest are already checked
+			valid[i], _ = pubs[i].Verify(sigs[i], digests[i], nil)
+		}
+	})
+
+	for i := range valid {
+		if !valid[i] {
+			return false, i, nil
+		}
+	}
+	return true, -1, nil
+}
+
+// batchVerifyRecoverable checks the random linear combination of the
+// verification equations of the recoverable signatures (isPlain[i] == false).
+// It returns false if at least one of them is invalid, or if there are none.
+func batchVerifyRecoverable(pubs []PublicKey, sigs []RecoverableSignature, isPlain []bool, digests [][]byte) bool {
+	n := 0
+	for i := range isPlain {
+		if !isPlain[i] {
+			n++
+		}
+	}
+	if n == 0 {
+		return false
+	}
+
+	// the points are Base, then Rᵢ, then Pᵢ
+	points := make([]secp256k1.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	_, points[0] = secp256k1.Generators()
+
+	var randomizer [sizeRandomizer]byte
+	var z, t fr.Element
+	j := 0
+	for i := range sigs {
+		if isPlain[i] {
+			continue
+		}
+		if !recoverR(&points[1+j], &sigs[i]) {
+			return false
+		}
+		points[1+n+j] = pubs[i].A
+
+		if _, err := rand.Read(randomizer[:]); err != nil {
+			return false
+		}
+		z.SetBytes(randomizer[:])
+
+		scalars[1+j].SetBytes(sigs[i].S[:sizeFr]).Mul(&scalars[1+j], &z)
+		scalars[1+n+j].SetBytes(sigs[i].R[:sizeFr]).Mul(&scalars[1+n+j], &z).Neg(&scalars[1+n+j])
+		t.SetBigInt(HashToInt(digests[i])).Mul(&t, &z)
+		scalars[0].Sub(&scalars[0], &t)
+		j++
+	}
+
+	var res secp256k1.G1Jac
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false
+	}
+	return res.Z.IsZero()
+}
+
+// verifyRecoverable checks that Rᵢ = s⁻¹mᵢ⋅Base + s⁻¹rᵢ⋅Pᵢ, where Rᵢ is given by
+// the recovery information of the signature.
+func verifyRecoverable(pub *PublicKey, sig *RecoverableSignature, digest []byte) bool {
+	var R secp256k1.G1Affine
+	if !recoverR(&R, sig) {
+		return false
+	}
+	var r, s, u1, u2 big.Int
+	r.SetBytes(sig.R[:sizeFr])
+	s.SetBytes(sig.S[:sizeFr])
+	s.ModInverse(&s, order)
+	u1.Mul(HashToInt(digest), &s).Mod(&u1, order)
+	u2.Mul(&r, &s).Mod(&u2, order)
+	var U secp256k1.G1Jac
+	var UAff secp256k1.G1Affine
+	U.JointScalarMultiplicationBase(&pub.A, &u1, &u2)
+	UAff.FromJacobian(&U)
+	return UAff.Equal(&R)
+}
+
+// recoverR sets R to the point of the signature, whose x-coordinate is
+// r + (v>>1)⋅order and the parity of y is v&1 (see SignForRecover). It returns
+// false if there is no such point on the curve.
+func recoverR(R *secp256k1.G1Affine, sig *RecoverableSignature) bool {
+	r := new(big.Int).SetBytes(sig.R[:sizeFr])
+	x := big.NewInt(int64(sig.V >> 1))
+	x.Mul(x, order).Add(x, r)
+	if x.Cmp(fp.Modulus()) >= 0 {
+		return false
+	}
+	P, err := RecoverP(uint(sig.V), r)
+	if err != nil {
+		return false
+	}
+	R.Set(P)
+	return true
+}
+
+// messageDigest returns the message hashed with hFunc if it's not nil, as in
+// Sign and Verify, and the message itself otherwise.
+func messageDigest(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
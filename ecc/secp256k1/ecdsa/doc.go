@@ -21,6 +21,11 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 //
+// The keys can also be used for ECDH key agreement and ECIES encryption.
+//
+// BatchVerify verifies a batch of signatures; the recoverable signatures (see
+// RecoverableSignature) are checked with a single multi-scalar multiplication.
+//
 // Documentation:
 // - Wikipedia: https://en.wikipedia.org/wiki/Elliptic_Curve_Digital_Signature_Algorithm
 // - FIPS 186-4: https://nvlpubs.nist.gov/nistpubs/FIPS/NIST.FIPS.186-4.pdf
@@ -0,0 +1,169 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+)
+
+func TestECDH(t *testing.T) {
+	t.Parallel()
+
+	alice, _ := GenerateKey(rand.Reader)
+	bob, _ := GenerateKey(rand.Reader)
+
+	s1, err := alice.ECDH(&bob.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := bob.ECDH(&alice.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s1, s2) || len(s1) != sizeFp {
+		t.Fatal("ECDH shared secrets differ")
+	}
+
+	k1, _ := alice.SharedKey(&bob.PublicKey, []byte("salt"), []byte("info"), 48)
+	k2, _ := bob.SharedKey(&alice.PublicKey, []byte("salt"), []byte("info"), 48)
+	k3, _ := bob.SharedKey(&alice.PublicKey, []byte("salt"), []byte("other info"), 48)
+	if !bytes.Equal(k1, k2) || len(k1) != 48 {
+		t.Fatal("shared keys differ")
+	}
+	if bytes.Equal(k1, k3) {
+		t.Fatal("shared keys with different contexts should differ")
+	}
+
+	// invalid public key
+	var invalid PublicKey
+	invalid.A.X.SetOne()
+	invalid.A.Y.SetOne()
+	if _, err := alice.ECDH(&invalid); err != errInvalidPublicKey {
+		t.Fatal("expected an error for a point not on the curve")
+	}
+}
+
+func TestECIES(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := GenerateKey(rand.Reader)
+	s1, s2 := []byte("shared info 1"), []byte("shared info 2")
+
+	for _, size := range []int{0, 1, 16, 100} {
+		message := make([]byte, size)
+		rand.Read(message)
+		ciphertext, err := Encrypt(rand.Reader, &privKey.PublicKey, message, s1, s2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ciphertext) != eciesOverhead+size {
+			t.Fatal("wrong ciphertext size")
+		}
+		decrypted, err := privKey.Decrypt(ciphertext, s1, s2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decrypted, message) {
+			t.Fatal("decrypted message differs")
+		}
+
+		// the shared information is authenticated
+		if _, err := privKey.Decrypt(ciphertext, s1, nil); err != errInvalidMessage {
+			t.Fatal("expected an error for a wrong s2")
+		}
+		if _, err := privKey.Decrypt(ciphertext, nil, s2); err != errInvalidMessage {
+			t.Fatal("expected an error for a wrong s1")
+		}
+		// tampered ephemeral key, iv, ciphertext and tag
+		for _, i := range []int{1, eciesPointSize, len(ciphertext) - sha256.Size - 1, len(ciphertext) - 1} {
+			tampered := append([]byte{}, ciphertext...)
+			tampered[i] ^= 1
+			if _, err := privKey.Decrypt(tampered, s1, s2); err == nil {
+				t.Fatalf("expected an error for a tampered byte %d", i)
+			}
+		}
+	}
+
+	// another key can't decrypt
+	other, _ := GenerateKey(rand.Reader)
+	ciphertext, _ := Encrypt(rand.Reader, &privKey.PublicKey, []byte("secret"), nil, nil)
+	if _, err := other.Decrypt(ciphertext, nil, nil); err != errInvalidMessage {
+		t.Fatal("expected an error for a wrong private key")
+	}
+	if _, err := privKey.Decrypt(ciphertext[:eciesOverhead-1], nil, nil); err != errInvalidMessage {
+		t.Fatal("expected an error for a short ciphertext")
+	}
+}
+
+// TestECIESLayout decrypts a ciphertext following the devp2p specification
+// step by step.
+func TestECIESLayout(t *testing.T) {
+	t.Parallel()
+
+	privKey, _ := GenerateKey(rand.Reader)
+	message := []byte("devp2p compatible")
+	ciphertext, err := Encrypt(rand.Reader, &privKey.PublicKey, message, nil, []byte{0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var R PublicKey
+	R.A.X.SetBytes(ciphertext[1 : 1+sizeFp])
+	R.A.Y.SetBytes(ciphertext[1+sizeFp : 1+2*sizeFp])
+	z, err := privKey.ECDH(&R)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kdf := sha256.Sum256(append([]byte{0, 0, 0, 1}, z...))
+	km := sha256.Sum256(kdf[16:])
+	mac := hmac.New(sha256.New, km[:])
+	mac.Write(ciphertext[1+2*sizeFp : len(ciphertext)-32])
+	mac.Write([]byte{0x01, 0x02})
+	if !bytes.Equal(mac.Sum(nil), ciphertext[len(ciphertext)-32:]) {
+		t.Fatal("wrong tag")
+	}
+	block, _ := aes.NewCipher(kdf[:16])
+	iv := ciphertext[1+2*sizeFp : 1+2*sizeFp+16]
+	decrypted := make([]byte, len(message))
+	cipher.NewCTR(block, iv).XORKeyStream(decrypted, ciphertext[1+2*sizeFp+16:len(ciphertext)-32])
+	if !bytes.Equal(decrypted, message) {
+		t.Fatal("wrong encryption")
+	}
+}
+
+func BenchmarkECIES(b *testing.B) {
+	privKey, _ := GenerateKey(rand.Reader)
+	message := make([]byte, 1024)
+	ciphertext, _ := Encrypt(rand.Reader, &privKey.PublicKey, message, nil, nil)
+
+	b.Run("Encrypt", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Encrypt(rand.Reader, &privKey.PublicKey, message, nil, nil)
+		}
+	})
+	b.Run("Decrypt", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			privKey.Decrypt(ciphertext, nil, nil)
+		}
+	})
+}
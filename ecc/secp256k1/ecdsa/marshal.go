@@ -19,17 +19,25 @@ package ecdsa
 import (
 	"crypto/subtle"
 	"errors"
-	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"hash"
 	"io"
 	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
 )
 
 var errWrongSize = errors.New("wrong size buffer")
 var errRBiggerThanRMod = errors.New("r >= r_mod")
 var errSBiggerThanRMod = errors.New("s >= r_mod")
 var errZero = errors.New("zero value")
+var errInvalidDER = errors.New("invalid DER encoding")
+var errInvalidRecoveryID = errors.New("invalid recovery information")
+
+// halfOrder is ⌊order/2⌋, the largest low-s value.
+var halfOrder = new(big.Int).Rsh(order, 1)
 
 // Bytes returns the binary representation of the public key
 // follows https://tools.ietf.org/html/rfc8032#section-3.1
@@ -164,3 +172,126 @@ func (sig *Signature) SetBytes(buf []byte) (int, error) {
 	n += sizeFr
 	return n, nil
 }
+
+// BytesDER returns the DER encoding of sig, as the ASN.1 structure
+//
+//	ECDSA-Sig-Value ::= SEQUENCE { r INTEGER, s INTEGER }
+//
+// used by X.509, HSMs and Bitcoin.
+func (sig *Signature) BytesDER() []byte {
+	r := new(big.Int).SetBytes(sig.R[:])
+	s := new(big.Int).SetBytes(sig.S[:])
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(r)
+		b.AddASN1BigInt(s)
+	})
+	return b.BytesOrPanic()
+}
+
+// SetBytesDER sets sig from its DER encoding (see BytesDER). The encoding
+// must be strict (minimal integers, no trailing data), and r, s must be in
+// [1, order).
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytesDER(buf []byte) (int, error) {
+	r, s := new(big.Int), new(big.Int)
+	input := cryptobyte.String(buf)
+	var inner cryptobyte.String
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Integer(r) ||
+		!inner.ReadASN1Integer(s) ||
+		!inner.Empty() {
+		return 0, errInvalidDER
+	}
+	if r.Sign() <= 0 || s.Sign() <= 0 {
+		return 0, errZero
+	}
+	if r.Cmp(order) >= 0 {
+		return 0, errRBiggerThanRMod
+	}
+	if s.Cmp(order) >= 0 {
+		return 0, errSBiggerThanRMod
+	}
+	r.FillBytes(sig.R[:])
+	s.FillBytes(sig.S[:])
+	return len(buf), nil
+}
+
+// IsLowS returns true if s ≤ order/2. Since (r, s) and (r, order-s) are both
+// valid signatures, some protocols (Bitcoin, Ethereum) only accept the low-s
+// one to prevent malleability.
+func (sig *Signature) IsLowS() bool {
+	s := new(big.Int).SetBytes(sig.S[:])
+	return s.Cmp(halfOrder) <= 0
+}
+
+// NormalizeS replaces s by order-s if s > order/2, so that the signature is
+// low-s, and returns true if s was replaced. The signature is still valid,
+// but for the opposite of the point R, so the y-parity bit of the recovery
+// information, if any, must be flipped.
+func (sig *Signature) NormalizeS() bool {
+	s := new(big.Int).SetBytes(sig.S[:])
+	if s.Cmp(halfOrder) <= 0 {
+		return false
+	}
+	s.Sub(order, s)
+	s.FillBytes(sig.S[:])
+	return true
+}
+
+// sizeRecoverableSignature is the size of r||s||v.
+const sizeRecoverableSignature = sizeSignature + 1
+
+// RecoverableSignature is an ECDSA signature with the public key recovery
+// information V in [0, 3] (see SignForRecover), encoded as r||s||v on 65
+// bytes, as Ethereum signatures (without the offset 27 of the legacy
+// transactions).
+type RecoverableSignature struct {
+	Signature
+	V byte
+}
+
+// Bytes returns the binary representation of sig as r||s||v.
+func (sig *RecoverableSignature) Bytes() []byte {
+	var res [sizeRecoverableSignature]byte
+	copy(res[:sizeSignature], sig.Signature.Bytes())
+	res[sizeSignature] = sig.V
+	return res[:]
+}
+
+// SetBytes sets sig from its binary representation r||s||v.
+// It returns the number of bytes read from buf.
+func (sig *RecoverableSignature) SetBytes(buf []byte) (int, error) {
+	if len(buf) != sizeRecoverableSignature {
+		return 0, errWrongSize
+	}
+	if buf[sizeSignature] > 3 {
+		return 0, errInvalidRecoveryID
+	}
+	if _, err := sig.Signature.SetBytes(buf[:sizeSignature]); err != nil {
+		return 0, err
+	}
+	sig.V = buf[sizeSignature]
+	return sizeRecoverableSignature, nil
+}
+
+// RecoverFromSignature recovers the public key from the message and the
+// signature r||s||v (see RecoverableSignature). If hFunc is nil, message is the
+// hash of the message, as in Sign.
+func (pk *PublicKey) RecoverFromSignature(message, sigBin []byte, hFunc hash.Hash) error {
+	var sig RecoverableSignature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return err
+	}
+	if hFunc != nil {
+		hFunc.Reset()
+		if _, err := hFunc.Write(message); err != nil {
+			return err
+		}
+		message = hFunc.Sum(nil)
+	}
+	r := new(big.Int).SetBytes(sig.R[:])
+	s := new(big.Int).SetBytes(sig.S[:])
+	return pk.RecoverFrom(message, uint(sig.V), r, s)
+}
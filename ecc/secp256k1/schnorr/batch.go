@@ -0,0 +1,109 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+var errBatchSize = errors.New("the numbers of public keys, signatures and messages differ")
+
+// sizeRandomizer is the size in bytes of the random coefficients of the
+// batch verification, so that an invalid batch passes with probability 2⁻¹²⁸.
+const sizeRandomizer = 16
+
+// BatchVerify verifies the signatures sigs[i] of messages msgs[i] for the
+// public keys pubs[i] at once. It returns true and -1 if all the signatures are
+// valid, and false and the index of the first invalid signature otherwise.
+//
+// As in BIP-340, it checks a random linear combination of the verification
+// equations, with a₀ = 1,
+//
+//	(∑ aᵢsᵢ)⋅G = ∑ aᵢRᵢ + ∑ aᵢeᵢPᵢ
+//
+// with a single multi-scalar multiplication, and falls back to verifying the
+// signatures one by one to find the invalid one if the check fails.
+func BatchVerify(pubs []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) (bool, int, error) {
+	if len(pubs) != len(sigs) || len(pubs) != len(msgs) {
+		return false, -1, errBatchSize
+	}
+	if len(pubs) == 0 {
+		return true, -1, nil
+	}
+
+	// the points are G, then Rᵢ, then Pᵢ
+	n := len(pubs)
+	points := make([]secp256k1.G1Affine, 2*n+1)
+	scalars := make([]fr.Element, 2*n+1)
+	_, points[0] = secp256k1.Generators()
+
+	var randomizer [sizeRandomizer]byte
+	var sig Signature
+	var a, s, sum big.Int
+	for i := 0; i < n; i++ {
+		if _, err := sig.SetBytes(sigs[i]); err != nil {
+			return false, i, err
+		}
+		if err := liftX(&points[1+i], sig.R[:]); err != nil {
+			return false, i, nil
+		}
+		points[1+n+i].Set(&pubs[i].A)
+		m, err := digest(msgs[i], hFunc)
+		if err != nil {
+			return false, i, err
+		}
+		px := pubs[i].A.X.Bytes()
+		e := challenge(sig.R[:], px[:], m)
+
+		if i == 0 {
+			a.SetUint64(1)
+		} else {
+			if _, err := rand.Read(randomizer[:]); err != nil {
+				return false, -1, err
+			}
+			a.SetBytes(randomizer[:])
+		}
+		s.SetBytes(sig.S[:]).Mul(&s, &a)
+		sum.Add(&sum, &s)
+		scalars[1+i].SetBigInt(&a)
+		scalars[1+n+i].SetBigInt(e.Mul(e, &a))
+	}
+	scalars[0].SetBigInt(&sum).Neg(&scalars[0])
+
+	var res secp256k1.G1Jac
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, -1, err
+	}
+	if res.Z.IsZero() {
+		return true, -1, nil
+	}
+
+	// find the invalid signature
+	for i := 0; i < n; i++ {
+		ok, err := pubs[i].Verify(sigs[i], msgs[i], hFunc)
+		if err != nil || !ok {
+			return false, i, err
+		}
+	}
+	// not reachable, unless the randomizers are not random
+	return false, -1, nil
+}
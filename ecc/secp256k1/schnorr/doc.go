@@ -0,0 +1,27 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schnorr provides the BIP-340 Schnorr signature scheme on the
+// secp256k1 curve, as used by Bitcoin Taproot.
+//
+// Public keys are x-only: a public key is the x-coordinate of the point with
+// an even y-coordinate, and a signature is R.x||s, where R has an even
+// y-coordinate too. The nonce and the challenge are derived with the tagged
+// hashes of BIP-340, so the hash function passed to Sign and Verify is only
+// used to hash the message beforehand; if it is nil, the message is signed
+// as is.
+//
+// Documentation:
+// - BIP-340: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+package schnorr
@@ -0,0 +1,108 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/subtle"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+)
+
+// Bytes returns the x-only binary representation of the public key, that is
+// the x-coordinate of the point as a big endian integer.
+func (pk *PublicKey) Bytes() []byte {
+	x := pk.A.X.Bytes()
+	return x[:]
+}
+
+// SetBytes sets pk from its x-only binary representation in buf: the point
+// is the point of the curve with this x-coordinate and an even y-coordinate.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePublicKey {
+		return 0, io.ErrShortBuffer
+	}
+	if err := liftX(&pk.A, buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	return sizePublicKey, nil
+}
+
+// Bytes returns the binary representation of privKey,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	copy(res[:sizePublicKey], privKey.PublicKey.Bytes())
+	subtle.ConstantTimeCopy(1, res[sizePublicKey:sizePrivateKey], privKey.scalar[:])
+	return res[:]
+}
+
+// SetBytes sets privKey from buf, where buf is interpreted
+// as  publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// The scalar can be a BIP-340 secret key, whose point has an odd
+// y-coordinate: it is then negated.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePrivateKey {
+		return 0, io.ErrShortBuffer
+	}
+	var pk PublicKey
+	if _, err := pk.SetBytes(buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	k := new(big.Int).SetBytes(buf[sizePublicKey:sizePrivateKey])
+	if k.Sign() == 0 || k.Cmp(order) >= 0 {
+		return 0, errZeroScalar
+	}
+	privKey.setScalar(k)
+	if !privKey.PublicKey.A.Equal(&pk.A) {
+		return 0, errKeyMismatch
+	}
+	return sizePrivateKey, nil
+}
+
+// Bytes returns the binary representation of sig
+// as a byte array of size sizeFp+sizeFr r||s
+func (sig *Signature) Bytes() []byte {
+	var res [sizeSignature]byte
+	subtle.ConstantTimeCopy(1, res[:sizeFp], sig.R[:])
+	subtle.ConstantTimeCopy(1, res[sizeFp:], sig.S[:])
+	return res[:]
+}
+
+// SetBytes sets sig from a buffer in binary.
+// buf is read interpreted as r||s, with r < p_mod and s < r_mod.
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytes(buf []byte) (int, error) {
+	if len(buf) != sizeSignature {
+		return 0, errWrongSize
+	}
+	bufBigInt := new(big.Int)
+	if bufBigInt.SetBytes(buf[:sizeFp]).Cmp(fp.Modulus()) >= 0 {
+		return 0, errRBiggerThanPMod
+	}
+	if bufBigInt.SetBytes(buf[sizeFp:]).Cmp(order) >= 0 {
+		return 0, errSBiggerThanRMod
+	}
+	copy(sig.R[:], buf[:sizeFp])
+	copy(sig.S[:], buf[sizeFp:])
+	return sizeSignature, nil
+}
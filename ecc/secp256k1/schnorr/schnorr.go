@@ -0,0 +1,284 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizeFp         = fp.Bytes
+	sizePublicKey  = sizeFp
+	sizePrivateKey = sizePublicKey + sizeFr
+	sizeSignature  = sizeFp + sizeFr
+	sizeAuxRand    = 32
+)
+
+var (
+	errWrongSize       = errors.New("wrong size buffer")
+	errNotOnCurve      = errors.New("x is not the x-coordinate of a point on the curve")
+	errRBiggerThanPMod = errors.New("r >= p_mod")
+	errSBiggerThanRMod = errors.New("s >= r_mod")
+	errZeroScalar      = errors.New("the secret scalar is zero or >= r_mod")
+	errKeyMismatch     = errors.New("the secret scalar doesn't match the public key")
+	errZeroNonce       = errors.New("the nonce is zero")
+)
+
+var order = fr.Modulus()
+
+// tags of the BIP-340 tagged hashes
+var (
+	tagAux       = sha256.Sum256([]byte("BIP0340/aux"))
+	tagNonce     = sha256.Sum256([]byte("BIP0340/nonce"))
+	tagChallenge = sha256.Sum256([]byte("BIP0340/challenge"))
+)
+
+// PublicKey represents a BIP-340 public key. A has an even y-coordinate.
+type PublicKey struct {
+	A secp256k1.G1Affine
+}
+
+// PrivateKey represents a BIP-340 private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian, such that scalar⋅G = A
+}
+
+// Signature represents a BIP-340 signature
+type Signature struct {
+	R [sizeFp]byte // x-coordinate of the commitment, which has an even y-coordinate
+	S [sizeFr]byte
+}
+
+var one = new(big.Int).SetInt64(1)
+
+// randFieldElement returns a random element of the order of the given
+// curve using the procedure given in FIPS 186-4, Appendix B.5.1.
+func randFieldElement(rand io.Reader) (k *big.Int, err error) {
+	b := make([]byte, fr.Bits/8+8)
+	_, err = io.ReadFull(rand, b)
+	if err != nil {
+		return
+	}
+
+	k = new(big.Int).SetBytes(b)
+	n := new(big.Int).Sub(order, one)
+	k.Mod(k, n)
+	k.Add(k, one)
+	return
+}
+
+// GenerateKey generates a public and private key pair.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	k, err := randFieldElement(rand)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey := new(PrivateKey)
+	privateKey.setScalar(k)
+	return privateKey, nil
+}
+
+// setScalar sets the key pair from the secret scalar k ∈ [1, order), which is
+// negated if k⋅G has an odd y-coordinate.
+func (privKey *PrivateKey) setScalar(k *big.Int) {
+	privKey.PublicKey.A.ScalarMultiplicationBaseConstantTime(k)
+	if !hasEvenY(&privKey.PublicKey.A) {
+		k = new(big.Int).Sub(order, k)
+		privKey.PublicKey.A.Neg(&privKey.PublicKey.A)
+	}
+	k.FillBytes(privKey.scalar[:])
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// Sign performs the BIP-340 signature, with 32 fresh random bytes as
+// auxiliary randomness (see SignWithAuxRand).
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	var auxRand [sizeAuxRand]byte
+	if _, err := io.ReadFull(rand.Reader, auxRand[:]); err != nil {
+		return nil, err
+	}
+	return privKey.SignWithAuxRand(message, auxRand[:], hFunc)
+}
+
+// SignWithAuxRand performs the BIP-340 signature with the 32 bytes of
+// auxiliary randomness auxRand
+//
+// t = bytes(d) ⊕ hash_aux(auxRand)
+// k = hash_nonce(t || bytes(P) || m) (mod order), negated if R = k ⋅ G has an odd y
+// e = hash_challenge(bytes(R) || bytes(P) || m) (mod order)
+// signature = bytes(R) || bytes(k + e ⋅ d (mod order))
+//
+// The auxiliary randomness only protects against side channels: the
+// signature is still secure if auxRand is constant.
+func (privKey *PrivateKey) SignWithAuxRand(message, auxRand []byte, hFunc hash.Hash) ([]byte, error) {
+	if len(auxRand) != sizeAuxRand {
+		return nil, errWrongSize
+	}
+	m, err := digest(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	px := privKey.PublicKey.A.X.Bytes()
+
+	t := taggedHash(&tagAux, auxRand)
+	for i := range t {
+		t[i] ^= privKey.scalar[i]
+	}
+	nonce := taggedHash(&tagNonce, t[:], px[:], m)
+	k := new(big.Int).SetBytes(nonce[:])
+	k.Mod(k, order)
+	if k.Sign() == 0 {
+		return nil, errZeroNonce
+	}
+
+	var R secp256k1.G1Affine
+	R.ScalarMultiplicationBaseConstantTime(k)
+	if !hasEvenY(&R) {
+		k.Sub(order, k)
+	}
+
+	var sig Signature
+	sig.R = R.X.Bytes()
+	e := challenge(sig.R[:], px[:], m)
+
+	s := new(big.Int).SetBytes(privKey.scalar[:])
+	s.Mul(s, e).
+		Add(s, k).
+		Mod(s, order)
+	s.FillBytes(sig.S[:])
+
+	return sig.Bytes(), nil
+}
+
+// Verify validates the BIP-340 signature
+//
+// R = s ⋅ G - e ⋅ P
+// R ≠ 0, y_R even and x_R ?= r
+func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+
+	// Deserialize the signature
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	m, err := digest(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	px := publicKey.A.X.Bytes()
+	e := challenge(sig.R[:], px[:], m)
+	e.Sub(order, e)
+	s := new(big.Int).SetBytes(sig.S[:])
+
+	var R secp256k1.G1Jac
+	R.JointScalarMultiplicationBase(&publicKey.A, s, e)
+	if R.Z.IsZero() {
+		return false, nil
+	}
+	var rAff secp256k1.G1Affine
+	rAff.FromJacobian(&R)
+	rx := rAff.X.Bytes()
+
+	return hasEvenY(&rAff) && subtle.ConstantTimeCompare(rx[:], sig.R[:]) == 1, nil
+}
+
+// digest returns hFunc(message), or message if hFunc is nil.
+func digest(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// challenge returns hash_challenge(r || px || m) (mod order).
+func challenge(r, px, m []byte) *big.Int {
+	h := taggedHash(&tagChallenge, r, px, m)
+	e := new(big.Int).SetBytes(h[:])
+	return e.Mod(e, order)
+}
+
+// taggedHash returns SHA256(SHA256(tag) || SHA256(tag) || data...), where
+// tagHash is SHA256(tag).
+func taggedHash(tagHash *[sha256.Size]byte, data ...[]byte) (res [sha256.Size]byte) {
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	h.Sum(res[:0])
+	return
+}
+
+// liftX sets p to the point of x-coordinate x and even y-coordinate.
+func liftX(p *secp256k1.G1Affine, x []byte) error {
+	if err := p.X.SetBytesCanonical(x); err != nil {
+		return err
+	}
+	// y² = x³ + 7
+	_, b := secp256k1.CurveCoefficients()
+	var y2 fp.Element
+	y2.Square(&p.X).
+		Mul(&y2, &p.X).
+		Add(&y2, &b)
+	if p.Y.Sqrt(&y2) == nil {
+		return errNotOnCurve
+	}
+	if !hasEvenY(p) {
+		p.Y.Neg(&p.Y)
+	}
+	return nil
+}
+
+func hasEvenY(p *secp256k1.G1Affine) bool {
+	y := p.Y.Bytes()
+	return y[sizeFp-1]&1 == 0
+}
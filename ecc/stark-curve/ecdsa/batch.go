@@ -0,0 +1,153 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"errors"
+	"github.com/consensys/gnark-crypto/ecc/stark-curve"
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+	"hash"
+	"math/big"
+)
+
+var (
+	errBatchSize  = errors.New("the numbers of public keys, signatures and messages differ")
+	errNotOnCurve = errors.New("public key is not on the curve")
+)
+
+// BatchVerify verifies the signatures sigs[i] of messages msgs[i] for the
+// public keys pubs[i] at once. It returns true and -1 if all the signatures
+// are valid, and false and the index of the first invalid signature otherwise.
+//
+// sigs[i] is either a signature r||s (see Signature), or a recoverable
+// signature r||s||v (see RecoverableSignature). A recoverable signature is
+// valid if it is valid for Verify and v is its recovery information.
+//
+// A plain ECDSA signature only contains the x-coordinate r of the point R, so
+// its verification equation can't be combined with the others: such
+// signatures are verified one by one, split across the CPUs.
+// There is no multi-scalar multiplication on this curve, so the recoverable
+// signatures are verified one by one as well.
+func BatchVerify(pubs []PublicKey, sigs, msgs [][]byte, hFunc hash.Hash) (bool, int, error) {
+	if len(pubs) != len(sigs) || len(pubs) != len(msgs) {
+		return false, -1, errBatchSize
+	}
+	n := len(pubs)
+	if n == 0 {
+		return true, -1, nil
+	}
+
+	// decode the signatures and hash the messages first: hFunc can't be
+	// shared between go routines.
+	decoded := make([]RecoverableSignature, n)
+	isPlain := make([]bool, n)
+	digests := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		if !pubs[i].A.IsInSubGroup() {
+			return false, i, errNotOnCurve
+		}
+		if len(sigs[i]) == sizeRecoverableSignature {
+			if _, err := decoded[i].SetBytes(sigs[i]); err != nil {
+				return false, i, err
+			}
+		} else {
+			if _, err := decoded[i].Signature.SetBytes(sigs[i]); err != nil {
+				return false, i, err
+			}
+			isPlain[i] = true
+		}
+		d, err := messageDigest(msgs[i], hFunc)
+		if err != nil {
+			return false, i, err
+		}
+		digests[i] = d
+	}
+
+	valid := make([]bool, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			if valid[i] {
+				continue
+			}
+			if !isPlain[i] {
+				valid[i] = verifyRecoverable(&pubs[i], &decoded[i], digests[i])
+				continue
+			}
+			// the signature and the digest are already checked
+			valid[i], _ = pubs[i].Verify(sigs[i], digests[i], nil)
+		}
+	})
+
+	for i := range valid {
+		if !valid[i] {
+			return false, i, nil
+		}
+	}
+	return true, -1, nil
+}
+
+// verifyRecoverable checks that Rᵢ = s⁻¹mᵢ⋅Base + s⁻¹rᵢ⋅Pᵢ, where Rᵢ is given by
+// the recovery information of the signature.
+func verifyRecoverable(pub *PublicKey, sig *RecoverableSignature, digest []byte) bool {
+	var R starkcurve.G1Affine
+	if !recoverR(&R, sig) {
+		return false
+	}
+	var r, s, u1, u2 big.Int
+	r.SetBytes(sig.R[:sizeFr])
+	s.SetBytes(sig.S[:sizeFr])
+	s.ModInverse(&s, order)
+	u1.Mul(HashToInt(digest), &s).Mod(&u1, order)
+	u2.Mul(&r, &s).Mod(&u2, order)
+	var U starkcurve.G1Jac
+	var UAff starkcurve.G1Affine
+	U.JointScalarMultiplicationBase(&pub.A, &u1, &u2)
+	UAff.FromJacobian(&U)
+	return UAff.Equal(&R)
+}
+
+// recoverR sets R to the point of the signature, whose x-coordinate is
+// r + (v>>1)⋅order and the parity of y is v&1 (see SignForRecover). It returns
+// false if there is no such point on the curve.
+func recoverR(R *starkcurve.G1Affine, sig *RecoverableSignature) bool {
+	r := new(big.Int).SetBytes(sig.R[:sizeFr])
+	x := big.NewInt(int64(sig.V >> 1))
+	x.Mul(x, order).Add(x, r)
+	if x.Cmp(fp.Modulus()) >= 0 {
+		return false
+	}
+	P, err := RecoverP(uint(sig.V), r)
+	if err != nil {
+		return false
+	}
+	R.Set(P)
+	return true
+}
+
+// messageDigest returns the message hashed with hFunc if it's not nil, as in
+// Sign and Verify, and the message itself otherwise.
+func messageDigest(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
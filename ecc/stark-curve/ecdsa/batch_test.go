@@ -0,0 +1,138 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+)
+
+// batchOfSignatures returns n public keys, signatures and messages.
+// The signatures are recoverable, except the odd ones if plain is set.
+func batchOfSignatures(tb testing.TB, n int, plain bool) ([]PublicKey, [][]byte, [][]byte) {
+	hFunc := sha256.New()
+	pubs := make([]PublicKey, n)
+	sigs := make([][]byte, n)
+	msgs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(rand.Reader)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		pubs[i] = privKey.PublicKey
+		msgs[i] = []byte(fmt.Sprintf("message %d", i))
+		if plain && i%2 == 1 {
+			if sigs[i], err = privKey.Sign(msgs[i], hFunc); err != nil {
+				tb.Fatal(err)
+			}
+			continue
+		}
+		v, r, s, err := privKey.SignForRecover(msgs[i], hFunc)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		var sig RecoverableSignature
+		r.FillBytes(sig.R[:sizeFr])
+		s.FillBytes(sig.S[:sizeFr])
+		sig.V = byte(v)
+		sigs[i] = sig.Bytes()
+	}
+	return pubs, sigs, msgs
+}
+
+func TestBatchVerify(t *testing.T) {
+	t.Parallel()
+	const n = 20
+	for _, plain := range []bool{false, true} {
+		pubs, sigs, msgs := batchOfSignatures(t, n, plain)
+		checkBatchVerify(t, pubs, sigs, msgs)
+	}
+
+	// a signature valid for Verify, with the wrong recovery information
+	pubs, sigs, msgs := batchOfSignatures(t, n, false)
+	hFunc := sha256.New()
+	sigs[5][sizeSignature] ^= 1
+	ok, invalid, err := BatchVerify(pubs, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok || invalid != 5 {
+		t.Fatalf("expected signature 5 to be invalid, got %d", invalid)
+	}
+}
+
+func checkBatchVerify(t *testing.T, pubs []PublicKey, sigs, msgs [][]byte) {
+	hFunc := sha256.New()
+
+	ok, invalid, err := BatchVerify(pubs, sigs, msgs, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || invalid != -1 {
+		t.Fatal("valid batch should verify")
+	}
+
+	// a single signature is invalid
+	for _, i := range []int{6, 7} {
+		msgs[i], msgs[i+1] = msgs[i+1], msgs[i]
+		ok, invalid, err = BatchVerify(pubs, sigs, msgs, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok || invalid != i {
+			t.Fatalf("expected signature %d to be invalid, got %d", i, invalid)
+		}
+		msgs[i], msgs[i+1] = msgs[i+1], msgs[i]
+	}
+
+	// malformed signature
+	sig := sigs[3]
+	sigs[3] = sigs[3][2:]
+	if _, invalid, err = BatchVerify(pubs, sigs, msgs, hFunc); err == nil || invalid != 3 {
+		t.Fatal("expected an error for a malformed signature")
+	}
+	sigs[3] = sig
+
+	// empty batch and wrong sizes
+	if ok, _, err := BatchVerify(nil, nil, nil, hFunc); !ok || err != nil {
+		t.Fatal("empty batch should verify")
+	}
+	if _, _, err := BatchVerify(pubs, sigs[1:], msgs, hFunc); err != errBatchSize {
+		t.Fatal("expected an error for mismatched sizes")
+	}
+}
+
+func BenchmarkBatchVerify(b *testing.B) {
+	const n = 256
+	pubs, sigs, msgs := batchOfSignatures(b, n, false)
+	hFunc := sha256.New()
+
+	b.Run("BatchVerify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchVerify(pubs, sigs, msgs, hFunc)
+		}
+	})
+	b.Run("Verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := range pubs {
+				pubs[j].Verify(sigs[j][:sizeSignature], msgs[j], hFunc)
+			}
+		}
+	})
+}
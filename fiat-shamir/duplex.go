@@ -0,0 +1,175 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+// securityMargin is the number of extra bytes squeezed when sampling a field
+// element, so that the reduction modulo the field order has a statistical
+// distance to the uniform distribution of at most 2⁻¹²⁸.
+const securityMargin = 16
+
+var errNoChallenge = errors.New("a transcript forked from a duplex needs at least one challenge")
+
+// domain separation tags of the duplex operations
+const (
+	tagInit byte = iota
+	tagAbsorb
+	tagSqueeze
+	tagRatchet
+	tagFork
+)
+
+// Marshaler is implemented by the values that can be absorbed in a Duplex or
+// bound to a Transcript challenge (see ComputeChallengeFrom), in particular the
+// field elements and the affine points of the curves.
+type Marshaler interface {
+	Marshal() []byte
+}
+
+// Duplex is a duplex-sponge style transcript, in the spirit of SAFE.
+//
+// Unlike Transcript, the challenges need not be declared up front: values are
+// absorbed and challenges are squeezed in any order, for an arbitrary number
+// of rounds. Every squeezed challenge depends on the domain separator and on
+// all the values absorbed and squeezed before it.
+//
+// The state is a digest of the hash function; absorbing a value only buffers
+// it, the hash function is called when a challenge is squeezed.
+type Duplex struct {
+	h       hash.Hash
+	state   []byte
+	pending []byte // length-prefixed values absorbed since the last squeeze
+}
+
+// NewDuplex returns a new Duplex.
+// h is the hash function used to update the state and derive the challenges;
+// domainSeparator identifies the protocol and is bound to all the challenges.
+func NewDuplex(h hash.Hash, domainSeparator []byte) *Duplex {
+	d := &Duplex{h: h}
+	d.state = d.update(tagInit, appendLengthPrefixed(nil, domainSeparator))
+	return d
+}
+
+// Absorb binds the duplex to the given values. The values are length-prefixed
+// so that the encoding of a sequence of values is injective.
+func (d *Duplex) Absorb(values ...[]byte) {
+	for _, v := range values {
+		d.pending = appendLengthPrefixed(d.pending, v)
+	}
+}
+
+// AbsorbValues binds the duplex to the binary representation of the given
+// values, typically field elements or curve points.
+func (d *Duplex) AbsorbValues(values ...Marshaler) {
+	for _, v := range values {
+		d.pending = appendLengthPrefixed(d.pending, v.Marshal())
+	}
+}
+
+// Squeeze returns a challenge of n bytes, bound to all the previous operations
+// on the duplex.
+func (d *Duplex) Squeeze(n int) []byte {
+	d.flush()
+
+	res := make([]byte, 0, n)
+	var buf [16]byte
+	for counter := uint64(0); len(res) < n; counter++ {
+		binary.BigEndian.PutUint64(buf[:8], uint64(n))
+		binary.BigEndian.PutUint64(buf[8:], counter)
+		block := d.update(tagSqueeze, buf[:])
+		res = append(res, block...)
+	}
+	res = res[:n]
+
+	// ratchet, so that the next challenges differ from this one
+	binary.BigEndian.PutUint64(buf[:8], uint64(n))
+	d.state = d.update(tagRatchet, buf[:8])
+
+	return res
+}
+
+// NewTranscript returns a new Transcript for the given challenges, whose first
+// challenge is bound to the current state of the duplex. It allows running a
+// protocol written against Transcript (sumcheck, gkr...) as a sub-protocol.
+//
+// At least one challenge is needed to bind the transcript to the duplex.
+func (d *Duplex) NewTranscript(challengesID ...string) (*Transcript, error) {
+	if len(challengesID) == 0 {
+		return nil, errNoChallenge
+	}
+	d.flush()
+	seed := d.update(tagFork, nil)
+	d.state = d.update(tagRatchet, nil)
+
+	t := NewTranscript(d.h, challengesID...)
+	if err := t.Bind(challengesID[0], seed); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// SqueezeElements returns n field elements sampled from the duplex.
+// Each element is derived from Bytes + 16 squeezed bytes, reduced modulo the
+// field order, so that it is statistically close to uniform.
+//
+// Example:
+//
+//	challenges := fiatshamir.SqueezeElements[fr.Element](d, 2)
+func SqueezeElements[E any, PE interface {
+	*E
+	Marshaler
+	SetBytes([]byte) *E
+}](d *Duplex, n int) []E {
+	var zero E
+	size := len(PE(&zero).Marshal()) + securityMargin
+
+	buf := d.Squeeze(n * size)
+	res := make([]E, n)
+	for i := range res {
+		PE(&res[i]).SetBytes(buf[i*size : (i+1)*size])
+	}
+	return res
+}
+
+// flush absorbs the pending values in the state.
+func (d *Duplex) flush() {
+	if len(d.pending) == 0 {
+		return
+	}
+	d.state = d.update(tagAbsorb, d.pending)
+	d.pending = d.pending[:0]
+}
+
+// update returns H(state || tag || data).
+func (d *Duplex) update(tag byte, data []byte) []byte {
+	d.h.Reset()
+	defer d.h.Reset()
+	d.h.Write(d.state)
+	d.h.Write([]byte{tag})
+	d.h.Write(data)
+	return d.h.Sum(nil)
+}
+
+func appendLengthPrefixed(dst, v []byte) []byte {
+	var l [8]byte
+	binary.BigEndian.PutUint64(l[:], uint64(len(v)))
+	dst = append(dst, l[:]...)
+	return append(dst, v...)
+}
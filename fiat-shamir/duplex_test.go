@@ -0,0 +1,142 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fiatshamir
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+func TestDuplex(t *testing.T) {
+	t.Parallel()
+
+	run := func(ds string, values ...[]byte) (c1, c2 []byte) {
+		d := NewDuplex(sha256.New(), []byte(ds))
+		d.Absorb(values...)
+		c1 = d.Squeeze(50)
+		c2 = d.Squeeze(50)
+		return
+	}
+
+	c1, c2 := run("ds", []byte("v1"), []byte("v2"))
+	if len(c1) != 50 || len(c2) != 50 {
+		t.Fatal("wrong challenge length")
+	}
+	if bytes.Equal(c1, c2) {
+		t.Fatal("successive challenges should differ")
+	}
+
+	// determinism
+	d1, d2 := run("ds", []byte("v1"), []byte("v2"))
+	if !bytes.Equal(c1, d1) || !bytes.Equal(c2, d2) {
+		t.Fatal("duplex should be deterministic")
+	}
+
+	// domain separation
+	d1, _ = run("other ds", []byte("v1"), []byte("v2"))
+	if bytes.Equal(c1, d1) {
+		t.Fatal("challenges should depend on the domain separator")
+	}
+
+	// the encoding of the absorbed values is injective
+	d1, _ = run("ds", []byte("v1v"), []byte("2"))
+	if bytes.Equal(c1, d1) {
+		t.Fatal("challenges should depend on the boundaries of the absorbed values")
+	}
+
+	// a challenge is not a prefix of a longer one
+	d := NewDuplex(sha256.New(), []byte("ds"))
+	d.Absorb([]byte("v1"), []byte("v2"))
+	if bytes.Equal(c1, d.Squeeze(64)[:50]) {
+		t.Fatal("challenges should depend on their length")
+	}
+}
+
+func TestDuplexTypedValues(t *testing.T) {
+	t.Parallel()
+
+	_, _, g1, _ := bn254.Generators()
+	var x fr.Element
+	x.SetRandom()
+
+	d1 := NewDuplex(sha256.New(), []byte("ds"))
+	d1.AbsorbValues(&x, &g1)
+	e1 := SqueezeElements[fr.Element](d1, 3)
+
+	xBytes := x.Marshal()
+	d2 := NewDuplex(sha256.New(), []byte("ds"))
+	d2.Absorb(xBytes, g1.Marshal())
+	e2 := SqueezeElements[fr.Element](d2, 3)
+
+	if len(e1) != 3 {
+		t.Fatal("wrong number of elements")
+	}
+	for i := range e1 {
+		if !e1[i].Equal(&e2[i]) {
+			t.Fatal("AbsorbValues should match Absorb of the marshalled values")
+		}
+	}
+	if e1[0].Equal(&e1[1]) || e1[1].Equal(&e1[2]) {
+		t.Fatal("squeezed elements should differ")
+	}
+}
+
+func TestDuplexNewTranscript(t *testing.T) {
+	t.Parallel()
+
+	challenge := func(absorbed string) []byte {
+		d := NewDuplex(sha256.New(), []byte("ds"))
+		d.Absorb([]byte(absorbed))
+		ts, err := d.NewTranscript("alpha", "beta")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ts.Bind("alpha", []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+		alpha, err := ts.ComputeChallenge("alpha")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return alpha
+	}
+
+	if !bytes.Equal(challenge("a"), challenge("a")) {
+		t.Fatal("transcript challenges should be deterministic")
+	}
+	if bytes.Equal(challenge("a"), challenge("b")) {
+		t.Fatal("transcript challenges should depend on the duplex state")
+	}
+
+	d := NewDuplex(sha256.New(), []byte("ds"))
+	if _, err := d.NewTranscript(); err == nil {
+		t.Fatal("forking a transcript without challenges should fail")
+	}
+}
+
+func BenchmarkDuplexSqueezeElements(b *testing.B) {
+	d := NewDuplex(sha256.New(), []byte("ds"))
+	var x fr.Element
+	x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d.AbsorbValues(&x)
+		SqueezeElements[fr.Element](d, 1)
+	}
+}
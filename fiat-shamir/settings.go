@@ -1,12 +1,20 @@
 package fiatshamir
 
-import "hash"
+import (
+	"errors"
+	"hash"
+)
+
+// ErrTranscriptAndDuplex is returned by the protocols given settings holding
+// both a transcript and a duplex, as only one of them can derive the challenges.
+var ErrTranscriptAndDuplex = errors.New("fiat-shamir settings hold both a transcript and a duplex")
 
 type Settings struct {
 	Transcript     *Transcript
 	Prefix         string
 	BaseChallenges [][]byte
 	Hash           hash.Hash
+	Duplex         *Duplex
 }
 
 func WithTranscript(transcript *Transcript, prefix string, baseChallenges ...[]byte) Settings {
@@ -23,3 +31,22 @@ func WithHash(hash hash.Hash, baseChallenges ...[]byte) Settings {
 		Hash:           hash,
 	}
 }
+
+// WithDuplex returns settings deriving a new transcript from the duplex d, so
+// that the challenges of the protocol are bound to everything absorbed in d.
+func WithDuplex(d *Duplex, baseChallenges ...[]byte) Settings {
+	return Settings{
+		BaseChallenges: baseChallenges,
+		Duplex:         d,
+	}
+}
+
+// NewTranscript returns a new transcript for the given challenges, forked from
+// the duplex of the settings if set, built on the hash function otherwise.
+// The base challenges are not bound, and the Transcript field is ignored.
+func (s *Settings) NewTranscript(challengesID ...string) (*Transcript, error) {
+	if s.Duplex != nil {
+		return s.Duplex.NewTranscript(challengesID...)
+	}
+	return NewTranscript(s.Hash, challengesID...), nil
+}
@@ -136,3 +136,14 @@ func (t *Transcript) ComputeChallenge(challengeID string) ([]byte, error) {
 	return res, nil
 
 }
+
+// ComputeChallengeFrom binds the binary representations of values to the
+// challenge, in order, then computes it.
+func ComputeChallengeFrom[T Marshaler](t *Transcript, challengeID string, values ...T) ([]byte, error) {
+	for _, v := range values {
+		if err := t.Bind(challengeID, v.Marshal()); err != nil {
+			return nil, err
+		}
+	}
+	return t.ComputeChallenge(challengeID)
+}
@@ -96,6 +96,35 @@ func TestTranscript(t *testing.T) {
 
 }
 
+type marshaler []byte
+
+func (m marshaler) Marshal() []byte {
+	return m
+}
+
+func TestComputeChallengeFrom(t *testing.T) {
+	t.Parallel()
+
+	fs := initTranscript()
+	expected, err := fs.ComputeChallenge("alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs = NewTranscript(sha256.New(), "alpha", "beta", "gamma")
+	alpha, err := ComputeChallengeFrom(fs, "alpha", marshaler("v1"), marshaler("v2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(alpha, expected) {
+		t.Fatal("ComputeChallengeFrom should bind the values in order then compute the challenge")
+	}
+
+	if _, err = ComputeChallengeFrom(fs, "alpha", marshaler("v3")); err != errChallengeAlreadyComputed {
+		t.Fatal("binding a value to a computed challenge should fail")
+	}
+}
+
 func TestNonExistingChallenge(t *testing.T) {
 	t.Parallel()
 
@@ -158,6 +158,12 @@ func Generate(w io.Writer, F *config.FieldConfig) error {
 	// fft butterflies
 	f.generateButterfly()
 
+	if F.NbWords == 4 {
+		// vector operations
+		f.generateAddVec()
+		f.generateSubVec()
+	}
+
 	return nil
 }
 
@@ -229,6 +229,27 @@ func GenerateFF(F *config.FieldConfig, outputDir string) error {
 		}
 	}
 
+	if F.ASM && F.NbWords == 4 {
+		// generate vector_amd64.go and vector_purego.go
+		pathSrc := filepath.Join(outputDir, "vector_amd64.go")
+		bavardOptsCpy := make([]func(*bavard.Bavard) error, len(bavardOpts))
+		copy(bavardOptsCpy, bavardOpts)
+		bavardOptsCpy = append(bavardOptsCpy, bavard.BuildTag("!purego"))
+		if err := bavard.GenerateFromString(pathSrc, []string{element.VectorOpsAmd64}, F, bavardOptsCpy...); err != nil {
+			return err
+		}
+
+		pathSrc = filepath.Join(outputDir, "vector_purego.go")
+		copy(bavardOptsCpy, bavardOpts)
+		bavardOptsCpy[len(bavardOptsCpy)-1] = bavard.BuildTag("!amd64 purego")
+		if err := bavard.GenerateFromString(pathSrc, []string{element.VectorOpsPureGo}, F, bavardOptsCpy...); err != nil {
+			return err
+		}
+	} else {
+		_ = os.Remove(filepath.Join(outputDir, "vector_amd64.go"))
+		_ = os.Remove(filepath.Join(outputDir, "vector_purego.go"))
+	}
+
 	if F.ASM {
 		// generate asm.go and asm_noadx.go
 		src := []string{
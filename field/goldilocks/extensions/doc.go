@@ -0,0 +1,31 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package extensions implements the quadratic and cubic extensions of the
+// goldilocks field.
+//
+// The goldilocks field is only 64 bits wide, which caps the soundness of
+// protocols that sample their challenges from it (e.g. FRI). Sampling the
+// challenges from E2 or E3 instead brings them to ~128 or ~192 bits.
+//
+//	E2 = goldilocks[u] / (u² - 7)
+//	E3 = goldilocks[v] / (v³ - 7)
+//
+// 7 generates the multiplicative group of the goldilocks field, so it is
+// neither a square nor a cube and both polynomials are irreducible.
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package extensions
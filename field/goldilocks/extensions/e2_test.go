@@ -0,0 +1,211 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extensions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+const (
+	nbFuzzShort = 10
+	nbFuzz      = 50
+)
+
+func TestE2Arithmetic(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE2()
+	genB := GenE2()
+	genE := GenElement()
+
+	properties.Property("[GOLDILOCKS] sub & add should leave an element invariant", prop.ForAll(
+		func(a, b *E2) bool {
+			var c E2
+			c.Set(a)
+			c.Add(&c, b).Sub(&c, b)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] mul should match the schoolbook multiplication", prop.ForAll(
+		func(a, b *E2) bool {
+			var c E2
+			var t, nr goldilocks.Element
+			nr.SetUint64(7)
+			c.A0.Mul(&a.A0, &b.A0)
+			t.Mul(&a.A1, &b.A1).Mul(&t, &nr)
+			c.A0.Add(&c.A0, &t)
+			c.A1.Mul(&a.A0, &b.A1)
+			t.Mul(&a.A1, &b.A0)
+			c.A1.Add(&c.A1, &t)
+			return c.Equal(new(E2).Mul(a, b))
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E2) bool {
+			var c, d E2
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] square and mul should output the same result", prop.ForAll(
+		func(a *E2) bool {
+			var b, c E2
+			b.Mul(a, a)
+			c.Square(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] inverse twice should leave an element invariant", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			b.Inverse(a).Inverse(&b)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] a*a⁻¹ should be 1", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			b.Inverse(a).Mul(&b, a)
+			return a.IsZero() || b.IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] norm should be a*conjugate(a)", prop.ForAll(
+		func(a *E2) bool {
+			var b E2
+			var norm goldilocks.Element
+			b.Conjugate(a).Mul(&b, a)
+			a.Norm(&norm)
+			return b.A1.IsZero() && b.A0.Equal(&norm)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] MulByElement should match Mul", prop.ForAll(
+		func(a *E2, e goldilocks.Element) bool {
+			var b, c E2
+			b.MulByElement(a, &e)
+			c.A0 = e
+			c.Mul(&c, a)
+			return b.Equal(&c)
+		},
+		genA,
+		genE,
+	))
+
+	properties.Property("[GOLDILOCKS] a^(q²-1) should be 1", prop.ForAll(
+		func(a *E2) bool {
+			q := goldilocks.Modulus()
+			var e big.Int
+			e.Mul(q, q).Sub(&e, big.NewInt(1))
+			var b E2
+			b.Exp(*a, &e)
+			return a.IsZero() || b.IsOne()
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkE2Mul(b *testing.B) {
+	var x, y E2
+	_, _ = x.SetRandom()
+	_, _ = y.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(&x, &y)
+	}
+}
+
+func BenchmarkE2Inverse(b *testing.B) {
+	var x E2
+	_, _ = x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Inverse(&x)
+	}
+}
+
+// ------------------------------------------------------------
+// generators
+
+// GenElement generates a goldilocks element
+func GenElement() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var elmt goldilocks.Element
+
+		if _, err := elmt.SetRandom(); err != nil {
+			panic(err)
+		}
+		genResult := gopter.NewGenResult(elmt, gopter.NoShrinker)
+		return genResult
+	}
+}
+
+// GenE2 generates an E2 elmt
+func GenE2() gopter.Gen {
+	return gopter.CombineGens(
+		GenElement(),
+		GenElement(),
+	).Map(func(values []interface{}) *E2 {
+		return &E2{A0: values[0].(goldilocks.Element), A1: values[1].(goldilocks.Element)}
+	})
+}
+
+// GenE3 generates an E3 elmt
+func GenE3() gopter.Gen {
+	return gopter.CombineGens(
+		GenElement(),
+		GenElement(),
+		GenElement(),
+	).Map(func(values []interface{}) *E3 {
+		return &E3{A0: values[0].(goldilocks.Element), A1: values[1].(goldilocks.Element), A2: values[2].(goldilocks.Element)}
+	})
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extensions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestE3Arithmetic(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE3()
+	genB := GenE3()
+	genE := GenElement()
+
+	properties.Property("[GOLDILOCKS] sub & add should leave an element invariant", prop.ForAll(
+		func(a, b *E3) bool {
+			var c E3
+			c.Set(a)
+			c.Add(&c, b).Sub(&c, b)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] mul should match the schoolbook multiplication", prop.ForAll(
+		func(a, b *E3) bool {
+			x := []goldilocks.Element{a.A0, a.A1, a.A2}
+			y := []goldilocks.Element{b.A0, b.A1, b.A2}
+			var r [5]goldilocks.Element
+			var t goldilocks.Element
+			for i := range x {
+				for j := range y {
+					t.Mul(&x[i], &y[j])
+					r[i+j].Add(&r[i+j], &t)
+				}
+			}
+			// reduce with v³ = 7
+			var nr goldilocks.Element
+			nr.SetUint64(7)
+			t.Mul(&r[3], &nr)
+			r[0].Add(&r[0], &t)
+			t.Mul(&r[4], &nr)
+			r[1].Add(&r[1], &t)
+			c := E3{A0: r[0], A1: r[1], A2: r[2]}
+			return c.Equal(new(E3).Mul(a, b))
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("[GOLDILOCKS] square and mul should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.Mul(a, a)
+			c.Square(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] inverse twice should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a).Inverse(&b)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] a*a⁻¹ should be 1", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a).Mul(&b, a)
+			return a.IsZero() || b.IsOne()
+		},
+		genA,
+	))
+
+	properties.Property("[GOLDILOCKS] MulByElement should match Mul", prop.ForAll(
+		func(a *E3, e goldilocks.Element) bool {
+			var b, c E3
+			b.MulByElement(a, &e)
+			c.A0 = e
+			c.Mul(&c, a)
+			return b.Equal(&c)
+		},
+		genA,
+		genE,
+	))
+
+	properties.Property("[GOLDILOCKS] a^(q³-1) should be 1", prop.ForAll(
+		func(a *E3) bool {
+			q := goldilocks.Modulus()
+			var e big.Int
+			e.Mul(q, q).Mul(&e, q).Sub(&e, big.NewInt(1))
+			var b E3
+			b.Exp(*a, &e)
+			return a.IsZero() || b.IsOne()
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkE3Mul(b *testing.B) {
+	var x, y E3
+	_, _ = x.SetRandom()
+	_, _ = y.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(&x, &y)
+	}
+}
+
+func BenchmarkE3Inverse(b *testing.B) {
+	var x E3
+	_, _ = x.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Inverse(&x)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package poseidon2 provides the Poseidon2 permutation over goldilocks goldilocks and a
+// sponge-based hash function built on top of it.
+//
+// The permutation follows https://eprint.iacr.org/2023/323.pdf. The round keys (and, for
+// widths larger than 3, the diagonal of the internal matrix) are derived deterministically
+// from a seed which depends on the parameters, using keccak256 as in the mimc package.
+//
+// The default parameters are width 8, 8 full rounds and 22 partial rounds,
+// with the s-box x -> x^7.
+package poseidon2
@@ -0,0 +1,186 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+)
+
+const (
+	// BlockSize size that poseidon2 consumes
+	BlockSize = goldilocks.Bytes
+
+	// DefaultCapacity is the default capacity of the sponge, in number of field elements
+	DefaultCapacity = 4
+)
+
+// digest represents the partial evaluation of the checksum
+// along with the params of the sponge
+type digest struct {
+	perm      *Permutation
+	capacity  int
+	data      []goldilocks.Element // data to hash
+	byteOrder goldilocks.ByteOrder
+}
+
+// NewPoseidon2 returns a poseidon2 sponge hash function, with rate Width-Capacity
+// field elements. The digest consists of Capacity field elements.
+//
+// By default the permutation is instantiated with DefaultWidth, DefaultNbFullRounds,
+// DefaultNbPartialRounds and the capacity is DefaultCapacity.
+func NewPoseidon2(opts ...Option) hash.Hash {
+	cfg := poseidon2Options(opts...)
+	if cfg.capacity < 1 || cfg.capacity >= cfg.width {
+		panic("poseidon2: capacity must be in [1, width)")
+	}
+	d := &digest{
+		perm:      NewPermutation(cfg.width, cfg.nbFullRounds, cfg.nbPartialRounds),
+		capacity:  cfg.capacity,
+		byteOrder: cfg.byteOrder,
+	}
+	d.Reset()
+	return d
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = d.data[:0]
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	res := d.checksum()
+	for i := range res {
+		bytes := res[i].Bytes()
+		b = append(b, bytes[:]...)
+	}
+	return b
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return d.capacity * BlockSize
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+//
+// Each []byte block of size BlockSize represents a goldilocks.Element in the
+// configured byte order (big endian by default).
+//
+// If len(p) is not a multiple of BlockSize and any of the []byte in p represent an integer
+// larger than goldilocks.Modulus, this function returns an error.
+//
+// To hash arbitrary data ([]byte not representing canonical field elements) use goldilocks.Hash first
+func (d *digest) Write(p []byte) (int, error) {
+	// we usually expect multiple of block size. But sometimes we hash short
+	// values (FS transcript). Instead of forcing to hash to field, we left-pad the
+	// input here.
+	if len(p) > 0 && len(p) < BlockSize {
+		pp := make([]byte, BlockSize)
+		copy(pp[len(pp)-len(p):], p)
+		p = pp
+	}
+
+	var start int
+	for start = 0; start < len(p); start += BlockSize {
+		if start+BlockSize > len(p) {
+			break
+		}
+		if elem, err := d.byteOrder.Element((*[BlockSize]byte)(p[start : start+BlockSize])); err == nil {
+			d.data = append(d.data, elem)
+		} else {
+			return 0, err
+		}
+	}
+
+	if start != len(p) {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*BlockSize")
+	}
+	return len(p), nil
+}
+
+// WriteElements adds field elements to the running hash.
+func (d *digest) WriteElements(elems ...goldilocks.Element) {
+	d.data = append(d.data, elems...)
+}
+
+// WriteString writes a string that doesn't necessarily consist of field elements
+func (d *digest) WriteString(rawBytes []byte) error {
+	if elems, err := goldilocks.Hash(rawBytes, []byte("string:"), 1); err != nil {
+		return err
+	} else {
+		d.data = append(d.data, elems[0])
+	}
+	return nil
+}
+
+// checksum absorbs the data in the sponge and squeezes Capacity field elements.
+//
+// The input is padded with a single one followed by zeroes to a multiple of the
+// rate, so that messages of different lengths never collide.
+func (d *digest) checksum() []goldilocks.Element {
+	width := d.perm.params.Width
+	rate := width - d.capacity
+
+	state := make([]goldilocks.Element, width)
+	nbBlocks := len(d.data)/rate + 1
+	for i := 0; i < nbBlocks; i++ {
+		for j := 0; j < rate; j++ {
+			k := i*rate + j
+			if k < len(d.data) {
+				state[j].Add(&state[j], &d.data[k])
+			} else if k == len(d.data) {
+				var one goldilocks.Element
+				one.SetOne()
+				state[j].Add(&state[j], &one)
+			}
+		}
+		_ = d.perm.Permutation(state) // cannot fail, len(state) == width
+	}
+
+	res := make([]goldilocks.Element, 0, d.capacity)
+	for {
+		for j := 0; j < rate && len(res) < d.capacity; j++ {
+			res = append(res, state[j])
+		}
+		if len(res) == d.capacity {
+			return res
+		}
+		_ = d.perm.Permutation(state)
+	}
+}
+
+// Sum computes the poseidon2 hash of msg, using the default parameters.
+func Sum(msg []byte) ([]byte, error) {
+	d := NewPoseidon2()
+	if _, err := d.Write(msg); err != nil {
+		return nil, err
+	}
+	return d.Sum(nil), nil
+}
@@ -0,0 +1,256 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"testing"
+
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermutationVector(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+	input := make([]goldilocks.Element, DefaultWidth)
+	for i := range input {
+		input[i].SetUint64(uint64(i))
+	}
+	assert.NoError(h.Permutation(input))
+
+	expected := []string{
+		"4061215986104587093",
+		"14217615544200870153",
+		"14816357679982276216",
+		"17682241354910506500",
+		"9946730819492998593",
+		"15382968124347424380",
+		"9695717876521919558",
+		"1298220333732583884",
+	}
+	for i := range expected {
+		var e goldilocks.Element
+		_, err := e.SetString(expected[i])
+		assert.NoError(err)
+		assert.True(e.Equal(&input[i]), "permutation mismatch at index %d: got %s", i, input[i].String())
+	}
+}
+
+func TestPermutationInputSize(t *testing.T) {
+	h := NewPermutation(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+	err := h.Permutation(make([]goldilocks.Element, DefaultWidth+1))
+	require.ErrorIs(t, err, ErrInvalidSizebuffer)
+}
+
+// denseMatrices returns the external and internal matrices of h as dense matrices
+func denseMatrices(h *Permutation) (ext, inter [][]goldilocks.Element) {
+	width := h.params.Width
+	ext = make([][]goldilocks.Element, width)
+	inter = make([][]goldilocks.Element, width)
+	for j := 0; j < width; j++ {
+		e := make([]goldilocks.Element, width)
+		e[j].SetOne()
+		h.matMulExternalInPlace(e)
+		ext[j] = e // column j
+		e = make([]goldilocks.Element, width)
+		e[j].SetOne()
+		h.matMulInternalInPlace(e)
+		inter[j] = e
+	}
+	return
+}
+
+func TestMatrices(t *testing.T) {
+	assert := require.New(t)
+
+	m4 := [4][4]uint64{
+		{5, 7, 1, 3},
+		{4, 6, 1, 1},
+		{1, 3, 5, 7},
+		{1, 1, 4, 6},
+	}
+
+	for _, width := range []int{2, 3, 4, 8, 12} {
+		h := NewPermutation(width, 2, 2)
+		ext, inter := denseMatrices(h)
+		for i := 0; i < width; i++ {
+			for j := 0; j < width; j++ {
+				var expectedExt, expectedInt goldilocks.Element
+				switch width {
+				case 2, 3:
+					// circ(2,1) and circ(2,1,1)
+					expectedExt.SetUint64(1)
+					if i == j {
+						expectedExt.SetUint64(2)
+					}
+					// 𝟙 + diag(1,..,1,2)
+					expectedInt.SetUint64(1)
+					if i == j {
+						expectedInt.SetUint64(2)
+						if i == width-1 {
+							expectedInt.SetUint64(3)
+						}
+					}
+				default:
+					// circ(2M4,M4,..,M4)
+					expectedExt.SetUint64(m4[i%4][j%4])
+					if i/4 == j/4 {
+						expectedExt.Double(&expectedExt)
+					}
+					// 𝟙 + diag(D)
+					expectedInt.SetOne()
+					if i == j {
+						expectedInt.Add(&expectedInt, &h.params.diagInternalMatrix[i])
+					}
+				}
+				assert.True(expectedExt.Equal(&ext[j][i]), "external matrix, width %d, entry (%d,%d)", width, i, j)
+				assert.True(expectedInt.Equal(&inter[j][i]), "internal matrix, width %d, entry (%d,%d)", width, i, j)
+			}
+		}
+	}
+}
+
+func TestSponge(t *testing.T) {
+	assert := require.New(t)
+
+	rate := DefaultWidth - DefaultCapacity
+	data := make([]goldilocks.Element, 2*rate+1)
+	for i := range data {
+		data[i].SetRandom()
+	}
+
+	h := NewPoseidon2()
+	for i := range data {
+		b := data[i].Bytes()
+		_, err := h.Write(b[:])
+		assert.NoError(err)
+	}
+	sum := h.Sum(nil)
+	assert.Equal(h.Size(), len(sum))
+
+	// Sum should not change the state of the hash
+	assert.Equal(sum, h.Sum(nil))
+
+	// sponge computed by hand: data||1||0..0 absorbed block per block
+	perm := NewPermutation(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+	padded := make([]goldilocks.Element, 3*rate)
+	copy(padded, data)
+	padded[len(data)].SetOne()
+	state := make([]goldilocks.Element, DefaultWidth)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < rate; j++ {
+			state[j].Add(&state[j], &padded[i*rate+j])
+		}
+		assert.NoError(perm.Permutation(state))
+	}
+	var expected []byte
+	for i := 0; i < DefaultCapacity; i++ {
+		b := state[i].Bytes()
+		expected = append(expected, b[:]...)
+	}
+	assert.Equal(expected, sum)
+
+	// WriteElements is equivalent to Write
+	h2 := NewPoseidon2()
+	h2.(*digest).WriteElements(data...)
+	assert.Equal(sum, h2.Sum(nil))
+
+	// Reset
+	h.Reset()
+	assert.Equal(NewPoseidon2().Sum(nil), h.Sum(nil))
+
+	// padding makes the empty message and the zero message differ
+	_, err := h.Write(make([]byte, BlockSize))
+	assert.NoError(err)
+	assert.NotEqual(NewPoseidon2().Sum(nil), h.Sum(nil))
+
+	// Sum is NewPoseidon2 + Write + Sum
+	var msg []byte
+	for i := range data {
+		b := data[i].Bytes()
+		msg = append(msg, b[:]...)
+	}
+	res, err := Sum(msg)
+	assert.NoError(err)
+	assert.Equal(sum, res)
+}
+
+func TestWriteInvalidInput(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPoseidon2()
+	// not a multiple of BlockSize
+	_, err := h.Write(make([]byte, BlockSize+1))
+	assert.Error(err)
+
+	// not canonical
+	buf := make([]byte, BlockSize)
+	for i := range buf {
+		buf[i] = 0xFF
+	}
+	_, err = h.Write(buf)
+	assert.Error(err)
+
+	// ... but valid in little endian when the most significant byte is 0
+	buf[BlockSize-1] = 0
+	h = NewPoseidon2(WithByteOrder(goldilocks.LittleEndian))
+	_, err = h.Write(buf)
+	assert.NoError(err)
+}
+
+func TestOptions(t *testing.T) {
+	assert := require.New(t)
+
+	for _, width := range []int{2, 4, 8} {
+		for _, capacity := range []int{1, width / 2} {
+			h := NewPoseidon2(WithPermutation(width, DefaultNbFullRounds, DefaultNbPartialRounds), WithCapacity(capacity))
+			var e goldilocks.Element
+			e.SetUint64(42)
+			b := e.Bytes()
+			_, err := h.Write(b[:])
+			assert.NoError(err)
+			assert.Equal(capacity*BlockSize, len(h.Sum(nil)))
+		}
+	}
+
+	assert.Panics(func() { NewPoseidon2(WithCapacity(DefaultWidth)) })
+	assert.Panics(func() { NewPermutation(5, DefaultNbFullRounds, DefaultNbPartialRounds) })
+	assert.Panics(func() { NewPermutation(DefaultWidth, DefaultNbFullRounds+1, DefaultNbPartialRounds) })
+}
+
+func TestFiatShamir(t *testing.T) {
+	fs := fiatshamir.NewTranscript(NewPoseidon2(), "c0")
+	zero := make([]byte, BlockSize)
+	err := fs.Bind("c0", zero)
+	require.NoError(t, err)
+	_, err = fs.ComputeChallenge("c0")
+	require.NoError(t, err)
+}
+
+func BenchmarkPoseidon2(b *testing.B) {
+	h := NewPermutation(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+	var tmp [DefaultWidth]goldilocks.Element
+	for i := range tmp {
+		tmp[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Permutation(tmp[:])
+	}
+}
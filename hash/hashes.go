@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package hash provides MiMC hash function defined over curves implemented in gnark-crypto/ecc.
+// Package hash provides MiMC and Poseidon2 hash functions defined over fields implemented in gnark-crypto.
 //
 // Originally developed and used in a ZKP context.
 package hash
@@ -29,6 +29,12 @@ import (
 	bw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/mimc"
 	bw756 "github.com/consensys/gnark-crypto/ecc/bw6-756/fr/mimc"
 	bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
+
+	poseidon2_bls377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/poseidon2"
+	poseidon2_bls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+	poseidon2_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	poseidon2_bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/poseidon2"
+	poseidon2_goldilocks "github.com/consensys/gnark-crypto/field/goldilocks/poseidon2"
 )
 
 type Hash uint
@@ -43,6 +49,11 @@ const (
 	MIMC_BLS24_317
 	MIMC_BW6_633
 	MIMC_BW6_756
+	POSEIDON2_BN254
+	POSEIDON2_BLS12_381
+	POSEIDON2_BLS12_377
+	POSEIDON2_BW6_761
+	POSEIDON2_GOLDILOCKS
 )
 
 // size of digests in bytes
@@ -56,9 +67,15 @@ var digestSize = []uint8{
 	MIMC_BLS24_317: 48,
 	MIMC_BW6_633:   80,
 	MIMC_BW6_756:   96,
+
+	POSEIDON2_BN254:      32,
+	POSEIDON2_BLS12_381:  32,
+	POSEIDON2_BLS12_377:  32,
+	POSEIDON2_BW6_761:    48,
+	POSEIDON2_GOLDILOCKS: 32,
 }
 
-// New creates the corresponding mimc hash function.
+// New creates the corresponding hash function, with default parameters.
 func (m Hash) New() hash.Hash {
 	switch m {
 	case MIMC_BN254:
@@ -79,12 +96,22 @@ func (m Hash) New() hash.Hash {
 		return bw633.NewMiMC()
 	case MIMC_BW6_756:
 		return bw756.NewMiMC()
+	case POSEIDON2_BN254:
+		return poseidon2_bn254.NewPoseidon2()
+	case POSEIDON2_BLS12_381:
+		return poseidon2_bls381.NewPoseidon2()
+	case POSEIDON2_BLS12_377:
+		return poseidon2_bls377.NewPoseidon2()
+	case POSEIDON2_BW6_761:
+		return poseidon2_bw761.NewPoseidon2()
+	case POSEIDON2_GOLDILOCKS:
+		return poseidon2_goldilocks.NewPoseidon2()
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
-// String returns the mimc ID to string format.
+// String returns the hash ID to string format.
 func (m Hash) String() string {
 	switch m {
 	case MIMC_BN254:
@@ -105,8 +132,18 @@ func (m Hash) String() string {
 		return "MIMC_BW633"
 	case MIMC_BW6_756:
 		return "MIMC_BW756"
+	case POSEIDON2_BN254:
+		return "POSEIDON2_BN254"
+	case POSEIDON2_BLS12_381:
+		return "POSEIDON2_BLS381"
+	case POSEIDON2_BLS12_377:
+		return "POSEIDON2_BLS377"
+	case POSEIDON2_BW6_761:
+		return "POSEIDON2_BW761"
+	case POSEIDON2_GOLDILOCKS:
+		return "POSEIDON2_GOLDILOCKS"
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
@@ -0,0 +1,86 @@
+// Package dudect implements a leakage detection test in the spirit of dudect
+// (Reparaz, Balasch, Verbauwhede, "Dude, is my code constant time?", 2016).
+//
+// The execution time of an operation is measured on inputs from two classes
+// (typically a fixed input and random inputs) picked at random, and Welch's
+// t-test is used to decide whether the two timing distributions differ.
+package dudect
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Threshold above which |t| is considered to be evidence of a timing leak.
+const Threshold = 10
+
+// Run performs n measurements and returns Welch's t statistic.
+//
+// Before the i-th measurement, prepare(i, class) is called with a random class
+// in {0, 1} so that the caller can set up the input; measure(i) is the timed
+// operation. The slowest measurements (above the given percentile, in ]0, 1])
+// are discarded, as they are mostly caused by interrupts and scheduling.
+func Run(n int, percentile float64, prepare func(i, class int), measure func(i int)) float64 {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano())) //#nosec G404 weak rng is fine here
+
+	classes := make([]int, n)
+	durations := make([]float64, n)
+	for i := 0; i < n; i++ {
+		classes[i] = rnd.Intn(2)
+		prepare(i, classes[i])
+	}
+	for i := 0; i < n; i++ {
+		start := time.Now()
+		measure(i)
+		durations[i] = float64(time.Since(start))
+	}
+
+	cutoff := math.Inf(1)
+	if percentile > 0 && percentile < 1 {
+		sorted := make([]float64, n)
+		copy(sorted, durations)
+		sort.Float64s(sorted)
+		cutoff = sorted[int(percentile*float64(n-1))]
+	}
+
+	var ctx [2]welford
+	for i := 0; i < n; i++ {
+		if durations[i] <= cutoff {
+			ctx[classes[i]].push(durations[i])
+		}
+	}
+	return tStatistic(&ctx[0], &ctx[1])
+}
+
+// welford accumulates the mean and variance of a sample online.
+type welford struct {
+	n, mean, m2 float64
+}
+
+func (w *welford) push(x float64) {
+	w.n++
+	delta := x - w.mean
+	w.mean += delta / w.n
+	w.m2 += delta * (x - w.mean)
+}
+
+func (w *welford) variance() float64 {
+	if w.n < 2 {
+		return 0
+	}
+	return w.m2 / (w.n - 1)
+}
+
+// tStatistic returns Welch's t statistic of the two samples.
+func tStatistic(a, b *welford) float64 {
+	if a.n < 2 || b.n < 2 {
+		return 0
+	}
+	den := math.Sqrt(a.variance()/a.n + b.variance()/b.n)
+	if den == 0 {
+		return 0
+	}
+	return (a.mean - b.mean) / den
+}
@@ -0,0 +1,32 @@
+package dudect
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWelford(t *testing.T) {
+	var w welford
+	for _, x := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		w.push(x)
+	}
+	if w.mean != 5 || math.Abs(w.variance()-32.0/7) > 1e-12 {
+		t.Fatalf("wrong mean or variance: %v %v", w.mean, w.variance())
+	}
+}
+
+func TestRunDetectsLeak(t *testing.T) {
+	const n = 2000
+	delays := make([]time.Duration, n)
+	tStat := Run(n, 0.9, func(i, class int) {
+		delays[i] = time.Duration(class) * 50 * time.Microsecond
+	}, func(i int) {
+		deadline := time.Now().Add(delays[i])
+		for time.Now().Before(deadline) {
+		}
+	})
+	if math.Abs(tStat) < Threshold {
+		t.Fatalf("leak not detected, t = %f", tStat)
+	}
+}
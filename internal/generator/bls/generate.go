@@ -0,0 +1,45 @@
+package bls
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+type blsConfig struct {
+	config.Curve
+	SuiteID string // curve identifier in the ciphersuite ID, e.g. BLS12381
+	MapG1   string // map to curve used by hash_to_g1 (SSWU or SVDW)
+	MapG2   string // map to curve used by hash_to_g2 (SSWU or SVDW)
+}
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	conf.Package = "bls"
+	baseDir = filepath.Join(baseDir, conf.Package)
+
+	blsConf := blsConfig{
+		Curve:   conf,
+		SuiteID: strings.ToUpper(strings.ReplaceAll(conf.Name, "-", "")),
+		MapG1:   mapName(conf.HashE1),
+		MapG2:   mapName(conf.HashE2),
+	}
+
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "bls.go"), Templates: []string{"bls.go.tmpl"}},
+		{File: filepath.Join(baseDir, "aggregate.go"), Templates: []string{"aggregate.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "bls_test.go"), Templates: []string{"bls.test.go.tmpl"}},
+	}
+	return bgen.Generate(blsConf, conf.Package, "./bls/template", entries...)
+}
+
+func mapName(suite config.HashSuite) string {
+	if _, ok := suite.(*config.HashSuiteSswu); ok {
+		return string(config.SSWU)
+	}
+	// hash to curve falls back to SVDW when no suite is specified
+	return string(config.SVDW)
+}
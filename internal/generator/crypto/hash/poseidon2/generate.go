@@ -0,0 +1,121 @@
+package poseidon2
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// Config describes a field for which we generate a poseidon2 package, along
+// with the default parameters of the permutation over that field.
+type Config struct {
+	config.FieldDependency
+	Name            string // name of the field, used to derive the constants
+	SBoxDegree      int    // degree d of the s-box x -> xᵈ, must satisfy gcd(d, p-1) = 1
+	Width           int    // default size of the state
+	Capacity        int    // default capacity of the sponge
+	NbFullRounds    int    // default number of full rounds
+	NbPartialRounds int    // default number of partial rounds
+
+	// TestVector is the image of (0, 1, .., Width-1) by the default permutation
+	TestVector []string
+
+	// ReferenceVector is the image of (0, 1, .., Width-1) by the permutation of
+	// the reference implementation, whose round keys are generated with the
+	// Grain LFSR of the Poseidon paper. It is only set for Width = 3, where the
+	// matrices of the reference implementation are the ones of this package.
+	// https://github.com/HorizenLabs/poseidon2
+	ReferenceVector []string
+}
+
+func Generate(conf Config, baseDir string, bgen *bavard.BatchGenerator) error {
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "poseidon2.go"), Templates: []string{"poseidon2.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash.go"), Templates: []string{"hash.go.tmpl"}},
+		{File: filepath.Join(baseDir, "options.go"), Templates: []string{"options.go.tmpl"}},
+		{File: filepath.Join(baseDir, "poseidon2_test.go"), Templates: []string{"poseidon2.test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, "poseidon2", "./crypto/hash/poseidon2/template", entries...)
+}
+
+// Curves returns the poseidon2 configurations of the scalar fields for which we
+// generate a poseidon2 package.
+func Curves() map[string]Config {
+	return map[string]Config{
+		"bn254": withReferenceVector(newCurveConfig("bn254", 5, 56, []string{
+			"3701663887762035013657091747110608072969084366482084060799620941471629433078",
+			"736443460513651650627504651688950568487642517630078134397186660306873351035",
+			"262168601976394294132077379072920786683592360477942740758557601455219165751",
+		}), []string{
+			"0x0bb61d24daca55eebcb1929a82650f328134334da98ea4f847f760054f4a3033",
+			"0x303b6f7c86d043bfcbcc80214f26a30277a15d3f74ca654992defe7ff8d03570",
+			"0x1ed25194542b12eef8617361c3ba7c52e660b145994427cc86296242cf766ec8",
+		}),
+		"bls12-381": newCurveConfig("bls12-381", 5, 56, []string{
+			"23283732358538078405672310861876954276630525356881775049202951753065529026612",
+			"4251163699798559160047624486324255820792915239635228846013432430203280235676",
+			"34188424187604221969196342302596172660571210252479958641491897845952958385617",
+		}),
+		"bls12-377": newCurveConfig("bls12-377", 17, 32, []string{
+			"2240445260247052625883007917279259926449323855933284186076369659821340172093",
+			"5103646462241184818148963018595661875715640377165377855176854471174527275753",
+			"3791262271529590619067187128029401601477340734044959890160039895147928359996",
+		}),
+		"bw6-761": newCurveConfig("bw6-761", 5, 56, []string{
+			"177217750822928835728355059121001853405582338440388507837675989017338526359452746909165379677637107457661666469037",
+			"14888149411269307147678814373000198909884356933848348398871646398835813004810259383370366651428116593562782806270",
+			"186959458730079002073001911396906417050594075539614954834892827770298945168470467430500323203822710482710533354660",
+		}),
+	}
+}
+
+// Goldilocks returns the poseidon2 configuration for the goldilocks field.
+func Goldilocks() Config {
+	return Config{
+		FieldDependency: config.FieldDependency{
+			FieldPackagePath: "github.com/consensys/gnark-crypto/field/goldilocks",
+			FieldPackageName: "goldilocks",
+			ElementType:      "goldilocks.Element",
+		},
+		Name:            "goldilocks",
+		SBoxDegree:      7,
+		Width:           8,
+		Capacity:        4,
+		NbFullRounds:    8,
+		NbPartialRounds: 22,
+		TestVector: []string{
+			"4061215986104587093",
+			"14217615544200870153",
+			"14816357679982276216",
+			"17682241354910506500",
+			"9946730819492998593",
+			"15382968124347424380",
+			"9695717876521919558",
+			"1298220333732583884",
+		},
+	}
+}
+
+func newCurveConfig(name string, sBoxDegree, nbPartialRounds int, testVector []string) Config {
+	return Config{
+		FieldDependency: config.FieldDependency{
+			FieldPackagePath: "github.com/consensys/gnark-crypto/ecc/" + name + "/fr",
+			FieldPackageName: "fr",
+			ElementType:      "fr.Element",
+		},
+		Name:            name,
+		SBoxDegree:      sBoxDegree,
+		Width:           3,
+		Capacity:        1,
+		NbFullRounds:    8,
+		NbPartialRounds: nbPartialRounds,
+		TestVector:      testVector,
+	}
+}
+
+func withReferenceVector(conf Config, referenceVector []string) Config {
+	conf.ReferenceVector = referenceVector
+	return conf
+}
@@ -182,6 +182,10 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	entries = []bavard.Entry{
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"tests/marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_stream.go"), Templates: []string{"multiexp_stream.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_stream_test.go"), Templates: []string{"tests/multiexp_stream.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_precomputed.go"), Templates: []string{"multiexp_precomputed.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_precomputed_test.go"), Templates: []string{"tests/multiexp_precomputed.go.tmpl"}},
 	}
 
 	marshal := []func(*bavard.Bavard) error{bavard.Funcs(funcs)}
@@ -18,6 +18,16 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "ecdsa_test.go"), Templates: []string{"ecdsa.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"marshal.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "rfc6979.go"), Templates: []string{"rfc6979.go.tmpl"}},
+		{File: filepath.Join(baseDir, "rfc6979_test.go"), Templates: []string{"rfc6979.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "batch.go"), Templates: []string{"batch.go.tmpl"}},
+		{File: filepath.Join(baseDir, "batch_test.go"), Templates: []string{"batch.test.go.tmpl"}},
+	}
+	if conf.Equal(config.SECP256K1) || conf.Equal(config.STARK_CURVE) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ecies.go"), Templates: []string{"ecies.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ecies_test.go"), Templates: []string{"ecies.test.go.tmpl"}},
+		)
 	}
 	return bgen.Generate(conf, conf.Package, "./ecdsa/template", entries...)
 
@@ -17,6 +17,12 @@ func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.Batc
 		{File: filepath.Join(baseDir, "eddsa.go"), Templates: []string{"eddsa.go.tmpl"}},
 		{File: filepath.Join(baseDir, "eddsa_test.go"), Templates: []string{"eddsa.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "frost.go"), Templates: []string{"frost.go.tmpl"}},
+		{File: filepath.Join(baseDir, "frost_test.go"), Templates: []string{"frost.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "batch.go"), Templates: []string{"batch.go.tmpl"}},
+		{File: filepath.Join(baseDir, "batch_test.go"), Templates: []string{"batch.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ecies.go"), Templates: []string{"ecies.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ecies_test.go"), Templates: []string{"ecies.test.go.tmpl"}},
 	}
 	return bgen.Generate(conf, conf.Package, "./edwards/eddsa/template", entries...)
 
@@ -0,0 +1,31 @@
+package ipa
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// ipaConfig adds to the curve configuration the name of the package of the
+// curve, since Package is overwritten with the package being generated.
+type ipaConfig struct {
+	config.TwistedEdwardsCurve
+	CurvePackage string
+}
+
+func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.BatchGenerator) error {
+	// inner product argument commitment scheme
+	data := ipaConfig{TwistedEdwardsCurve: conf, CurvePackage: conf.Package}
+	data.Package = "ipa"
+	baseDir = filepath.Join(baseDir, data.Package)
+
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ipa.go"), Templates: []string{"ipa.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ipa_test.go"), Templates: []string{"ipa.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+	}
+	return bgen.Generate(data, data.Package, "./edwards/ipa/template", entries...)
+
+}
@@ -8,7 +8,14 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 )
 
-func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+// Config describes the field for which we generate an fft package.
+type Config struct {
+	config.FieldDependency
+	Name    string // name of the curve or field, used to select the domain constants
+	Package string
+}
+
+func Generate(conf Config, baseDir string, bgen *bavard.BatchGenerator) error {
 
 	conf.Package = "fft"
 
@@ -23,6 +30,14 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		{File: filepath.Join(baseDir, "options.go"), Templates: []string{"options.go.tmpl", "imports.go.tmpl"}},
 	}
 
+	// FFT over the elliptic curve groups
+	if conf.Name != "goldilocks" {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "fft_group.go"), Templates: []string{"fft.group.go.tmpl", "imports.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "fft_group_test.go"), Templates: []string{"tests/fft.group.go.tmpl", "imports.go.tmpl"}},
+		)
+	}
+
 	funcs := make(map[string]interface{})
 	funcs["bitReverse"] = func(n, i int64) uint64 {
 		nn := uint64(64 - bits.TrailingZeros64(uint64(n)))
@@ -54,12 +69,12 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		return err
 	}
 
-	// put the generator in the parent dir (fr)
+	// put the generator in the parent dir (field package)
 	frDir := filepath.Dir(baseDir)
 	entries = []bavard.Entry{
 		{File: filepath.Join(frDir, "generator.go"), Templates: []string{"fr.generator.go.tmpl"}},
 	}
-	return bgen.GenerateWithOptions(conf, "fr", "./fft/template/", bavardOpts, entries...)
+	return bgen.GenerateWithOptions(conf, conf.FieldPackageName, "./fft/template/", bavardOpts, entries...)
 }
 
 func anyToUint64(x any) uint64 {
@@ -0,0 +1,24 @@
+package ipa
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	// inner product argument commitment scheme
+	conf.Package = "ipa"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ipa.go"), Templates: []string{"ipa.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ipa_test.go"), Templates: []string{"ipa.test.go.tmpl"}},
+	}
+	if !conf.Equal(config.SECP256K1) {
+		entries = append(entries, bavard.Entry{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}})
+	}
+	return bgen.Generate(conf, conf.Package, "./ipa/template/", entries...)
+
+}
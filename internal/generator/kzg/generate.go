@@ -12,11 +12,29 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	// kzg commitment scheme
 	conf.Package = "kzg"
 	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "ceremony.go"), Templates: []string{"ceremony.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ceremony_test.go"), Templates: []string{"ceremony.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
 		{File: filepath.Join(baseDir, "kzg.go"), Templates: []string{"kzg.go.tmpl"}},
 		{File: filepath.Join(baseDir, "kzg_test.go"), Templates: []string{"kzg.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "shplonk.go"), Templates: []string{"shplonk.go.tmpl"}},
+		{File: filepath.Join(baseDir, "shplonk_test.go"), Templates: []string{"shplonk.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "utils.go"), Templates: []string{"utils.go.tmpl"}},
+		{File: filepath.Join(baseDir, "zeromorph.go"), Templates: []string{"zeromorph.go.tmpl"}},
+		{File: filepath.Join(baseDir, "zeromorph_test.go"), Templates: []string{"zeromorph.test.go.tmpl"}},
+	}
+	if conf.Equal(config.BLS12_381) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ceremony_ethereum.go"), Templates: []string{"ceremony_ethereum.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ceremony_ethereum_test.go"), Templates: []string{"ceremony_ethereum.test.go.tmpl"}},
+		)
+	}
+	if conf.Equal(config.BN254) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ceremony_ppot.go"), Templates: []string{"ceremony_ppot.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ceremony_ppot_test.go"), Templates: []string{"ceremony_ppot.test.go.tmpl"}},
+		)
 	}
 	return bgen.Generate(conf, conf.Package, "./kzg/template/", entries...)
 
@@ -0,0 +1,21 @@
+package logup
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	// logUp lookup argument
+	conf.Package = "logup"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "logup.go"), Templates: []string{"logup.go.tmpl"}},
+		{File: filepath.Join(baseDir, "logup_test.go"), Templates: []string{"logup.test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./logup/template/", entries...)
+
+}
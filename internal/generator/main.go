@@ -10,23 +10,29 @@ import (
 	"github.com/consensys/bavard"
 	"github.com/consensys/gnark-crypto/field/generator"
 	field "github.com/consensys/gnark-crypto/field/generator/config"
+	"github.com/consensys/gnark-crypto/internal/generator/bls"
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/mimc"
+	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/poseidon2"
 	"github.com/consensys/gnark-crypto/internal/generator/ecc"
 	"github.com/consensys/gnark-crypto/internal/generator/ecdsa"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards/eddsa"
+	edwardsipa "github.com/consensys/gnark-crypto/internal/generator/edwards/ipa"
 	"github.com/consensys/gnark-crypto/internal/generator/fft"
 	fri "github.com/consensys/gnark-crypto/internal/generator/fri/template"
 	"github.com/consensys/gnark-crypto/internal/generator/gkr"
 	"github.com/consensys/gnark-crypto/internal/generator/hash_to_field"
 	"github.com/consensys/gnark-crypto/internal/generator/iop"
+	"github.com/consensys/gnark-crypto/internal/generator/ipa"
 	"github.com/consensys/gnark-crypto/internal/generator/kzg"
+	"github.com/consensys/gnark-crypto/internal/generator/logup"
 	"github.com/consensys/gnark-crypto/internal/generator/pairing"
 	"github.com/consensys/gnark-crypto/internal/generator/pedersen"
 	"github.com/consensys/gnark-crypto/internal/generator/permutation"
 	"github.com/consensys/gnark-crypto/internal/generator/plookup"
 	"github.com/consensys/gnark-crypto/internal/generator/polynomial"
+	"github.com/consensys/gnark-crypto/internal/generator/secretsharing"
 	"github.com/consensys/gnark-crypto/internal/generator/sis"
 	"github.com/consensys/gnark-crypto/internal/generator/sumcheck"
 	"github.com/consensys/gnark-crypto/internal/generator/test_vector_utils"
@@ -76,6 +82,9 @@ func main() {
 			// generate G1, G2, multiExp, ...
 			assertNoError(ecc.Generate(conf, curveDir, bgen))
 
+			// generate ipa commitment on G1
+			assertNoError(ipa.Generate(conf, filepath.Join(curveDir, "ipa"), bgen))
+
 			if conf.Equal(config.SECP256K1) {
 				return
 			}
@@ -86,11 +95,20 @@ func main() {
 			// generate pairing tests
 			assertNoError(pairing.Generate(conf, curveDir, bgen))
 
+			// generate bls signatures
+			assertNoError(bls.Generate(conf, curveDir, bgen))
+
 			// generate fri on fr
 			assertNoError(fri.Generate(conf, filepath.Join(curveDir, "fr", "fri"), bgen))
 
+			frInfo := config.FieldDependency{
+				FieldPackagePath: "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/fr",
+				FieldPackageName: "fr",
+				ElementType:      "fr.Element",
+			}
+
 			// generate fft on fr
-			assertNoError(fft.Generate(conf, filepath.Join(curveDir, "fr", "fft"), bgen))
+			assertNoError(fft.Generate(fft.Config{FieldDependency: frInfo, Name: conf.Name}, filepath.Join(curveDir, "fr", "fft"), bgen))
 
 			if conf.Equal(config.BN254) || conf.Equal(config.BLS12_377) {
 				assertNoError(sis.Generate(conf, filepath.Join(curveDir, "fr", "sis"), bgen))
@@ -102,19 +120,24 @@ func main() {
 			// generate pedersen on fr
 			assertNoError(pedersen.Generate(conf, filepath.Join(curveDir, "fr", "pedersen"), bgen))
 
+			// generate secret sharing and DKG on fr
+			assertNoError(secretsharing.Generate(conf, filepath.Join(curveDir, "fr", "secretsharing"), bgen))
+
 			// generate plookup on fr
 			assertNoError(plookup.Generate(conf, filepath.Join(curveDir, "fr", "plookup"), bgen))
 
+			// generate logup on fr
+			assertNoError(logup.Generate(conf, filepath.Join(curveDir, "fr", "logup"), bgen))
+
 			// generate permutation on fr
 			assertNoError(permutation.Generate(conf, filepath.Join(curveDir, "fr", "permutation"), bgen))
 
 			// generate mimc on fr
 			assertNoError(mimc.Generate(conf, filepath.Join(curveDir, "fr", "mimc"), bgen))
 
-			frInfo := config.FieldDependency{
-				FieldPackagePath: "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/fr",
-				FieldPackageName: "fr",
-				ElementType:      "fr.Element",
+			// generate poseidon2 on fr
+			if p2conf, ok := poseidon2.Curves()[conf.Name]; ok {
+				assertNoError(poseidon2.Generate(p2conf, filepath.Join(curveDir, "fr", "poseidon2"), bgen))
 			}
 
 			// generate polynomial on fr
@@ -168,6 +191,9 @@ func main() {
 
 			// generate eddsa on companion curves
 			assertNoError(eddsa.Generate(conf, curveDir, bgen))
+
+			// generate ipa commitment on companion curves
+			assertNoError(edwardsipa.Generate(conf, curveDir, bgen))
 		}(conf)
 
 	}
@@ -177,6 +203,23 @@ func main() {
 		defer wg.Done()
 		assertNoError(test_vector_utils.GenerateRationals(bgen))
 	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// generate poseidon2 on goldilocks
+		assertNoError(poseidon2.Generate(poseidon2.Goldilocks(), filepath.Join(baseDir, "field", "goldilocks", "poseidon2"), bgen))
+
+		// generate fft on goldilocks
+		assertNoError(fft.Generate(fft.Config{
+			FieldDependency: config.FieldDependency{
+				FieldPackagePath: "github.com/consensys/gnark-crypto/field/goldilocks",
+				FieldPackageName: "goldilocks",
+				ElementType:      "goldilocks.Element",
+			},
+			Name: "goldilocks",
+		}, filepath.Join(baseDir, "field", "goldilocks", "fft"), bgen))
+	}()
 	wg.Wait()
 
 	// format the whole directory
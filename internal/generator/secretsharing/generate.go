@@ -0,0 +1,24 @@
+package secretsharing
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	// Shamir secret sharing, verifiable secret sharing and distributed key generation
+	conf.Package = "secretsharing"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "secretsharing.go"), Templates: []string{"secretsharing.go.tmpl"}},
+		{File: filepath.Join(baseDir, "vss.go"), Templates: []string{"vss.go.tmpl"}},
+		{File: filepath.Join(baseDir, "dkg.go"), Templates: []string{"dkg.go.tmpl"}},
+		{File: filepath.Join(baseDir, "secretsharing_test.go"), Templates: []string{"secretsharing.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "dkg_test.go"), Templates: []string{"dkg.test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./secretsharing/template/", entries...)
+
+}